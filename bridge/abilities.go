@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// attrMovement is the movement speed attribute sent in UpdateAttributes. It is 0.1
+// when walking without effects.
+const attrMovement = "minecraft:movement"
+
+// Speed effect settings for set_movement_speed.
+const (
+	maxSpeedLevel           = 5
+	defaultSpeedSeconds     = 600
+	maxSpeedSeconds         = 1000000 // longest duration /effect accepts
+	movementConfirmTimeout  = 2 * time.Second
+	movementConfirmInterval = 100 * time.Millisecond
+)
+
+// MovementSpeed reports the player's speeds. Movement comes from the movement attribute
+// and includes effects; the walk and fly speeds are the defaults from the base ability
+// layer and are zero until the server sends UpdateAbilities.
+type MovementSpeed struct {
+	Movement         float32 `json:"movement"`
+	WalkSpeed        float32 `json:"walk_speed"`
+	FlySpeed         float32 `json:"fly_speed"`
+	VerticalFlySpeed float32 `json:"vertical_fly_speed"`
+}
+
+// SetAbilities records the abilities from UpdateAbilities. Abilities of other players
+// are ignored; a zero unique ID is accepted since some servers leave it unset.
+func (gs *GameState) SetAbilities(data protocol.AbilityData) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if data.EntityUniqueID != 0 && data.EntityUniqueID != gs.session.EntityUniqueID {
+		return
+	}
+	gs.abilities = data
+	gs.abilitiesKnown = true
+	gs.movementAbilities = Abilities{
+		Known:        true,
+		MayFly:       abilityValue(data.Layers, protocol.AbilityMayFly),
+		Flying:       abilityValue(data.Layers, protocol.AbilityFlying),
+		NoClip:       abilityValue(data.Layers, protocol.AbilityNoClip),
+		Invulnerable: abilityValue(data.Layers, protocol.AbilityInvulnerable),
+		InstantBuild: abilityValue(data.Layers, protocol.AbilityInstantBuild),
+	}
+}
+
+// Abilities reports what the player can do when moving. Known is false until the
+// server sends the player's abilities.
+type Abilities struct {
+	Known        bool `json:"known"`
+	MayFly       bool `json:"may_fly"`
+	Flying       bool `json:"flying"`
+	NoClip       bool `json:"no_clip"`
+	Invulnerable bool `json:"invulnerable"`
+	InstantBuild bool `json:"instant_build"`
+}
+
+// abilityValue resolves one ability from the layers of UpdateAbilities: the base
+// layer's value, overridden by any other layer (spectator, commands, ...) that sets
+// the ability.
+func abilityValue(layers []protocol.AbilityLayer, ability uint32) bool {
+	var value bool
+	for _, layer := range layers {
+		if layer.Type == protocol.AbilityLayerTypeBase {
+			value = layer.Values&ability != 0
+		}
+	}
+	for _, layer := range layers {
+		if layer.Type != protocol.AbilityLayerTypeBase && layer.Abilities&ability != 0 {
+			value = layer.Values&ability != 0
+		}
+	}
+	return value
+}
+
+// SetAdventureFlags records the flight flags of the legacy AdventureSettings packet,
+// which older servers send instead of UpdateAbilities.
+func (gs *GameState) SetAdventureFlags(flags uint32, uniqueID int64) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if uniqueID != 0 && uniqueID != gs.session.EntityUniqueID {
+		return
+	}
+	a := &gs.movementAbilities
+	a.Known = true
+	a.MayFly = flags&packet.AdventureFlagAllowFlight != 0
+	a.Flying = flags&packet.AdventureFlagFlying != 0
+	a.NoClip = flags&packet.AdventureFlagNoClip != 0
+}
+
+// SetFlying records the client starting or stopping flight.
+func (gs *GameState) SetFlying(flying bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.movementAbilities.Flying = flying
+}
+
+// Abilities returns the player's movement abilities.
+func (gs *GameState) Abilities() Abilities {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.movementAbilities
+}
+
+// flightInput reports whether a PlayerAuthInput starts or stops flight.
+func flightInput(p *packet.PlayerAuthInput) (flying, changed bool) {
+	has := func(flag int) bool { return p.InputData.Len() > flag && p.InputData.Load(flag) }
+	switch {
+	case has(packet.InputFlagStartFlying):
+		return true, true
+	case has(packet.InputFlagStopFlying):
+		return false, true
+	}
+	return false, false
+}
+
+// MovementSpeed returns the player's current speeds.
+func (gs *GameState) MovementSpeed() MovementSpeed {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	speed := MovementSpeed{Movement: gs.attributes[attrMovement]}
+	for _, layer := range gs.abilities.Layers {
+		if layer.Type == protocol.AbilityLayerTypeBase {
+			speed.WalkSpeed = layer.WalkSpeed
+			speed.FlySpeed = layer.FlySpeed
+			speed.VerticalFlySpeed = layer.VerticalFlySpeed
+		}
+	}
+	return speed
+}
+
+// speedEffectCommands returns the /effect commands that set the player's speed level:
+// positive levels apply Speed, negative levels apply Slowness and zero removes both.
+// Vanilla commands cannot change the fly speed, only the walking speed.
+func speedEffectCommands(level, seconds int) ([]string, error) {
+	if level < -maxSpeedLevel || level > maxSpeedLevel {
+		return nil, fmt.Errorf("level must be between %d and %d, got %d", -maxSpeedLevel, maxSpeedLevel, level)
+	}
+	if seconds < 1 || seconds > maxSpeedSeconds {
+		return nil, fmt.Errorf("seconds must be between 1 and %d, got %d", maxSpeedSeconds, seconds)
+	}
+	switch {
+	case level > 0:
+		return []string{
+			"effect @s slowness 0",
+			fmt.Sprintf("effect @s speed %d %d true", seconds, level-1),
+		}, nil
+	case level < 0:
+		return []string{
+			"effect @s speed 0",
+			fmt.Sprintf("effect @s slowness %d %d true", seconds, -level-1),
+		}, nil
+	}
+	return []string{"effect @s speed 0", "effect @s slowness 0"}, nil
+}
+
+// setMovementSpeed sends the speed effect commands and waits up to timeout for the
+// movement attribute to change. It reports whether a change was seen; servers that
+// reject the commands (for example because the player is not an operator) send none.
+func setMovementSpeed(ctx context.Context, state *GameState, cmds []string, timeout time.Duration) (bool, error) {
+	before := state.MovementSpeed().Movement
+	for _, cmd := range cmds {
+		if err := sendCommand(state, cmd); err != nil {
+			return false, err
+		}
+	}
+
+	ticker := time.NewTicker(movementConfirmInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for state.MovementSpeed().Movement == before {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-deadline:
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+	return true, nil
+}
+
+// playerPermissionNames and commandPermissionNames name the permission levels in
+// UpdateAbilities.
+var (
+	playerPermissionNames  = []string{"visitor", "member", "operator", "custom"}
+	commandPermissionNames = []string{"any", "game_directors", "admin", "host", "owner", "internal"}
+)
+
+// permissionName returns names[level], or the level as a number if it is unknown.
+func permissionName(names []string, level byte) string {
+	if int(level) < len(names) {
+		return names[level]
+	}
+	return fmt.Sprintf("%d", level)
+}
+
+// Permissions reports what the player is allowed to do, from UpdateAbilities. Known is
+// false until the server sends the player's abilities.
+type Permissions struct {
+	Known             bool   `json:"known"`
+	PlayerPermission  string `json:"player_permission,omitempty"`
+	CommandPermission string `json:"command_permission,omitempty"`
+	CanRunCommands    bool   `json:"can_run_commands"`
+	CanBuild          bool   `json:"can_build"`
+	CanMine           bool   `json:"can_mine"`
+}
+
+// Permissions returns the player's permission levels.
+func (gs *GameState) Permissions() Permissions {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if !gs.abilitiesKnown {
+		return Permissions{}
+	}
+	perms := Permissions{
+		Known:             true,
+		PlayerPermission:  permissionName(playerPermissionNames, gs.abilities.PlayerPermissions),
+		CommandPermission: permissionName(commandPermissionNames, gs.abilities.CommandPermissions),
+		CanRunCommands:    gs.abilities.CommandPermissions >= protocol.CommandPermissionLevelGameDirectors,
+	}
+	for _, layer := range gs.abilities.Layers {
+		if layer.Type == protocol.AbilityLayerTypeBase {
+			perms.CanBuild = layer.Values&protocol.AbilityBuild != 0
+			perms.CanMine = layer.Values&protocol.AbilityMine != 0
+		}
+	}
+	return perms
+}
+
+// The permissions each tool needs. The server silently drops the work of a tool
+// when the player lacks the permission, so such tools get a warning attached.
+var (
+	commandPermissionTools = map[string]bool{
+		"command":            true,
+		"run_commands":       true,
+		"goto":               true,
+		"teleport":           true,
+		"teleport_relative":  true,
+		"set_gamerule":       true,
+		"set_movement_speed": true,
+		"dig_column":         true,
+	}
+	buildPermissionTools = map[string]bool{
+		"place_blocks":    true,
+		"build_from_file": true,
+	}
+	minePermissionTools = map[string]bool{
+		"dig_column": true,
+	}
+	// Tools that need none of the permissions above. Every registered tool is in
+	// at least one of these maps, so a new tool has to be classified.
+	unrestrictedTools = map[string]bool{
+		"cancel_operation":       true,
+		"chat":                   true,
+		"check_block_placeable":  true,
+		"clear_violations":       true,
+		"craft":                  true,
+		"drop_item":              true,
+		"emote":                  true,
+		"export_commands":        true,
+		"find_safe_position":     true,
+		"get_abilities":          true,
+		"get_available_commands": true,
+		"get_bearing":            true,
+		"get_biome":              true,
+		"get_block_entity":       true,
+		"get_chat_history":       true,
+		"get_container_contents": true,
+		"get_entities":           true,
+		"get_events":             true,
+		"get_gamerules":          true,
+		"get_hazards":            true,
+		"get_held_item":          true,
+		"get_inventory":          true,
+		"get_item_registry":      true,
+		"get_movement_speed":     true,
+		"get_operation_progress": true,
+		"get_pending_form":       true,
+		"get_players":            true,
+		"get_position":           true,
+		"get_proxy_info":         true,
+		"get_raw_packet":         true,
+		"get_session_info":       true,
+		"get_snapshot":           true,
+		"get_status":             true,
+		"get_violations":         true,
+		"get_window":             true,
+		"get_world_generation":   true,
+		"get_world_info":         true,
+		"is_loaded":              true,
+		"list_build_presets":     true,
+		"load_area":              true,
+		"move_item":              true,
+		"ping_pack":              true,
+		"save_build_preset":      true,
+		"select_hotbar_slot":     true,
+		"set_anti_idle":          true,
+		"set_packet_log_filter":  true,
+		"set_waypoint":           true,
+		"submit_form":            true,
+		"switch_realm":           true,
+		"toggle_packet_logging":  true,
+		"upload_structure":       true,
+		"wait_for":               true,
+	}
+)
+
+// permissionWarning returns a warning if the player is known to lack the permission
+// the named tool needs, or "" otherwise.
+func permissionWarning(state *GameState, tool string) string {
+	perms := state.Permissions()
+	if !perms.Known {
+		return ""
+	}
+	if commandPermissionTools[tool] && !perms.CanRunCommands {
+		return fmt.Sprintf("warning: the player's command permission is %q; the server will likely ignore commands that need operator permission", perms.CommandPermission)
+	}
+	if buildPermissionTools[tool] && !perms.CanBuild {
+		return fmt.Sprintf("warning: the player (%s) does not have build permission; the server will likely reject block placement", perms.PlayerPermission)
+	}
+	if minePermissionTools[tool] && !perms.CanMine {
+		return fmt.Sprintf("warning: the player (%s) does not have mine permission; the server will likely reject block breaking", perms.PlayerPermission)
+	}
+	return ""
+}
+
+// permissionMiddleware attaches a permission warning to the results of tools the
+// player lacks permission for.
+func permissionMiddleware(state *GameState) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if result != nil {
+				if warning := permissionWarning(state, req.Params.Name); warning != "" {
+					result.Content = append(result.Content, mcp.NewTextContent(warning))
+				}
+			}
+			return result, err
+		}
+	}
+}
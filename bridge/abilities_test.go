@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_MovementSpeed(t *testing.T) {
+	gs := NewGameState()
+	gs.SetIdentity("Steve", "123", 42)
+	gs.InitFromGameData(minecraft.GameData{EntityUniqueID: 7})
+
+	interceptServerPacket(&packet.UpdateAttributes{
+		EntityRuntimeID: 42,
+		Attributes: []protocol.Attribute{
+			{AttributeValue: protocol.AttributeValue{Name: attrMovement, Value: 0.13}},
+		},
+	}, gs)
+	interceptServerPacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
+		EntityUniqueID: 7,
+		Layers: []protocol.AbilityLayer{
+			{Type: protocol.AbilityLayerTypeBase, WalkSpeed: 0.1, FlySpeed: 0.05, VerticalFlySpeed: 1},
+		},
+	}}, gs)
+	// Abilities of other players are ignored.
+	interceptServerPacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
+		EntityUniqueID: 12345,
+		Layers:         []protocol.AbilityLayer{{Type: protocol.AbilityLayerTypeBase, FlySpeed: 0.5}},
+	}}, gs)
+
+	want := MovementSpeed{Movement: 0.13, WalkSpeed: 0.1, FlySpeed: 0.05, VerticalFlySpeed: 1}
+	if got := gs.MovementSpeed(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSpeedEffectCommands(t *testing.T) {
+	tests := []struct {
+		level, seconds int
+		want           []string
+		wantErr        bool
+	}{
+		{2, 60, []string{"effect @s slowness 0", "effect @s speed 60 1 true"}, false},
+		{-1, 600, []string{"effect @s speed 0", "effect @s slowness 600 0 true"}, false},
+		{0, 600, []string{"effect @s speed 0", "effect @s slowness 0"}, false},
+		{6, 600, nil, true},
+		{1, 0, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := speedEffectCommands(tt.level, tt.seconds)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("level %d: expected error %v, got %v", tt.level, tt.wantErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("level %d: expected %v, got %v", tt.level, tt.want, got)
+		}
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	gs := NewGameState()
+	if gs.Permissions().Known {
+		t.Error("expected permissions unknown before UpdateAbilities")
+	}
+	if w := permissionWarning(gs, "command"); w != "" {
+		t.Errorf("expected no warning while permissions are unknown, got %q", w)
+	}
+
+	interceptServerPacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
+		PlayerPermissions:  packet.PermissionLevelMember,
+		CommandPermissions: protocol.CommandPermissionLevelAny,
+		Layers: []protocol.AbilityLayer{
+			{Type: protocol.AbilityLayerTypeBase, Abilities: protocol.AbilityCount - 1, Values: protocol.AbilityBuild | protocol.AbilityMine},
+		},
+	}}, gs)
+	want := Permissions{Known: true, PlayerPermission: "member", CommandPermission: "any", CanBuild: true, CanMine: true}
+	if got := gs.Permissions(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if permissionWarning(gs, "command") == "" {
+		t.Error("expected a warning for commands without operator permission")
+	}
+	if w := permissionWarning(gs, "place_blocks"); w != "" {
+		t.Errorf("expected no warning for building with build permission, got %q", w)
+	}
+
+	interceptServerPacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{
+		PlayerPermissions:  packet.PermissionLevelOperator,
+		CommandPermissions: protocol.CommandPermissionLevelGameDirectors,
+	}}, gs)
+	if w := permissionWarning(gs, "command"); w != "" {
+		t.Errorf("expected no warning for an operator, got %q", w)
+	}
+	if permissionWarning(gs, "place_blocks") == "" || permissionWarning(gs, "build_from_file") == "" {
+		t.Error("expected a warning for building without build permission")
+	}
+	if w := permissionWarning(gs, "dig_column"); !strings.Contains(w, "mine permission") {
+		t.Errorf("expected a warning for digging without mine permission, got %q", w)
+	}
+}
+
+func TestPermissionTools_Classified(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	gs := NewGameState()
+	registerQueryTools(s, gs)
+	registerActionTools(s, gs)
+	resp, ok := s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("expected a tools/list response")
+	}
+	tools := resp.Result.(mcp.ListToolsResult).Tools
+	if len(tools) == 0 {
+		t.Fatal("expected registered tools")
+	}
+
+	registered := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		registered[tool.Name] = true
+		n := len(permissionMapsOf(tool.Name))
+		if n == 0 {
+			t.Errorf("tool %s is not in any permission map", tool.Name)
+		}
+		if unrestrictedTools[tool.Name] && n > 1 {
+			t.Errorf("tool %s is unrestricted but also needs a permission", tool.Name)
+		}
+	}
+	for _, m := range permissionMaps {
+		for name := range m {
+			if !registered[name] {
+				t.Errorf("permission map lists unregistered tool %s", name)
+			}
+		}
+	}
+}
+
+var permissionMaps = []map[string]bool{commandPermissionTools, buildPermissionTools, minePermissionTools, unrestrictedTools}
+
+// permissionMapsOf returns the permission maps listing the named tool.
+func permissionMapsOf(name string) []map[string]bool {
+	var maps []map[string]bool
+	for _, m := range permissionMaps {
+		if m[name] {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
+
+func TestPermissionMiddleware(t *testing.T) {
+	gs := NewGameState()
+	gs.SetAbilities(protocol.AbilityData{CommandPermissions: protocol.CommandPermissionLevelAny})
+	handler := permissionMiddleware(gs)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	tests := []struct {
+		tool string
+		want int
+	}{
+		{"command", 2},
+		{"get_status", 1},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tt.tool
+		result, _ := handler(context.Background(), req)
+		if len(result.Content) != tt.want {
+			t.Errorf("%s: expected %d content items, got %d", tt.tool, tt.want, len(result.Content))
+		}
+	}
+}
+
+func TestIntercept_Abilities(t *testing.T) {
+	gs := NewGameState()
+	if gs.Abilities().Known {
+		t.Fatal("expected abilities to be unknown before UpdateAbilities")
+	}
+
+	interceptServerPacket(&packet.UpdateAbilities{AbilityData: protocol.AbilityData{Layers: []protocol.AbilityLayer{{
+		Type:      protocol.AbilityLayerTypeBase,
+		Abilities: protocol.AbilityCount - 1,
+		Values:    protocol.AbilityMayFly | protocol.AbilityInvulnerable,
+	}}}}, gs)
+	expected := Abilities{Known: true, MayFly: true, Invulnerable: true}
+	if got := gs.Abilities(); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// The client starting and stopping flight
+	input := protocol.NewBitset(packet.PlayerAuthInputBitsetSize)
+	input.Set(packet.InputFlagStartFlying)
+	interceptClientPacket(&packet.PlayerAuthInput{InputData: input}, gs)
+	if !gs.Abilities().Flying {
+		t.Error("expected flying after StartFlying input")
+	}
+	interceptClientPacket(&packet.PlayerAuthInput{}, gs) // no input flags
+	if !gs.Abilities().Flying {
+		t.Error("expected input without flight flags to leave flying alone")
+	}
+	input = protocol.NewBitset(packet.PlayerAuthInputBitsetSize)
+	input.Set(packet.InputFlagStopFlying)
+	interceptClientPacket(&packet.PlayerAuthInput{InputData: input}, gs)
+	if gs.Abilities().Flying {
+		t.Error("expected not flying after StopFlying input")
+	}
+
+	// Legacy servers
+	interceptServerPacket(&packet.AdventureSettings{Flags: packet.AdventureFlagAllowFlight | packet.AdventureFlagFlying | packet.AdventureFlagNoClip}, gs)
+	if a := gs.Abilities(); !a.MayFly || !a.Flying || !a.NoClip || !a.Invulnerable {
+		t.Errorf("expected AdventureSettings flight flags, got %+v", a)
+	}
+}
+
+func TestAbilityValue(t *testing.T) {
+	layers := []protocol.AbilityLayer{
+		{Type: protocol.AbilityLayerTypeBase, Abilities: protocol.AbilityCount - 1, Values: protocol.AbilityNoClip},
+		{Type: protocol.AbilityLayerTypeSpectator, Abilities: protocol.AbilityFlying | protocol.AbilityNoClip, Values: protocol.AbilityFlying},
+	}
+	tests := []struct {
+		ability  uint32
+		expected bool
+	}{
+		{protocol.AbilityFlying, true},  // set by the spectator layer
+		{protocol.AbilityNoClip, false}, // cleared by the spectator layer
+		{protocol.AbilityMayFly, false}, // base layer only
+	}
+	for _, tt := range tests {
+		if got := abilityValue(layers, tt.ability); got != tt.expected {
+			t.Errorf("ability %d: expected %v, got %v", tt.ability, tt.expected, got)
+		}
+	}
+}
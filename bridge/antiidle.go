@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultAntiIdleInterval is how long the agent may be idle before the proxy
+// signals activity on its behalf.
+const DefaultAntiIdleInterval = 4 * time.Minute
+
+// minAntiIdleInterval rate-limits anti-idle actions.
+const minAntiIdleInterval = 10 * time.Second
+
+// antiIdleYawJitter is the head rotation, in degrees, sent for a single tick to
+// signal activity. Rotation is used rather than movement so that the player's
+// position is never disturbed.
+const antiIdleYawJitter = 0.5
+
+// SetAntiIdle enables or disables the anti-idle action and sets its idle interval.
+func (gs *GameState) SetAntiIdle(enabled bool, interval time.Duration) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.antiIdle = enabled
+	gs.antiIdleInterval = interval
+	gs.lastActivity = time.Now()
+}
+
+// AntiIdle returns whether the anti-idle action is enabled and its idle interval.
+func (gs *GameState) AntiIdle() (bool, time.Duration) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.antiIdle, gs.antiIdleInterval
+}
+
+// MarkActivity records agent activity, postponing the next anti-idle action.
+func (gs *GameState) MarkActivity() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.lastActivity = time.Now()
+	gs.lastToolCall = gs.lastActivity
+}
+
+// AntiIdleDue reports whether an anti-idle action should be performed at now.
+// When it returns true the idle timer is restarted, so at most one action is
+// performed per interval.
+func (gs *GameState) AntiIdleDue(now time.Time) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if !gs.antiIdle || now.Sub(gs.lastActivity) < gs.antiIdleInterval {
+		return false
+	}
+	gs.lastActivity = now
+	return true
+}
+
+// activityMiddleware marks every tool call as agent activity.
+func activityMiddleware(state *GameState) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			state.MarkActivity()
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAntiIdleDue(t *testing.T) {
+	gs := NewGameState()
+	now := time.Now()
+	if gs.AntiIdleDue(now.Add(time.Hour)) {
+		t.Error("expected anti-idle to be off by default")
+	}
+
+	gs.SetAntiIdle(true, time.Minute)
+	if gs.AntiIdleDue(now.Add(30 * time.Second)) {
+		t.Error("expected no action before the interval elapsed")
+	}
+	due := now.Add(2 * time.Minute)
+	if !gs.AntiIdleDue(due) {
+		t.Fatal("expected action after the interval elapsed")
+	}
+	if gs.AntiIdleDue(due.Add(time.Second)) {
+		t.Error("expected at most one action per interval")
+	}
+}
+
+func TestActivityMiddleware(t *testing.T) {
+	gs := NewGameState()
+	gs.SetAntiIdle(true, time.Minute)
+	gs.mu.Lock()
+	gs.lastActivity = time.Now().Add(-time.Hour)
+	gs.mu.Unlock()
+
+	handler := activityMiddleware(gs)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	if _, err := handler(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if gs.AntiIdleDue(time.Now()) {
+		t.Error("expected tool call to reset the idle timer")
+	}
+}
@@ -11,12 +11,47 @@ import (
 	"golang.org/x/oauth2"
 )
 
-const tokenFile = ".realm-token"
+// defaultTokenFile caches the Xbox Live token unless -token-file or REALM_TOKEN_FILE
+// name another file, e.g. one per account.
+const defaultTokenFile = ".realm-token"
+
+// resolveTokenFile returns the token cache path: the -token-file flag, then the
+// REALM_TOKEN_FILE env var, then defaultTokenFile.
+func resolveTokenFile(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path := os.Getenv("REALM_TOKEN_FILE"); path != "" {
+		return path
+	}
+	return defaultTokenFile
+}
+
+// checkTokenFileWritable fails early if a token could not be cached at path, before
+// the user goes through browser authentication for nothing.
+func checkTokenFileWritable(path string) error {
+	if info, err := os.Stat(path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", path)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".realm-token-check-*")
+	if err != nil {
+		return fmt.Errorf("cannot create files next to %s: %w", path, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
 
 // getTokenSource returns an OAuth2 token source for Xbox Live authentication.
-// It tries to load a cached token first, falling back to browser-based auth.
-func getTokenSource() (oauth2.TokenSource, error) {
-	token, err := loadToken()
+// It tries to load a token cached at path first, falling back to browser-based auth.
+func getTokenSource(path string) (oauth2.TokenSource, error) {
+	token, err := loadToken(path)
 	if err == nil {
 		slog.Info("using cached authentication")
 		return auth.RefreshTokenSource(token), nil
@@ -28,15 +63,15 @@ func getTokenSource() (oauth2.TokenSource, error) {
 		return nil, err
 	}
 
-	if err := saveToken(token); err != nil {
+	if err := saveToken(path, token); err != nil {
 		slog.Warn("could not cache token", "error", err)
 	}
 
 	return auth.RefreshTokenSource(token), nil
 }
 
-func loadToken() (*oauth2.Token, error) {
-	data, err := os.ReadFile(tokenFile)
+func loadToken(path string) (*oauth2.Token, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -47,12 +82,12 @@ func loadToken() (*oauth2.Token, error) {
 	return &token, nil
 }
 
-func saveToken(token *oauth2.Token) error {
+func saveToken(path string, token *oauth2.Token) error {
 	data, err := json.Marshal(token)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(tokenFile, data, 0600)
+	return os.WriteFile(path, data, 0600)
 }
 
 // getRealmInvite returns the Realm invite code from env or file.
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestResolveTokenFile(t *testing.T) {
+	t.Setenv("REALM_TOKEN_FILE", "")
+	if got := resolveTokenFile(""); got != defaultTokenFile {
+		t.Errorf("expected default %q, got %q", defaultTokenFile, got)
+	}
+	t.Setenv("REALM_TOKEN_FILE", "alt.token")
+	if got := resolveTokenFile(""); got != "alt.token" {
+		t.Errorf("expected env file alt.token, got %q", got)
+	}
+	if got := resolveTokenFile("flag.token"); got != "flag.token" {
+		t.Errorf("expected flag to override env, got %q", got)
+	}
+}
+
+func TestSaveLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.token"), filepath.Join(dir, "second.token")
+	if err := saveToken(first, &oauth2.Token{RefreshToken: "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveToken(second, &oauth2.Token{RefreshToken: "two"}); err != nil {
+		t.Fatal(err)
+	}
+	for path, expected := range map[string]string{first: "one", second: "two"} {
+		token, err := loadToken(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if token.RefreshToken != expected {
+			t.Errorf("%s: expected refresh token %q, got %q", path, expected, token.RefreshToken)
+		}
+	}
+	if _, err := loadToken(filepath.Join(dir, "missing.token")); err == nil {
+		t.Error("expected error for a missing token file")
+	}
+}
+
+func TestCheckTokenFileWritable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "new.token")
+	if err := checkTokenFileWritable(path); err != nil {
+		t.Errorf("expected new file in a writable dir to pass, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected the check to leave no files behind, got %d", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkTokenFileWritable(path); err != nil {
+		t.Errorf("expected existing writable file to pass, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{}" {
+		t.Errorf("expected the check to leave the token intact, got %q", data)
+	}
+
+	if err := checkTokenFileWritable(dir); err == nil {
+		t.Error("expected a directory to fail")
+	}
+	if err := checkTokenFileWritable(filepath.Join(dir, "missing", "x.token")); err == nil {
+		t.Error("expected a missing directory to fail")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// noCommandAliases is the AliasesOffset of commands without aliases.
+const noCommandAliases = 0xffffffff
+
+// commandArgTypeNames names the basic command parameter types, as shown in usage hints.
+var commandArgTypeNames = map[uint32]string{
+	protocol.CommandArgTypeInt:             "int",
+	protocol.CommandArgTypeFloat:           "float",
+	protocol.CommandArgTypeValue:           "value",
+	protocol.CommandArgTypeWildcardInt:     "wildcard int",
+	protocol.CommandArgTypeOperator:        "operator",
+	protocol.CommandArgTypeCompareOperator: "compare operator",
+	protocol.CommandArgTypeTarget:          "target",
+	protocol.CommandArgTypeWildcardTarget:  "wildcard target",
+	protocol.CommandArgTypeFilepath:        "filepath",
+	protocol.CommandArgTypeIntegerRange:    "integer range",
+	protocol.CommandArgTypeEquipmentSlots:  "equipment slots",
+	protocol.CommandArgTypeString:          "string",
+	protocol.CommandArgTypeBlockPosition:   "x y z",
+	protocol.CommandArgTypePosition:        "x y z",
+	protocol.CommandArgTypeMessage:         "message",
+	protocol.CommandArgTypeRawText:         "text",
+	protocol.CommandArgTypeJSON:            "json",
+	protocol.CommandArgTypeBlockStates:     "block states",
+	protocol.CommandArgTypeCommand:         "command",
+}
+
+// CommandParam is one parameter of a command overload.
+type CommandParam struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional"`
+}
+
+// CommandInfo describes a command the server allows the player to run.
+type CommandInfo struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Aliases     []string         `json:"aliases,omitempty"`
+	Overloads   [][]CommandParam `json:"overloads"`
+}
+
+// commandParamType resolves the type of a command parameter to a readable name:
+// the enum type for enum parameters, otherwise the basic argument type.
+func commandParamType(pk *packet.AvailableCommands, t uint32) string {
+	idx := t & 0xffff
+	switch {
+	case t&protocol.CommandArgSoftEnum != 0:
+		if int(idx) < len(pk.DynamicEnums) {
+			return pk.DynamicEnums[idx].Type
+		}
+	case t&protocol.CommandArgEnum != 0:
+		if int(idx) < len(pk.Enums) {
+			return pk.Enums[idx].Type
+		}
+	case t&protocol.CommandArgSuffixed != 0:
+		if int(idx) < len(pk.Suffixes) {
+			return "int" + pk.Suffixes[idx]
+		}
+	default:
+		if name, ok := commandArgTypeNames[idx]; ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("unknown(%#x)", t)
+}
+
+// parseAvailableCommands extracts command names, aliases and overload parameters from
+// an AvailableCommands packet, sorted by command name.
+func parseAvailableCommands(pk *packet.AvailableCommands) []CommandInfo {
+	result := make([]CommandInfo, 0, len(pk.Commands))
+	for _, c := range pk.Commands {
+		info := CommandInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Overloads:   make([][]CommandParam, 0, len(c.Overloads)),
+		}
+		if c.AliasesOffset != noCommandAliases && int(c.AliasesOffset) < len(pk.Enums) {
+			for _, vi := range pk.Enums[c.AliasesOffset].ValueIndices {
+				if int(vi) < len(pk.EnumValues) && pk.EnumValues[vi] != c.Name {
+					info.Aliases = append(info.Aliases, pk.EnumValues[vi])
+				}
+			}
+		}
+		for _, o := range c.Overloads {
+			params := make([]CommandParam, 0, len(o.Parameters))
+			for _, p := range o.Parameters {
+				params = append(params, CommandParam{
+					Name:     p.Name,
+					Type:     commandParamType(pk, p.Type),
+					Optional: p.Optional,
+				})
+			}
+			info.Overloads = append(info.Overloads, params)
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// SetAvailableCommands replaces the commands available to the player.
+func (gs *GameState) SetAvailableCommands(commands []CommandInfo) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.availableCommands = commands
+}
+
+// AvailableCommands returns the commands available to the player, sorted by name.
+func (gs *GameState) AvailableCommands() []CommandInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.availableCommands
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestParseAvailableCommands(t *testing.T) {
+	pk := &packet.AvailableCommands{
+		EnumValues: []string{"kit", "k", "starter", "pvp"},
+		Enums: []protocol.CommandEnum{
+			{Type: "KitAliases", ValueIndices: []uint32{0, 1}},
+			{Type: "KitName", ValueIndices: []uint32{2, 3}},
+		},
+		Commands: []protocol.Command{
+			{
+				Name:          "tp",
+				Description:   "Teleports entities.",
+				AliasesOffset: noCommandAliases,
+				Overloads: []protocol.CommandOverload{{Parameters: []protocol.CommandParameter{
+					{Name: "destination", Type: protocol.CommandArgValid | protocol.CommandArgTypePosition},
+				}}},
+			},
+			{
+				Name:          "kit",
+				Description:   "Claim a kit.",
+				AliasesOffset: 0,
+				Overloads: []protocol.CommandOverload{{Parameters: []protocol.CommandParameter{
+					{Name: "name", Type: protocol.CommandArgValid | protocol.CommandArgEnum | 1},
+					{Name: "player", Type: protocol.CommandArgValid | protocol.CommandArgTypeTarget, Optional: true},
+				}}},
+			},
+		},
+	}
+
+	cmds := parseAvailableCommands(pk)
+	if len(cmds) != 2 || cmds[0].Name != "kit" || cmds[1].Name != "tp" {
+		t.Fatalf("expected [kit tp], got %+v", cmds)
+	}
+	kit := cmds[0]
+	if len(kit.Aliases) != 1 || kit.Aliases[0] != "k" {
+		t.Errorf("expected alias k, got %v", kit.Aliases)
+	}
+	params := kit.Overloads[0]
+	if params[0].Type != "KitName" {
+		t.Errorf("expected enum type KitName, got %s", params[0].Type)
+	}
+	if params[1].Type != "target" || !params[1].Optional {
+		t.Errorf("expected optional target, got %+v", params[1])
+	}
+	if got := cmds[1].Overloads[0][0].Type; got != "x y z" {
+		t.Errorf("expected position type, got %s", got)
+	}
+}
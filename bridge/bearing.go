@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+)
+
+// compassPoints are the eight compass directions, clockwise from north. In Minecraft
+// north is -Z and east is +X.
+var compassPoints = [8]string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// Bearing describes the horizontal direction from the player to a target.
+type Bearing struct {
+	Target    [2]float64 `json:"target"` // x, z
+	Distance  float64    `json:"distance"`
+	Direction string     `json:"direction"`
+	Yaw       float64    `json:"yaw"` // yaw to face the target (0 = south, 90 = west)
+}
+
+// compassDirection quantizes a compass angle (degrees clockwise from north) to the
+// nearest of the eight compass points. Angles exactly between two points round clockwise.
+func compassDirection(angle float64) string {
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return compassPoints[int(math.Floor((angle+22.5)/45))%len(compassPoints)]
+}
+
+// bearingTo returns the bearing from x, z to the target tx, tz.
+func bearingTo(x, z, tx, tz float64) Bearing {
+	dx, dz := tx-x, tz-z
+	yaw := math.Atan2(-dx, dz) * 180 / math.Pi
+	if yaw <= -180 {
+		yaw += 360 // due north is reported as 180, not -180
+	}
+	return Bearing{
+		Target:    [2]float64{tx, tz},
+		Distance:  math.Hypot(dx, dz),
+		Direction: compassDirection(math.Atan2(dx, -dz) * 180 / math.Pi),
+		Yaw:       yaw,
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompassDirection(t *testing.T) {
+	tests := []struct {
+		angle float64
+		want  string
+	}{
+		{0, "N"},
+		{22.4, "N"},
+		{22.5, "NE"},
+		{45, "NE"},
+		{67.5, "E"},
+		{90, "E"},
+		{135, "SE"},
+		{180, "S"},
+		{225, "SW"},
+		{270, "W"},
+		{315, "NW"},
+		{337.4, "NW"},
+		{337.5, "N"},
+		{360, "N"},
+		{-45, "NW"},
+		{-90, "W"},
+	}
+	for _, tt := range tests {
+		if got := compassDirection(tt.angle); got != tt.want {
+			t.Errorf("compassDirection(%v) = %s, expected %s", tt.angle, got, tt.want)
+		}
+	}
+}
+
+func TestBearingTo(t *testing.T) {
+	tests := []struct {
+		tx, tz  float64
+		wantDir string
+		wantYaw float64
+	}{
+		{0, -10, "N", 180},
+		{10, 0, "E", -90},
+		{0, 10, "S", 0},
+		{-10, 0, "W", 90},
+		{10, -10, "NE", -135},
+	}
+	for _, tt := range tests {
+		b := bearingTo(0, 0, tt.tx, tt.tz)
+		if b.Direction != tt.wantDir {
+			t.Errorf("bearing to %v,%v: expected %s, got %s", tt.tx, tt.tz, tt.wantDir, b.Direction)
+		}
+		if math.Abs(b.Yaw-tt.wantYaw) > 1e-9 {
+			t.Errorf("bearing to %v,%v: expected yaw %v, got %v", tt.tx, tt.tz, tt.wantYaw, b.Yaw)
+		}
+	}
+	if b := bearingTo(0, 0, 3, 4); b.Distance != 5 {
+		t.Errorf("expected distance 5, got %v", b.Distance)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// biomeNames maps Bedrock numeric biome IDs, as found in chunk biome palettes,
+// to their identifiers.
+var biomeNames = map[uint32]string{
+	0:   "ocean",
+	1:   "plains",
+	2:   "desert",
+	3:   "extreme_hills",
+	4:   "forest",
+	5:   "taiga",
+	6:   "swampland",
+	7:   "river",
+	8:   "hell",
+	9:   "the_end",
+	10:  "legacy_frozen_ocean",
+	11:  "frozen_river",
+	12:  "ice_plains",
+	13:  "ice_mountains",
+	14:  "mushroom_island",
+	15:  "mushroom_island_shore",
+	16:  "beach",
+	17:  "desert_hills",
+	18:  "forest_hills",
+	19:  "taiga_hills",
+	20:  "extreme_hills_edge",
+	21:  "jungle",
+	22:  "jungle_hills",
+	23:  "jungle_edge",
+	24:  "deep_ocean",
+	25:  "stone_beach",
+	26:  "cold_beach",
+	27:  "birch_forest",
+	28:  "birch_forest_hills",
+	29:  "roofed_forest",
+	30:  "cold_taiga",
+	31:  "cold_taiga_hills",
+	32:  "mega_taiga",
+	33:  "mega_taiga_hills",
+	34:  "extreme_hills_plus_trees",
+	35:  "savanna",
+	36:  "savanna_plateau",
+	37:  "mesa",
+	38:  "mesa_plateau_stone",
+	39:  "mesa_plateau",
+	40:  "warm_ocean",
+	41:  "deep_warm_ocean",
+	42:  "lukewarm_ocean",
+	43:  "deep_lukewarm_ocean",
+	44:  "cold_ocean",
+	45:  "deep_cold_ocean",
+	46:  "frozen_ocean",
+	47:  "deep_frozen_ocean",
+	48:  "bamboo_jungle",
+	49:  "bamboo_jungle_hills",
+	129: "sunflower_plains",
+	130: "desert_mutated",
+	131: "extreme_hills_mutated",
+	132: "flower_forest",
+	133: "taiga_mutated",
+	134: "swampland_mutated",
+	140: "ice_plains_spikes",
+	149: "jungle_mutated",
+	151: "jungle_edge_mutated",
+	155: "birch_forest_mutated",
+	156: "birch_forest_hills_mutated",
+	157: "roofed_forest_mutated",
+	158: "cold_taiga_mutated",
+	160: "redwood_taiga_mutated",
+	161: "redwood_taiga_hills_mutated",
+	162: "extreme_hills_plus_trees_mutated",
+	163: "savanna_mutated",
+	164: "savanna_plateau_mutated",
+	165: "mesa_bryce",
+	166: "mesa_plateau_stone_mutated",
+	167: "mesa_plateau_mutated",
+	178: "soulsand_valley",
+	179: "crimson_forest",
+	180: "warped_forest",
+	181: "basalt_deltas",
+	182: "jagged_peaks",
+	183: "frozen_peaks",
+	184: "snowy_slopes",
+	185: "grove",
+	186: "meadow",
+	187: "lush_caves",
+	188: "dripstone_caves",
+	189: "stony_peaks",
+	190: "deep_dark",
+	191: "mangrove_swamp",
+	192: "cherry_grove",
+	193: "pale_garden",
+}
+
+// biomeName returns the identifier of a biome ID, or "biome_<id>" for IDs
+// missing from the table (new or custom biomes).
+func biomeName(id uint32) string {
+	if name, ok := biomeNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("biome_%d", id)
+}
+
+// decodeBiomes reads the 3D biome palettes that follow the block data of a
+// LevelChunk: one paletted storage per sub-chunk from the bottom of the
+// dimension, where a storage may reuse the one below it. Decoding stops at
+// the first error, returning the storages read so far.
+func decodeBiomes(buf *bytes.Buffer, dimension int32) ([]*palettedStorage, error) {
+	minY, maxY := dimensionHeight(dimension)
+	count := int(maxY-minY) / 16
+	biomes := make([]*palettedStorage, 0, count)
+	for len(biomes) < count && buf.Len() > 0 {
+		s, err := decodePalettedStorage(buf)
+		if err != nil {
+			return biomes, err
+		}
+		if s == nil {
+			if len(biomes) == 0 {
+				return biomes, fmt.Errorf("first biome storage refers to a previous one")
+			}
+			s = biomes[len(biomes)-1]
+		}
+		biomes = append(biomes, s)
+	}
+	return biomes, nil
+}
+
+// handleChunkBiomes decodes the biome section of a LevelChunk payload. buf must
+// be positioned just past the sub-chunk block data.
+func handleChunkBiomes(buf *bytes.Buffer, p *packet.LevelChunk, state *GameState, radius int32) {
+	biomes, err := decodeBiomes(buf, p.Dimension)
+	if err != nil {
+		slog.Debug("chunk biome decode failed", "chunk", p.Position, "decoded", len(biomes), "error", err)
+	}
+	if len(biomes) == 0 {
+		return
+	}
+	feet := playerFeetBlock(state)
+	center := protocol.ChunkPos{feet[0] >> 4, feet[2] >> 4}
+	state.SetChunkBiomes(p.Dimension, p.Position, biomes, center, radius)
+}
+
+// SetChunkBiomes stores the biome storages of a chunk column and drops columns
+// more than radius chunks away from center.
+func (gs *GameState) SetChunkBiomes(dimension int32, pos protocol.ChunkPos, biomes []*palettedStorage, center protocol.ChunkPos, radius int32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	columns, ok := gs.biomes[dimension]
+	if !ok {
+		columns = make(map[protocol.ChunkPos][]*palettedStorage)
+		gs.biomes[dimension] = columns
+	}
+	columns[pos] = biomes
+	for p := range columns {
+		dx, dz := p[0]-center[0], p[1]-center[1]
+		if dx < -radius || dx > radius || dz < -radius || dz > radius {
+			delete(columns, p)
+		}
+	}
+}
+
+// BiomeAt returns the biome ID at a block position in a dimension. ok is false
+// if the chunk's biome data has not been parsed.
+func (gs *GameState) BiomeAt(dimension int32, pos protocol.BlockPos) (id uint32, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	biomes, ok := gs.biomes[dimension][protocol.ChunkPos{pos[0] >> 4, pos[2] >> 4}]
+	if !ok {
+		return 0, false
+	}
+	i := int(pos[1]>>4 - minSubChunkIndex(dimension))
+	if i < 0 || i >= len(biomes) {
+		return 0, false
+	}
+	return biomes[i].at(pos[0], pos[1], pos[2]), true
+}
+
+// BiomeInfo describes the biome at a position. Name is "unknown" and ID is
+// omitted when the chunk has not been parsed.
+type BiomeInfo struct {
+	ID        *uint32           `json:"id,omitempty"`
+	Name      string            `json:"name"`
+	Position  protocol.BlockPos `json:"position"`
+	Dimension string            `json:"dimension"`
+}
+
+// playerBiome returns the biome at the player's feet.
+func playerBiome(state *GameState) BiomeInfo {
+	_, _, _, _, _, dimension := state.Position()
+	feet := playerFeetBlock(state)
+	info := BiomeInfo{Name: "unknown", Position: feet, Dimension: dimensionName(dimension)}
+	if id, ok := state.BiomeAt(dimension, feet); ok {
+		info.ID = &id
+		info.Name = biomeName(id)
+	}
+	return info
+}
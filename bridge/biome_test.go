@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// encodeTestBiomes writes the overworld's 24 biome storages: the bottom one is
+// plains with a desert column at local x=1, z=3, and the rest repeat it.
+func encodeTestBiomes(buf *bytes.Buffer) {
+	indices := make([]uint16, 4096)
+	for y := int32(0); y < 16; y++ {
+		indices[storageIndex(1, y, 3)] = 1
+	}
+	encodeStorage(buf, 1, []uint32{1, 2}, indices)
+	for i := 1; i < 24; i++ {
+		buf.WriteByte(0x7f<<1 | 1)
+	}
+}
+
+func TestDecodeBiomes(t *testing.T) {
+	var buf bytes.Buffer
+	encodeTestBiomes(&buf)
+	biomes, err := decodeBiomes(&buf, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(biomes) != 24 {
+		t.Fatalf("expected 24 storages, got %d", len(biomes))
+	}
+	if got := biomes[23].at(1, 5, 3); got != 2 {
+		t.Errorf("expected repeated storage to hold desert, got %d", got)
+	}
+
+	// A leading "same as previous" marker has nothing to refer to
+	if _, err := decodeBiomes(bytes.NewBuffer([]byte{0x7f<<1 | 1}), 0); err == nil {
+		t.Error("expected error for leading copy marker")
+	}
+}
+
+func TestBiomeName(t *testing.T) {
+	tests := []struct {
+		id   uint32
+		want string
+	}{
+		{1, "plains"},
+		{8, "hell"},
+		{192, "cherry_grove"},
+		{999, "biome_999"},
+	}
+	for _, tt := range tests {
+		if got := biomeName(tt.id); got != tt.want {
+			t.Errorf("biomeName(%d): expected %q, got %q", tt.id, tt.want, got)
+		}
+	}
+}
+
+func TestHandleLevelChunk_Biomes(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(1.5, 64+playerEyeHeight, 3.5, 0, 0)
+	gs.SetChunkParsing(true, 2)
+
+	if info := playerBiome(gs); info.Name != "unknown" || info.ID != nil {
+		t.Errorf("expected unknown biome before parsing, got %+v", info)
+	}
+
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, 4, 1, 5)
+	encodeTestBiomes(&buf)
+	interceptServerPacket(&packet.LevelChunk{
+		Position:      protocol.ChunkPos{0, 0},
+		SubChunkCount: 1,
+		RawPayload:    buf.Bytes(),
+	}, gs)
+
+	info := playerBiome(gs)
+	if info.ID == nil || *info.ID != 2 || info.Name != "desert" {
+		t.Errorf("expected desert (2), got %+v", info)
+	}
+	if id, ok := gs.BiomeAt(0, protocol.BlockPos{0, 64, 0}); !ok || id != 1 {
+		t.Errorf("expected plains (1) at origin, got %d (ok=%v)", id, ok)
+	}
+
+	// Sub-chunk request mode carries only biomes
+	buf.Reset()
+	encodeTestBiomes(&buf)
+	interceptServerPacket(&packet.LevelChunk{
+		Position:      protocol.ChunkPos{1, 0},
+		SubChunkCount: protocol.SubChunkRequestModeLimitless,
+		RawPayload:    buf.Bytes(),
+	}, gs)
+	if id, ok := gs.BiomeAt(0, protocol.BlockPos{17, -60, 3}); !ok || id != 2 {
+		t.Errorf("expected desert (2) in request-mode chunk, got %d (ok=%v)", id, ok)
+	}
+}
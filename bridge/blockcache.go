@@ -0,0 +1,298 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// BlockEntry is a cached block observed from the server.
+type BlockEntry struct {
+	RuntimeID uint32
+	Updated   time.Time
+}
+
+// DefaultBlockCacheSize is the default cap on cached block entries.
+const DefaultBlockCacheSize = 100_000
+
+// blockCacheAgeWeight converts entry age into an equivalent distance in blocks when
+// ranking entries for eviction: a block seen a minute ago counts as one block farther.
+const blockCacheAgeWeight = 1.0 / 60
+
+// blockBucket holds the individually updated blocks of one sub-chunk, which are
+// evicted together, and when one of them was last updated.
+type blockBucket struct {
+	blocks  map[protocol.BlockPos]BlockEntry
+	updated time.Time
+}
+
+// cachedSubChunk is a decoded sub-chunk block layer and when it was received.
+// Pinned sub-chunks were requested explicitly and are kept at any distance.
+type cachedSubChunk struct {
+	layer  *palettedStorage
+	loaded time.Time
+	pinned bool
+}
+
+// chunkColumn records how many sub-chunks of a chunk column are held, when the
+// first was received and when a block in it was last updated.
+type chunkColumn struct {
+	subChunks int
+	loaded    time.Time
+	updated   time.Time
+}
+
+// ChunkColumnInfo describes the cached data of a chunk column.
+type ChunkColumnInfo struct {
+	SubChunks int
+	Loaded    time.Time
+	Updated   time.Time
+}
+
+// BlockCache is a thread-safe spatial cache of block runtime IDs keyed by position.
+// It has its own lock so block-heavy packets don't contend with the rest of GameState.
+// Individual block updates are stored per position, bucketed by sub-chunk; when
+// they grow past maxEntries, the buckets farthest from the player and longest
+// without an update are evicted. Decoded sub-chunks are stored whole and dropped
+// once outside subChunkRadius of the player, unless pinned, which is checked when the
+// player moves to another chunk.
+type BlockCache struct {
+	mu         sync.RWMutex
+	buckets    map[protocol.SubChunkPos]*blockBucket
+	count      int // blocks held in buckets
+	maxEntries int
+	center     protocol.BlockPos
+
+	subChunks      map[protocol.SubChunkPos]cachedSubChunk
+	subChunkRadius int32
+	columns        map[protocol.ChunkPos]chunkColumn
+}
+
+// NewBlockCache creates an empty block cache holding at most maxEntries blocks.
+// A maxEntries of zero or less disables the cap.
+func NewBlockCache(maxEntries int) *BlockCache {
+	return &BlockCache{
+		buckets:        make(map[protocol.SubChunkPos]*blockBucket),
+		maxEntries:     maxEntries,
+		subChunks:      make(map[protocol.SubChunkPos]cachedSubChunk),
+		subChunkRadius: DefaultChunkRadius,
+		columns:        make(map[protocol.ChunkPos]chunkColumn),
+	}
+}
+
+// SetSubChunkRadius sets how many chunks around the player decoded sub-chunks are kept.
+func (c *BlockCache) SetSubChunkRadius(radius int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subChunkRadius = int32(radius)
+	c.pruneSubChunksLocked()
+}
+
+// SetSubChunk stores a decoded block layer for a whole sub-chunk, unless it is
+// outside the radius around the player.
+func (c *BlockCache) SetSubChunk(pos protocol.SubChunkPos, layer *palettedStorage) {
+	c.storeSubChunk(pos, layer, false)
+}
+
+// SetPinnedSubChunk stores a decoded block layer for a whole sub-chunk that is kept
+// regardless of its distance from the player until UnpinSubChunks is called.
+func (c *BlockCache) SetPinnedSubChunk(pos protocol.SubChunkPos, layer *palettedStorage) {
+	c.storeSubChunk(pos, layer, true)
+}
+
+// UnpinSubChunks makes all pinned sub-chunks subject to the radius again.
+func (c *BlockCache) UnpinSubChunks() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for p, sc := range c.subChunks {
+		if sc.pinned {
+			sc.pinned = false
+			c.subChunks[p] = sc
+		}
+	}
+	c.pruneSubChunksLocked()
+}
+
+func (c *BlockCache) storeSubChunk(pos protocol.SubChunkPos, layer *palettedStorage, pinned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !pinned && !c.withinRadiusLocked(pos) {
+		return
+	}
+	now := time.Now()
+	colPos := protocol.ChunkPos{pos[0], pos[2]}
+	col, ok := c.columns[colPos]
+	if !ok {
+		col.loaded = now
+	}
+	if _, ok := c.subChunks[pos]; !ok {
+		col.subChunks++
+	}
+	col.updated = now
+	c.columns[colPos] = col
+	c.subChunks[pos] = cachedSubChunk{layer: layer, loaded: now, pinned: pinned}
+}
+
+// withinRadiusLocked reports whether a sub-chunk is within subChunkRadius chunks of
+// the player. Callers must hold c.mu.
+func (c *BlockCache) withinRadiusLocked(pos protocol.SubChunkPos) bool {
+	dx, dz := pos[0]-c.center[0]>>4, pos[2]-c.center[2]>>4
+	return dx >= -c.subChunkRadius && dx <= c.subChunkRadius && dz >= -c.subChunkRadius && dz <= c.subChunkRadius
+}
+
+// pruneSubChunksLocked drops the unpinned sub-chunks outside the radius, and the
+// columns left without sub-chunks. Callers must hold c.mu.
+func (c *BlockCache) pruneSubChunksLocked() {
+	for p, sc := range c.subChunks {
+		if sc.pinned || c.withinRadiusLocked(p) {
+			continue
+		}
+		delete(c.subChunks, p)
+		colPos := protocol.ChunkPos{p[0], p[2]}
+		if col := c.columns[colPos]; col.subChunks > 1 {
+			col.subChunks--
+			c.columns[colPos] = col
+		} else {
+			delete(c.columns, colPos)
+		}
+	}
+}
+
+// Column returns what is cached of a chunk column, or false if none of its
+// sub-chunks are held.
+func (c *BlockCache) Column(pos protocol.ChunkPos) (ChunkColumnInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	col, ok := c.columns[pos]
+	if !ok {
+		return ChunkColumnInfo{}, false
+	}
+	return ChunkColumnInfo{SubChunks: col.subChunks, Loaded: col.loaded, Updated: col.updated}, true
+}
+
+// SubChunkCount returns the number of decoded sub-chunks held.
+func (c *BlockCache) SubChunkCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.subChunks)
+}
+
+func subChunkPosOf(pos protocol.BlockPos) protocol.SubChunkPos {
+	return protocol.SubChunkPos{pos[0] >> 4, pos[1] >> 4, pos[2] >> 4}
+}
+
+// SetMaxEntries changes the cap, evicting immediately if the cache is over it.
+func (c *BlockCache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = n
+	c.evictLocked(time.Now())
+}
+
+// SetCenter records the player's position used to rank entries for eviction, and
+// drops sub-chunks outside the radius when the player enters another chunk.
+func (c *BlockCache) SetCenter(pos protocol.BlockPos) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	moved := pos[0]>>4 != c.center[0]>>4 || pos[2]>>4 != c.center[2]>>4
+	c.center = pos
+	if moved {
+		c.pruneSubChunksLocked()
+	}
+}
+
+// Set stores the runtime ID of the block at pos.
+func (c *BlockCache) Set(pos protocol.BlockPos, runtimeID uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	key := subChunkPosOf(pos)
+	b := c.buckets[key]
+	if b == nil {
+		b = &blockBucket{blocks: make(map[protocol.BlockPos]BlockEntry)}
+		c.buckets[key] = b
+	}
+	if _, ok := b.blocks[pos]; !ok {
+		c.count++
+	}
+	b.blocks[pos] = BlockEntry{RuntimeID: runtimeID, Updated: now}
+	b.updated = now
+	if sc, ok := c.subChunks[key]; ok {
+		sc.layer.set(pos[0], pos[1], pos[2], runtimeID)
+		col := protocol.ChunkPos{pos[0] >> 4, pos[2] >> 4}
+		if cc, ok := c.columns[col]; ok {
+			cc.updated = now
+			c.columns[col] = cc
+		}
+	}
+	c.evictLocked(now)
+}
+
+// evictLocked trims the cache when it exceeds maxEntries. Whole buckets are ranked
+// by the distance of their centre from the player and the age of their last update,
+// so the sort is over sub-chunks rather than blocks, and it evicts down to 90% of
+// the cap so it runs once per many inserts. Must be called with the write lock held.
+func (c *BlockCache) evictLocked(now time.Time) {
+	if c.maxEntries <= 0 || c.count <= c.maxEntries {
+		return
+	}
+	target := c.maxEntries * 9 / 10
+
+	type scored struct {
+		pos   protocol.SubChunkPos
+		score float64
+	}
+	entries := make([]scored, 0, len(c.buckets))
+	for pos, b := range c.buckets {
+		dx := float64(pos[0]<<4 + 8 - c.center[0])
+		dy := float64(pos[1]<<4 + 8 - c.center[1])
+		dz := float64(pos[2]<<4 + 8 - c.center[2])
+		dist := dx*dx + dy*dy + dz*dz
+		age := now.Sub(b.updated).Seconds() * blockCacheAgeWeight
+		entries = append(entries, scored{pos: pos, score: dist + age*age})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].score > entries[j].score })
+	for _, e := range entries {
+		if c.count <= target {
+			break
+		}
+		c.count -= len(c.buckets[e.pos].blocks)
+		delete(c.buckets, e.pos)
+	}
+}
+
+// Get returns the cached block at pos, preferring whichever of the individual block
+// update or the decoded sub-chunk is more recent.
+func (c *BlockCache) Get(pos protocol.BlockPos) (BlockEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key := subChunkPosOf(pos)
+	var e BlockEntry
+	var ok bool
+	if b := c.buckets[key]; b != nil {
+		e, ok = b.blocks[pos]
+	}
+	if sc, scOK := c.subChunks[key]; scOK && (!ok || sc.loaded.After(e.Updated)) {
+		return BlockEntry{RuntimeID: sc.layer.at(pos[0], pos[1], pos[2]), Updated: sc.loaded}, true
+	}
+	return e, ok
+}
+
+// Len returns the number of cached blocks.
+func (c *BlockCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.count
+}
+
+// Clear removes all cached blocks.
+func (c *BlockCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buckets = make(map[protocol.SubChunkPos]*blockBucket)
+	c.count = 0
+	c.subChunks = make(map[protocol.SubChunkPos]cachedSubChunk)
+	c.columns = make(map[protocol.ChunkPos]chunkColumn)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestBlockCache_SetGet(t *testing.T) {
+	c := NewBlockCache(DefaultBlockCacheSize)
+	pos := protocol.BlockPos{1, 64, -3}
+
+	if _, ok := c.Get(pos); ok {
+		t.Fatal("expected empty cache miss")
+	}
+	c.Set(pos, 42)
+	e, ok := c.Get(pos)
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if e.RuntimeID != 42 {
+		t.Errorf("expected runtime ID 42, got %d", e.RuntimeID)
+	}
+	if e.Updated.IsZero() {
+		t.Error("expected update time to be set")
+	}
+
+	c.Set(pos, 7)
+	if e, _ := c.Get(pos); e.RuntimeID != 7 {
+		t.Errorf("expected overwrite to 7, got %d", e.RuntimeID)
+	}
+	if c.Len() != 1 {
+		t.Errorf("expected 1 entry, got %d", c.Len())
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after clear, got %d", c.Len())
+	}
+}
+
+func TestBlockCache_EvictsFarthest(t *testing.T) {
+	const cap = 500
+	c := NewBlockCache(cap)
+	c.SetCenter(protocol.BlockPos{0, 64, 0})
+
+	// Fill every fourth block of a 128x128 layer around the center, 16 blocks per
+	// sub-chunk and well over the cap
+	for x := int32(-64); x < 64; x += 4 {
+		for z := int32(-64); z < 64; z += 4 {
+			c.Set(protocol.BlockPos{x, 64, z}, 1)
+		}
+	}
+
+	if n := c.Len(); n > cap {
+		t.Fatalf("cache exceeded cap: %d > %d", n, cap)
+	}
+	// Blocks next to the player must survive
+	for _, pos := range []protocol.BlockPos{{0, 64, 0}, {4, 64, 4}, {-4, 64, -4}} {
+		if _, ok := c.Get(pos); !ok {
+			t.Errorf("expected nearby block %v to be kept", pos)
+		}
+	}
+	// The farthest corner should be gone
+	if _, ok := c.Get(protocol.BlockPos{-64, 64, -64}); ok {
+		t.Error("expected farthest block to be evicted")
+	}
+}
+
+func TestBlockCache_SetMaxEntries(t *testing.T) {
+	c := NewBlockCache(0) // unbounded
+	for i := int32(0); i < 500; i++ {
+		c.Set(protocol.BlockPos{i, 0, 0}, 1)
+	}
+	if c.Len() != 500 {
+		t.Fatalf("expected unbounded cache to hold 500, got %d", c.Len())
+	}
+	c.SetMaxEntries(100)
+	if n := c.Len(); n > 100 {
+		t.Errorf("expected cache trimmed to cap, got %d", n)
+	}
+	if _, ok := c.Get(protocol.BlockPos{0, 0, 0}); !ok {
+		t.Error("expected block at center to be kept")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// maxBlockEntities bounds the block entities kept per dimension. When full, the entity
+// farthest from the player is evicted.
+const maxBlockEntities = 10_000
+
+// BlockEntity holds the NBT of a block entity (chest, sign, ...) from BlockActorData.
+type BlockEntity struct {
+	Position protocol.BlockPos `json:"position"`
+	NBT      map[string]any    `json:"nbt"`
+}
+
+// blockDistSq returns the squared distance between two block positions.
+func blockDistSq(a, b protocol.BlockPos) int64 {
+	dx, dy, dz := int64(a[0]-b[0]), int64(a[1]-b[1]), int64(a[2]-b[2])
+	return dx*dx + dy*dy + dz*dz
+}
+
+// SetBlockEntity stores the NBT of the block entity at pos in the current dimension.
+func (gs *GameState) SetBlockEntity(pos protocol.BlockPos, nbt map[string]any) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	entities, ok := gs.blockEntities[gs.dimension]
+	if !ok {
+		entities = make(map[protocol.BlockPos]map[string]any)
+		gs.blockEntities[gs.dimension] = entities
+	}
+	if _, exists := entities[pos]; !exists && len(entities) >= maxBlockEntities {
+		player := protocol.BlockPos{int32(gs.posX), int32(gs.posY), int32(gs.posZ)}
+		var farthest protocol.BlockPos
+		var farthestDist int64 = -1
+		for p := range entities {
+			if d := blockDistSq(p, player); d > farthestDist {
+				farthest, farthestDist = p, d
+			}
+		}
+		delete(entities, farthest)
+	}
+	entities[pos] = nbt
+}
+
+// BlockEntity returns the block entity at pos in the current dimension.
+func (gs *GameState) BlockEntity(pos protocol.BlockPos) (BlockEntity, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	nbt, ok := gs.blockEntities[gs.dimension][pos]
+	if !ok {
+		return BlockEntity{}, false
+	}
+	return BlockEntity{Position: pos, NBT: nbt}, true
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestBlockActorData_PerDimension(t *testing.T) {
+	gs := NewGameState()
+	pos := protocol.BlockPos{1, 64, 1}
+	interceptServerPacket(&packet.BlockActorData{
+		Position: pos,
+		NBTData:  map[string]any{"id": "Sign", "Text": "hello"},
+	}, gs)
+
+	be, ok := gs.BlockEntity(pos)
+	if !ok || be.NBT["Text"] != "hello" {
+		t.Fatalf("expected sign text hello, got %+v (ok=%v)", be, ok)
+	}
+
+	gs.SetDimension(1)
+	if _, ok := gs.BlockEntity(pos); ok {
+		t.Error("expected overworld block entity to be hidden in the nether")
+	}
+	gs.SetDimension(0)
+	if _, ok := gs.BlockEntity(pos); !ok {
+		t.Error("expected block entity to be kept after returning")
+	}
+}
+
+func TestBlockEntities_EvictFarthest(t *testing.T) {
+	gs := NewGameState()
+	for i := 0; i < maxBlockEntities; i++ {
+		gs.SetBlockEntity(protocol.BlockPos{int32(i), 0, 0}, map[string]any{})
+	}
+	gs.SetBlockEntity(protocol.BlockPos{-1, 0, 0}, map[string]any{})
+
+	gs.mu.RLock()
+	n := len(gs.blockEntities[0])
+	gs.mu.RUnlock()
+	if n != maxBlockEntities {
+		t.Errorf("expected %d block entities, got %d", maxBlockEntities, n)
+	}
+	if _, ok := gs.BlockEntity(protocol.BlockPos{maxBlockEntities - 1, 0, 0}); ok {
+		t.Error("expected the farthest block entity to be evicted")
+	}
+}
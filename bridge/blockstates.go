@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// NBT tag types written when hashing block states.
+const (
+	nbtTagEnd    = 0
+	nbtTagByte   = 1
+	nbtTagInt    = 3
+	nbtTagString = 8
+	nbtTagStruct = 10
+)
+
+// blockState is one entry of the canonical block state table: a block name and the
+// values of its state properties (byte, int32 or string).
+type blockState struct {
+	Name       string
+	Properties map[string]any
+}
+
+// readBlockStates reads a canonical block state table: the concatenated network
+// little-endian NBT compounds of every vanilla block state in runtime ID order, as
+// shipped in canonical_block_states.nbt by BedrockData or block_states.nbt by
+// dragonfly.
+func readBlockStates(r io.Reader) ([]blockState, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(data)
+	var states []blockState
+	for buf.Len() > 0 {
+		// A decoder per entry: network NBT caps the bytes one decoder reads.
+		var entry map[string]any
+		if err := nbt.NewDecoder(buf).Decode(&entry); err != nil {
+			return nil, fmt.Errorf("block state %d: %w", len(states), err)
+		}
+		name, _ := entry["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("block state %d has no name", len(states))
+		}
+		props, _ := entry["states"].(map[string]any)
+		states = append(states, blockState{Name: name, Properties: props})
+	}
+	if len(states) == 0 {
+		return nil, errors.New("no block states")
+	}
+	return states, nil
+}
+
+// loadBlockStates reads a canonical block state table from a file.
+func loadBlockStates(path string) ([]blockState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readBlockStates(f)
+}
+
+// builtinBlockStates are the states of the blocks the safety checks look for. On
+// servers using hashed block network IDs they resolve without a block state table;
+// sequential runtime IDs depend on every other state, so those need the table.
+var builtinBlockStates = func() []blockState {
+	states := []blockState{
+		{Name: "minecraft:air"},
+		{Name: "minecraft:magma"},
+		{Name: "minecraft:powder_snow"},
+		{Name: "minecraft:wither_rose"},
+	}
+	for _, name := range []string{"minecraft:water", "minecraft:flowing_water", "minecraft:lava", "minecraft:flowing_lava"} {
+		for depth := int32(0); depth < 16; depth++ {
+			states = append(states, blockState{Name: name, Properties: map[string]any{"liquid_depth": depth}})
+		}
+	}
+	for _, name := range []string{"minecraft:fire", "minecraft:soul_fire", "minecraft:cactus"} {
+		for age := int32(0); age < 16; age++ {
+			states = append(states, blockState{Name: name, Properties: map[string]any{"age": age}})
+		}
+	}
+	for growth := int32(0); growth < 8; growth++ {
+		states = append(states, blockState{Name: "minecraft:sweet_berry_bush", Properties: map[string]any{"growth": growth}})
+	}
+	for _, bit := range []byte{0, 1} {
+		states = append(states, blockState{Name: "minecraft:bedrock", Properties: map[string]any{"infiniburn_bit": bit}})
+		for _, name := range []string{"minecraft:campfire", "minecraft:soul_campfire"} {
+			for _, dir := range []string{"north", "south", "west", "east"} {
+				states = append(states, blockState{Name: name, Properties: map[string]any{"extinguished": bit, "minecraft:cardinal_direction": dir}})
+			}
+		}
+		for _, thickness := range []string{"tip", "frustum", "middle", "base", "merge"} {
+			states = append(states, blockState{Name: "minecraft:pointed_dripstone", Properties: map[string]any{"dripstone_thickness": thickness, "hanging": bit}})
+		}
+	}
+	return states
+}()
+
+// blockStateHash returns the network ID of a state on servers that set
+// UseBlockNetworkIDHashes: the FNV-1a hash of the little-endian NBT compound holding
+// the name and the properties sorted by key.
+func blockStateHash(s blockState) uint32 {
+	var buf bytes.Buffer
+	writeString := func(v string) {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(v)))
+		buf.WriteString(v)
+	}
+	buf.WriteByte(nbtTagStruct)
+	writeString("")
+	buf.WriteByte(nbtTagString)
+	writeString("name")
+	writeString(s.Name)
+	buf.WriteByte(nbtTagStruct)
+	writeString("states")
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := s.Properties[k].(type) {
+		case byte:
+			buf.WriteByte(nbtTagByte)
+			writeString(k)
+			buf.WriteByte(v)
+		case int32:
+			buf.WriteByte(nbtTagInt)
+			writeString(k)
+			binary.Write(&buf, binary.LittleEndian, v)
+		case string:
+			buf.WriteByte(nbtTagString)
+			writeString(k)
+			writeString(v)
+		}
+	}
+	buf.WriteByte(nbtTagEnd)
+	buf.WriteByte(nbtTagEnd)
+	h := fnv.New32a()
+	h.Write(buf.Bytes())
+	return h.Sum32()
+}
+
+// blockNameHash is the FNV-1 hash of a block name that orders sequential runtime IDs.
+func blockNameHash(name string) uint64 {
+	h := fnv.New64()
+	h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// buildBlockRegistry maps the block network IDs of a session to block names. With
+// hashed IDs, every state of the table, the built-in states and the server's custom
+// blocks is hashed. Otherwise runtime IDs are the positions of the table's states and
+// the custom blocks after a stable sort by name hash, and without a table none are
+// known.
+func buildBlockRegistry(table []blockState, custom []protocol.BlockEntry, hashed bool) map[uint32]string {
+	states := make([]blockState, 0, len(table)+len(custom))
+	states = append(states, table...)
+	for _, b := range custom {
+		states = append(states, blockState{Name: b.Name})
+	}
+	registry := make(map[uint32]string, len(states))
+	if hashed {
+		for _, s := range append(states, builtinBlockStates...) {
+			registry[blockStateHash(s)] = s.Name
+		}
+		return registry
+	}
+	if len(table) == 0 {
+		return registry
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return blockNameHash(states[i].Name) < blockNameHash(states[j].Name)
+	})
+	for i, s := range states {
+		registry[uint32(i)] = s.Name
+	}
+	return registry
+}
+
+// SetBlockStates sets the canonical block state table the block registry of each
+// session is built from.
+func (gs *GameState) SetBlockStates(states []blockState) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.blockStates = states
+}
+
+// AirRuntimeID returns the block network ID of air in the session, if known.
+func (gs *GameState) AirRuntimeID() (uint32, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.airRuntimeID, gs.airKnown
+}
+
+// initBlockRegistryLocked replaces the block registry for a session that starts
+// with the given custom blocks and ID scheme. Callers must hold gs.mu.
+func (gs *GameState) initBlockRegistryLocked(custom []protocol.BlockEntry, hashed bool) {
+	gs.blockRegistry = buildBlockRegistry(gs.blockStates, custom, hashed)
+	gs.airRuntimeID, gs.airKnown = 0, false
+	for id, name := range gs.blockRegistry {
+		if name == "minecraft:air" {
+			gs.airRuntimeID, gs.airKnown = id, true
+			break
+		}
+	}
+	if !hashed && len(gs.blockStates) == 0 {
+		slog.Warn("server uses sequential block runtime IDs and no -block-states table is set; cached blocks will not resolve to names")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// encodeBlockStates writes states as a canonical block state table.
+func encodeBlockStates(t *testing.T, states []blockState) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, s := range states {
+		props := s.Properties
+		if props == nil {
+			props = map[string]any{}
+		}
+		data, err := nbt.Marshal(map[string]any{"name": s.Name, "states": props, "version": int32(18105860)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+var testBlockStates = []blockState{
+	{Name: "minecraft:air"},
+	{Name: "minecraft:dirt"},
+	{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(0)}},
+	{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(1)}},
+	{Name: "minecraft:stone"},
+}
+
+func TestReadBlockStates(t *testing.T) {
+	states, err := readBlockStates(bytes.NewReader(encodeBlockStates(t, testBlockStates)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != len(testBlockStates) {
+		t.Fatalf("expected %d states, got %d", len(testBlockStates), len(states))
+	}
+	if states[3].Name != "minecraft:lava" || states[3].Properties["liquid_depth"] != int32(1) {
+		t.Errorf("expected lava with liquid_depth 1, got %+v", states[3])
+	}
+
+	if _, err := readBlockStates(bytes.NewReader(nil)); err == nil {
+		t.Error("expected an empty table to be rejected")
+	}
+	if _, err := readBlockStates(bytes.NewReader([]byte{10, 0})); err == nil {
+		t.Error("expected a truncated table to be rejected")
+	}
+}
+
+func TestBlockStateHash(t *testing.T) {
+	// The network ID of air on servers with hashed block IDs.
+	if got := int32(blockStateHash(blockState{Name: "minecraft:air"})); got != -604749536 {
+		t.Errorf("expected air to hash to -604749536, got %d", got)
+	}
+	a := blockState{Name: "minecraft:campfire", Properties: map[string]any{"extinguished": byte(0), "minecraft:cardinal_direction": "north"}}
+	b := blockState{Name: "minecraft:campfire", Properties: map[string]any{"minecraft:cardinal_direction": "north", "extinguished": byte(0)}}
+	if blockStateHash(a) != blockStateHash(b) {
+		t.Error("expected the hash not to depend on property order")
+	}
+	b.Properties["extinguished"] = byte(1)
+	if blockStateHash(a) == blockStateHash(b) {
+		t.Error("expected different states to hash differently")
+	}
+}
+
+func TestBuildBlockRegistry_Sequential(t *testing.T) {
+	registry := buildBlockRegistry(testBlockStates, []protocol.BlockEntry{{Name: "custom:gem"}}, false)
+	// Sorted by the FNV-1 hash of the name, keeping table order within a name.
+	expected := []string{"minecraft:stone", "custom:gem", "minecraft:lava", "minecraft:lava", "minecraft:dirt", "minecraft:air"}
+	if len(registry) != len(expected) {
+		t.Fatalf("expected %d runtime IDs, got %d", len(expected), len(registry))
+	}
+	for id, name := range expected {
+		if registry[uint32(id)] != name {
+			t.Errorf("runtime ID %d: expected %s, got %s", id, name, registry[uint32(id)])
+		}
+	}
+
+	if got := buildBlockRegistry(nil, nil, false); len(got) != 0 {
+		t.Errorf("expected no runtime IDs without a table, got %d", len(got))
+	}
+}
+
+func TestBuildBlockRegistry_Hashed(t *testing.T) {
+	// Without a table, the built-in states still resolve.
+	registry := buildBlockRegistry(nil, []protocol.BlockEntry{{Name: "custom:gem"}}, true)
+	lava := blockState{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(3)}}
+	for _, s := range []blockState{{Name: "minecraft:air"}, lava, {Name: "custom:gem"}} {
+		if got := registry[blockStateHash(s)]; got != s.Name {
+			t.Errorf("expected %s, got %q", s.Name, got)
+		}
+	}
+}
+
+// TestChunkBlockNames decodes chunks as a server sends them and resolves the cached
+// blocks to names without learning any from placements.
+func TestChunkBlockNames(t *testing.T) {
+	air := blockStateHash(blockState{Name: "minecraft:air"})
+	lava := blockStateHash(blockState{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(0)}})
+
+	tests := []struct {
+		name        string
+		table       []blockState
+		hashed      bool
+		fill, block uint32
+		expected    string
+	}{
+		{"hashed", nil, true, air, lava, "minecraft:lava"},
+		{"sequential", testBlockStates, false, 4, 0, "minecraft:stone"},
+	}
+	for _, tt := range tests {
+		gs := NewGameState()
+		gs.SetBlockStates(tt.table)
+		gs.InitFromGameData(minecraft.GameData{UseBlockNetworkIDHashes: tt.hashed})
+		gs.UpdatePosition(8, 64, 8, 0, 0)
+		gs.SetChunkParsing(true, 2)
+
+		var buf bytes.Buffer
+		encodeTestSubChunk(&buf, 4, tt.fill, tt.block)
+		interceptServerPacket(&packet.LevelChunk{Position: protocol.ChunkPos{0, 0}, SubChunkCount: 1, RawPayload: buf.Bytes()}, gs)
+
+		if name, ok := gs.BlockNameAt(protocol.BlockPos{1, 4*16 + 2, 3}); !ok || name != tt.expected {
+			t.Errorf("%s: expected %s, got %q (ok=%v)", tt.name, tt.expected, name, ok)
+		}
+		if name, _ := gs.BlockNameAt(protocol.BlockPos{0, 4 * 16, 0}); !isAirBlock(name) {
+			t.Errorf("%s: expected air around the block, got %q", tt.name, name)
+		}
+	}
+}
+
+// newChunkTestState returns a game state in a session with hashed block network IDs
+// that knows testBlockStates, with chunk parsing on and the player at (8,66,8).
+func newChunkTestState(t *testing.T) *GameState {
+	t.Helper()
+	gs := NewGameState()
+	gs.SetBlockStates(testBlockStates)
+	gs.InitFromGameData(minecraft.GameData{UseBlockNetworkIDHashes: true})
+	gs.UpdatePosition(8.5, 66+playerEyeHeight, 8.5, 0, 0)
+	gs.SetChunkParsing(true, 2)
+	return gs
+}
+
+// sendTestChunk sends the LevelChunk of chunk (0,0) with one sub-chunk at y 64-79
+// that is air except for blocks.
+func sendTestChunk(gs *GameState, blocks map[protocol.BlockPos]blockState) {
+	palette := []uint32{blockStateHash(blockState{Name: "minecraft:air"})}
+	indices := make([]uint16, 4096)
+	for pos, s := range blocks {
+		palette = append(palette, blockStateHash(s))
+		indices[storageIndex(pos[0], pos[1], pos[2])] = uint16(len(palette) - 1)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(9)
+	buf.WriteByte(1)
+	buf.WriteByte(4)
+	encodeStorage(&buf, 8, palette, indices)
+	interceptServerPacket(&packet.LevelChunk{Position: protocol.ChunkPos{0, 0}, SubChunkCount: 1, RawPayload: buf.Bytes()}, gs)
+}
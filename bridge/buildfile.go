@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// maxBuildFileBlocks caps the number of blocks build_from_file places in one call.
+const maxBuildFileBlocks = 50_000
+
+// BuildFromFileResult summarises a build_from_file run. Unlike place_blocks it
+// reports counts rather than every placed position.
+type BuildFromFileResult struct {
+	File        string            `json:"file"`
+	Origin      BlockCoord        `json:"origin"`
+	Rotation    int               `json:"rotation"`
+	Blocks      int               `json:"blocks"`
+	Placed      int               `json:"placed"`
+	Skipped     int               `json:"skipped"`
+	Failed      []FailedPlacement `json:"failed"`
+	Teleports   int               `json:"teleports"`
+	Interrupted bool              `json:"interrupted,omitempty"`
+	Pacing      PacingReport      `json:"pacing"`
+	ElapsedSecs float64           `json:"elapsed_seconds"`
+}
+
+// loadBuildFile reads the blocks of a build file, with positions relative to the
+// build's origin. CSV and Bedrock .mcstructure files are supported.
+func loadBuildFile(path string) ([]blockPlacement, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".csv", ".mcstructure":
+	case ".schematic", ".schem", ".nbt":
+		return nil, fmt.Errorf("%s files are not supported; export the structure as .mcstructure or convert it to CSV (x,y,z,block_name)", ext)
+	default:
+		return nil, fmt.Errorf("unknown build file type %q (want .csv or .mcstructure)", ext)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if ext == ".mcstructure" {
+		return parseMCStructure(f)
+	}
+	return parseBuildCSV(f)
+}
+
+// parseBuildCSV reads rows of x,y,z,block_name. Blank lines, lines starting with #
+// and a header row whose first column is not a number are ignored. Names without a
+// namespace are taken to be minecraft: blocks.
+func parseBuildCSV(r io.Reader) ([]blockPlacement, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+
+	var blocks []blockPlacement
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var coords [3]int
+		for i := range coords {
+			if coords[i], err = strconv.Atoi(strings.TrimSpace(rec[i])); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			if row == 1 {
+				continue // header
+			}
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("line %d: invalid coordinates %q", line, strings.Join(rec[:3], ","))
+		}
+		name := strings.TrimSpace(rec[3])
+		if name == "" {
+			line, _ := cr.FieldPos(3)
+			return nil, fmt.Errorf("line %d: missing block name", line)
+		}
+		if !strings.Contains(name, ":") {
+			name = "minecraft:" + name
+		}
+		blocks = append(blocks, blockPlacement{X: coords[0], Y: coords[1], Z: coords[2], BlockName: name})
+		if len(blocks) > maxBuildFileBlocks {
+			return nil, fmt.Errorf("build file has more than %d blocks", maxBuildFileBlocks)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("build file has no blocks")
+	}
+	return blocks, nil
+}
+
+// parseMCStructure reads a Bedrock structure file: little-endian NBT holding the
+// structure's size, a palette of block states and, per layer, the palette index of
+// every position in x, y, z order with z varying fastest. Only the first layer is
+// read (the second holds the water of waterlogged blocks); air, structure voids
+// and empty positions (index -1) are left out, and block states are dropped.
+func parseMCStructure(r io.Reader) ([]blockPlacement, error) {
+	var root map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&root); err != nil {
+		return nil, fmt.Errorf("decoding structure: %w", err)
+	}
+	size := nbtInts(root["size"])
+	if len(size) != 3 || size[0] < 0 || size[1] < 0 || size[2] < 0 {
+		return nil, fmt.Errorf("structure has an invalid size %v", size)
+	}
+	structure, _ := root["structure"].(map[string]any)
+	layers, _ := structure["block_indices"].([]any)
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("structure has no block indices")
+	}
+	indices := nbtInts(layers[0])
+	if len(indices) != int(size[0])*int(size[1])*int(size[2]) {
+		return nil, fmt.Errorf("structure has %d block indices for size %v", len(indices), size)
+	}
+	palettes, _ := structure["palette"].(map[string]any)
+	palette, _ := palettes["default"].(map[string]any)
+	states, _ := palette["block_palette"].([]any)
+
+	sz := int(size[2])
+	syz := int(size[1]) * sz
+	var blocks []blockPlacement
+	for i, index := range indices {
+		if index < 0 {
+			continue
+		}
+		if int(index) >= len(states) {
+			return nil, fmt.Errorf("block index %d is outside the palette of %d states", index, len(states))
+		}
+		state, _ := states[index].(map[string]any)
+		name, _ := state["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("palette state %d has no name", index)
+		}
+		if isAirBlock(name) || name == "minecraft:structure_void" {
+			continue
+		}
+		blocks = append(blocks, blockPlacement{X: i / syz, Y: i % syz / sz, Z: i % sz, BlockName: name})
+		if len(blocks) > maxBuildFileBlocks {
+			return nil, fmt.Errorf("build file has more than %d blocks", maxBuildFileBlocks)
+		}
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("build file has no blocks")
+	}
+	return blocks, nil
+}
+
+// nbtInts returns the values of an NBT list of ints decoded into an any. Empty
+// lists decode to []any and give nil.
+func nbtInts(v any) []int32 {
+	ints, _ := v.([]int32)
+	return ints
+}
+
+// layoutBuild turns relative build positions into world positions: rotated
+// clockwise (seen from above) by rotation degrees about the origin, then moved to
+// it. The result is ordered bottom layer first, so every layer can rest on the one
+// below; the file order is kept within a layer. Block states such as stair facing
+// are not rotated.
+func layoutBuild(blocks []blockPlacement, origin BlockCoord, rotation int) ([]blockPlacement, error) {
+	out := make([]blockPlacement, len(blocks))
+	for i, b := range blocks {
+		x, z := b.X, b.Z
+		switch rotation {
+		case 0:
+		case 90:
+			x, z = -z, x
+		case 180:
+			x, z = -x, -z
+		case 270:
+			x, z = z, -x
+		default:
+			return nil, fmt.Errorf("invalid rotation %d (want 0, 90, 180 or 270)", rotation)
+		}
+		b.X, b.Y, b.Z = origin.X+x, origin.Y+b.Y, origin.Z+z
+		out[i] = b
+	}
+	slices.SortStableFunc(out, func(a, b blockPlacement) int { return a.Y - b.Y })
+	return out, nil
+}
+
+// buildSummary condenses the result of placing a build file.
+func buildSummary(file string, origin BlockCoord, rotation, blocks int, r PlaceBlocksResult, elapsed time.Duration) BuildFromFileResult {
+	return BuildFromFileResult{
+		File:        file,
+		Origin:      origin,
+		Rotation:    rotation,
+		Blocks:      blocks,
+		Placed:      len(r.Placed),
+		Skipped:     r.Skipped,
+		Failed:      r.Failed,
+		Teleports:   r.Teleports,
+		Interrupted: r.Interrupted,
+		Pacing:      r.Pacing,
+		ElapsedSecs: elapsed.Seconds(),
+	}
+}
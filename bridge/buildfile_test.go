@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+func TestParseBuildCSV(t *testing.T) {
+	input := `x,y,z,block_name
+# floor
+0,0,0,stone
+
+1, 0, 0, minecraft:oak_planks
+0,1,0,custom:thing
+`
+	blocks, err := parseBuildCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []blockPlacement{
+		{X: 0, Y: 0, Z: 0, BlockName: "minecraft:stone"},
+		{X: 1, Y: 0, Z: 0, BlockName: "minecraft:oak_planks"},
+		{X: 0, Y: 1, Z: 0, BlockName: "custom:thing"},
+	}
+	if len(blocks) != len(expected) {
+		t.Fatalf("expected %d blocks, got %d", len(expected), len(blocks))
+	}
+	for i, b := range blocks {
+		if b != expected[i] {
+			t.Errorf("block %d: expected %+v, got %+v", i, expected[i], b)
+		}
+	}
+}
+
+func TestParseBuildCSV_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "no blocks"},
+		{"x,y,z,block\n", "no blocks"},
+		{"0,0,0,stone\n1,a,0,stone\n", "line 2: invalid coordinates"},
+		{"0,0,0,\n", "missing block name"},
+		{"0,0,0\n", "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		_, err := parseBuildCSV(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestLoadBuildFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hut.csv")
+	if err := os.WriteFile(path, []byte("0,0,0,stone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if blocks, err := loadBuildFile(path); err != nil || len(blocks) != 1 {
+		t.Errorf("expected 1 block, got %v, %v", blocks, err)
+	}
+	if _, err := loadBuildFile(filepath.Join(dir, "hut.schematic")); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+	structure := filepath.Join(dir, "hut.mcstructure")
+	if err := os.WriteFile(structure, encodeMCStructure(t, []int32{1, 1, 1}, []int32{0}, "minecraft:stone"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if blocks, err := loadBuildFile(structure); err != nil || len(blocks) != 1 {
+		t.Errorf("expected 1 block from the structure, got %v, %v", blocks, err)
+	}
+	if _, err := loadBuildFile(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+// encodeMCStructure writes a structure file with the given size, first layer of
+// block indices and palette of block names.
+func encodeMCStructure(t *testing.T, size, indices []int32, names ...string) []byte {
+	t.Helper()
+	palette := make([]any, len(names))
+	for i, name := range names {
+		palette[i] = map[string]any{"name": name, "states": map[string]any{}, "version": int32(18105860)}
+	}
+	data, err := nbt.MarshalEncoding(map[string]any{
+		"format_version": int32(1),
+		"size":           []any{size[0], size[1], size[2]},
+		"structure": map[string]any{
+			"block_indices": []any{int32List(indices), int32List(make([]int32, len(indices)))},
+			"entities":      []any{},
+			"palette": map[string]any{"default": map[string]any{
+				"block_palette":       palette,
+				"block_position_data": map[string]any{},
+			}},
+		},
+		"structure_world_origin": []any{int32(0), int32(0), int32(0)},
+	}, nbt.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// int32List returns ints as a value nbt encodes as a list of ints.
+func int32List(ints []int32) []any {
+	list := make([]any, len(ints))
+	for i, v := range ints {
+		list[i] = v
+	}
+	return list
+}
+
+func TestParseMCStructure(t *testing.T) {
+	// A 2x2x2 structure: indices run x, then y, then z fastest.
+	indices := []int32{
+		1, -1, // x0 y0 z0..1
+		0, 2, // x0 y1
+		1, 1, // x1 y0
+		3, 0, // x1 y1
+	}
+	data := encodeMCStructure(t, []int32{2, 2, 2}, indices, "minecraft:air", "minecraft:stone", "minecraft:oak_planks", "minecraft:structure_void")
+	blocks, err := parseMCStructure(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []blockPlacement{
+		{X: 0, Y: 0, Z: 0, BlockName: "minecraft:stone"},
+		{X: 0, Y: 1, Z: 1, BlockName: "minecraft:oak_planks"},
+		{X: 1, Y: 0, Z: 0, BlockName: "minecraft:stone"},
+		{X: 1, Y: 0, Z: 1, BlockName: "minecraft:stone"},
+	}
+	if len(blocks) != len(expected) {
+		t.Fatalf("expected %d blocks, got %+v", len(expected), blocks)
+	}
+	for i, b := range blocks {
+		if b != expected[i] {
+			t.Errorf("block %d: expected %+v, got %+v", i, expected[i], b)
+		}
+	}
+}
+
+func TestParseMCStructure_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"not nbt", []byte("x,y,z"), "decoding structure"},
+		{"size mismatch", encodeMCStructure(t, []int32{2, 1, 1}, []int32{0}, "minecraft:stone"), "block indices"},
+		{"index outside palette", encodeMCStructure(t, []int32{1, 1, 1}, []int32{1}, "minecraft:stone"), "outside the palette"},
+		{"only air", encodeMCStructure(t, []int32{1, 1, 1}, []int32{0}, "minecraft:air"), "no blocks"},
+	}
+	for _, tt := range tests {
+		_, err := parseMCStructure(bytes.NewReader(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestLayoutBuild(t *testing.T) {
+	blocks := []blockPlacement{
+		{X: 2, Y: 1, Z: 1, BlockName: "top"},
+		{X: 2, Y: 0, Z: 1, BlockName: "a"},
+		{X: 0, Y: 0, Z: 0, BlockName: "b"},
+	}
+	origin := BlockCoord{X: 100, Y: 64, Z: -50}
+	tests := []struct {
+		rotation int
+		expected BlockCoord // of "a"
+	}{
+		{0, BlockCoord{102, 64, -49}},
+		{90, BlockCoord{99, 64, -48}},
+		{180, BlockCoord{98, 64, -51}},
+		{270, BlockCoord{101, 64, -52}},
+	}
+	for _, tt := range tests {
+		out, err := layoutBuild(blocks, origin, tt.rotation)
+		if err != nil {
+			t.Fatalf("rotation %d: unexpected error: %v", tt.rotation, err)
+		}
+		// Bottom layer first, file order within it
+		if out[0].BlockName != "a" || out[1].BlockName != "b" || out[2].BlockName != "top" {
+			t.Errorf("rotation %d: unexpected order %+v", tt.rotation, out)
+		}
+		if got := (BlockCoord{out[0].X, out[0].Y, out[0].Z}); got != tt.expected {
+			t.Errorf("rotation %d: expected %+v, got %+v", tt.rotation, tt.expected, got)
+		}
+		if got := (BlockCoord{out[1].X, out[1].Y, out[1].Z}); got != (BlockCoord{100, 64, -50}) {
+			t.Errorf("rotation %d: expected the origin block at the origin, got %+v", tt.rotation, got)
+		}
+	}
+	if _, err := layoutBuild(blocks, origin, 45); err == nil {
+		t.Error("expected error for rotation 45")
+	}
+}
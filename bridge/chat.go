@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Message types for the chat tool.
+const (
+	chatTypeChat    = "chat"
+	chatTypeWhisper = "whisper"
+	chatTypeMe      = "me"
+)
+
+// validateWhisperTarget checks that target names a player /tell can address. When the
+// player list is known, the target must be online.
+func validateWhisperTarget(state *GameState, target string) error {
+	if err := validateDisplayName(target); err != nil {
+		return fmt.Errorf("invalid whisper target: %w", err)
+	}
+	if strings.ContainsAny(target, "\"\n") {
+		return fmt.Errorf("invalid whisper target %q", target)
+	}
+	players := state.Players()
+	if len(players) == 0 {
+		return nil
+	}
+	for _, p := range players {
+		if strings.EqualFold(p.Username, target) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no online player named %q", target)
+}
+
+// chatCommand returns the chat line that sends msg as the given message type: the
+// message itself for chat, or a /tell or /me command.
+func chatCommand(state *GameState, kind, target, msg string) (string, error) {
+	switch kind {
+	case chatTypeChat:
+		return msg, nil
+	case chatTypeWhisper:
+		if err := validateWhisperTarget(state, target); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("/tell %q %s", target, msg), nil
+	case chatTypeMe:
+		return "/me " + msg, nil
+	}
+	return "", fmt.Errorf("type must be %s, %s or %s", chatTypeChat, chatTypeWhisper, chatTypeMe)
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestChatCommand(t *testing.T) {
+	gs := NewGameState()
+	gs.AddPlayer("1", "Alex Smith")
+
+	tests := []struct {
+		kind, target string
+		want         string
+		wantErr      bool
+	}{
+		{chatTypeChat, "", "hello", false},
+		{chatTypeMe, "", "/me hello", false},
+		{chatTypeWhisper, "Alex Smith", `/tell "Alex Smith" hello`, false},
+		{chatTypeWhisper, "alex smith", `/tell "alex smith" hello`, false},
+		{chatTypeWhisper, "", "", true},
+		{chatTypeWhisper, "Nobody", "", true},
+		{chatTypeWhisper, `A"B`, "", true},
+		{"shout", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := chatCommand(gs, tt.kind, tt.target, "hello")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s %q: expected error %v, got %v", tt.kind, tt.target, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %q: expected %q, got %q", tt.kind, tt.target, tt.want, got)
+		}
+	}
+}
+
+func TestValidateWhisperTarget_UnknownPlayerList(t *testing.T) {
+	gs := NewGameState()
+	if err := validateWhisperTarget(gs, "Steve"); err != nil {
+		t.Errorf("expected any valid name to be accepted without a player list, got %v", err)
+	}
+}
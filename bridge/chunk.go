@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// DefaultChunkRadius is the default radius, in chunks around the player, that is
+// decoded into the block cache when chunk parsing is enabled.
+const DefaultChunkRadius = 4
+
+// palettedStorage is a decoded 16x16x16 paletted storage. Values are looked up by
+// index x<<8 | z<<4 | y. It is used for block layers and 3D biome data alike.
+type palettedStorage struct {
+	palette []uint32
+	indices []uint16
+}
+
+func storageIndex(x, y, z int32) int {
+	return int(x&15)<<8 | int(z&15)<<4 | int(y&15)
+}
+
+// at returns the value stored at the given sub-chunk local coordinates.
+func (s *palettedStorage) at(x, y, z int32) uint32 {
+	return s.palette[s.indices[storageIndex(x, y, z)]]
+}
+
+// set overwrites the value at the given sub-chunk local coordinates.
+func (s *palettedStorage) set(x, y, z int32, v uint32) {
+	for i, p := range s.palette {
+		if p == v {
+			s.indices[storageIndex(x, y, z)] = uint16(i)
+			return
+		}
+	}
+	s.palette = append(s.palette, v)
+	s.indices[storageIndex(x, y, z)] = uint16(len(s.palette) - 1)
+}
+
+// decodePalettedStorage reads one paletted storage in the network encoding. It
+// returns (nil, nil) for the "same as previous" marker used by biome storages.
+func decodePalettedStorage(buf *bytes.Buffer) (*palettedStorage, error) {
+	header, err := buf.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("reading storage header: %w", err)
+	}
+	bitsPerValue := int(header >> 1)
+	if bitsPerValue == 0x7f {
+		return nil, nil
+	}
+	switch bitsPerValue {
+	case 0, 1, 2, 3, 4, 5, 6, 8, 16:
+	default:
+		return nil, fmt.Errorf("invalid bits per value %d", bitsPerValue)
+	}
+
+	s := &palettedStorage{indices: make([]uint16, 4096)}
+	if bitsPerValue > 0 {
+		valuesPerWord := 32 / bitsPerValue
+		wordCount := (4096 + valuesPerWord - 1) / valuesPerWord
+		words := buf.Next(wordCount * 4)
+		if len(words) != wordCount*4 {
+			return nil, fmt.Errorf("storage truncated: want %d words", wordCount)
+		}
+		mask := uint32(1)<<bitsPerValue - 1
+		for i := range s.indices {
+			word := binary.LittleEndian.Uint32(words[(i/valuesPerWord)*4:])
+			s.indices[i] = uint16(word >> ((i % valuesPerWord) * bitsPerValue) & mask)
+		}
+	}
+
+	paletteCount := int32(1)
+	if bitsPerValue > 0 {
+		if err := protocol.Varint32(buf, &paletteCount); err != nil {
+			return nil, fmt.Errorf("reading palette size: %w", err)
+		}
+		if paletteCount <= 0 || paletteCount > 4096 {
+			return nil, fmt.Errorf("invalid palette size %d", paletteCount)
+		}
+	}
+	s.palette = make([]uint32, paletteCount)
+	for i := range s.palette {
+		var v int32
+		if err := protocol.Varint32(buf, &v); err != nil {
+			return nil, fmt.Errorf("reading palette entry: %w", err)
+		}
+		s.palette[i] = uint32(v)
+	}
+	for _, idx := range s.indices {
+		if int(idx) >= len(s.palette) {
+			return nil, fmt.Errorf("palette index %d out of range (%d entries)", idx, len(s.palette))
+		}
+	}
+	return s, nil
+}
+
+// decodeSubChunk reads one serialised sub-chunk and returns its block layer 0.
+// If the encoding carries its own Y index, hasIndex is true and index holds it.
+func decodeSubChunk(buf *bytes.Buffer) (layer *palettedStorage, index int8, hasIndex bool, err error) {
+	version, err := buf.ReadByte()
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("reading sub-chunk version: %w", err)
+	}
+	storages := byte(1)
+	switch version {
+	case 1:
+	case 8, 9:
+		if storages, err = buf.ReadByte(); err != nil {
+			return nil, 0, false, fmt.Errorf("reading storage count: %w", err)
+		}
+		if version == 9 {
+			b, err := buf.ReadByte()
+			if err != nil {
+				return nil, 0, false, fmt.Errorf("reading sub-chunk index: %w", err)
+			}
+			index, hasIndex = int8(b), true
+		}
+	default:
+		return nil, 0, false, fmt.Errorf("unsupported sub-chunk version %d", version)
+	}
+	if storages == 0 {
+		return nil, index, hasIndex, fmt.Errorf("sub-chunk has no storages")
+	}
+	for i := byte(0); i < storages; i++ {
+		s, err := decodePalettedStorage(buf)
+		if err != nil {
+			return nil, index, hasIndex, err
+		}
+		if i == 0 {
+			layer = s
+		}
+	}
+	if layer == nil {
+		return nil, index, hasIndex, fmt.Errorf("sub-chunk layer 0 missing")
+	}
+	return layer, index, hasIndex, nil
+}
+
+// minSubChunkIndex returns the Y index of the lowest sub-chunk in a dimension.
+func minSubChunkIndex(dimension int32) int32 {
+	if dimension == 0 {
+		return -4 // overworld spans y=-64..319
+	}
+	return 0
+}
+
+// chunkInRadius reports whether a chunk column is within radius chunks of the player.
+func chunkInRadius(state *GameState, cx, cz, radius int32) bool {
+	feet := playerFeetBlock(state)
+	dx, dz := cx-(feet[0]>>4), cz-(feet[2]>>4)
+	return dx >= -radius && dx <= radius && dz >= -radius && dz <= radius
+}
+
+// handleLevelChunk decodes the block and biome data of a LevelChunk into the state.
+// Chunks using the blob cache or the sub-chunk request system carry no inline block
+// data; for the latter, the sub-chunks arrive later via SubChunk packets.
+func handleLevelChunk(p *packet.LevelChunk, state *GameState) {
+	enabled, radius := state.ChunkParsing()
+	if !enabled || p.CacheEnabled || !chunkInRadius(state, p.Position[0], p.Position[1], radius) {
+		return
+	}
+	buf := bytes.NewBuffer(p.RawPayload)
+	if p.SubChunkCount == protocol.SubChunkRequestModeLimited || p.SubChunkCount == protocol.SubChunkRequestModeLimitless {
+		// Block data arrives in SubChunk packets; the payload holds only biomes.
+		handleChunkBiomes(buf, p, state, radius)
+		return
+	}
+
+	minIndex := minSubChunkIndex(p.Dimension)
+	for i := uint32(0); i < p.SubChunkCount; i++ {
+		layer, index, hasIndex, err := decodeSubChunk(buf)
+		if err != nil {
+			slog.Debug("level chunk decode failed", "chunk", p.Position, "subchunk", i, "error", err)
+			return
+		}
+		y := minIndex + int32(i)
+		if hasIndex {
+			y = int32(index)
+		}
+		state.BlocksIn(p.Dimension).SetSubChunk(protocol.SubChunkPos{p.Position[0], y, p.Position[1]}, layer)
+	}
+	handleChunkBiomes(buf, p, state, radius)
+}
+
+// handleSubChunk decodes the sub-chunks of a SubChunk response into the block cache.
+// Sub-chunks the server reports as all air are cached as air. Sub-chunks requested
+// by load_area are decoded even when chunk parsing is off or they lie outside the
+// radius, and are pinned in the cache.
+func handleSubChunk(p *packet.SubChunk, state *GameState) {
+	enabled, radius := state.ChunkParsing()
+	if p.CacheEnabled {
+		return
+	}
+	for _, entry := range p.SubChunkEntries {
+		pos := protocol.SubChunkPos{
+			p.Position[0] + int32(entry.Offset[0]),
+			p.Position[1] + int32(entry.Offset[1]),
+			p.Position[2] + int32(entry.Offset[2]),
+		}
+		requested := state.ClaimSubChunk(p.Dimension, pos, entry.Result)
+		if entry.Result != protocol.SubChunkResultSuccess && entry.Result != protocol.SubChunkResultSuccessAllAir {
+			continue
+		}
+		if !requested && (!enabled || !chunkInRadius(state, pos[0], pos[2], radius)) {
+			continue
+		}
+		var layer *palettedStorage
+		if entry.Result == protocol.SubChunkResultSuccessAllAir {
+			air, ok := state.AirRuntimeID()
+			if !ok {
+				continue
+			}
+			layer = &palettedStorage{palette: []uint32{air}, indices: make([]uint16, 4096)}
+		} else {
+			var err error
+			if layer, _, _, err = decodeSubChunk(bytes.NewBuffer(entry.RawPayload)); err != nil {
+				slog.Debug("sub-chunk decode failed", "pos", pos, "error", err)
+				continue
+			}
+		}
+		if requested {
+			state.BlocksIn(p.Dimension).SetPinnedSubChunk(pos, layer)
+		} else {
+			state.BlocksIn(p.Dimension).SetSubChunk(pos, layer)
+		}
+	}
+}
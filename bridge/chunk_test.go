@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// encodeStorage writes a paletted storage in the network encoding.
+func encodeStorage(buf *bytes.Buffer, bitsPerValue int, palette []uint32, indices []uint16) {
+	buf.WriteByte(byte(bitsPerValue<<1 | 1))
+	if bitsPerValue > 0 {
+		valuesPerWord := 32 / bitsPerValue
+		words := make([]uint32, (4096+valuesPerWord-1)/valuesPerWord)
+		for i, idx := range indices {
+			words[i/valuesPerWord] |= uint32(idx) << ((i % valuesPerWord) * bitsPerValue)
+		}
+		for _, w := range words {
+			binary.Write(buf, binary.LittleEndian, w)
+		}
+		protocol.WriteVarint32(buf, int32(len(palette)))
+	}
+	for _, v := range palette {
+		protocol.WriteVarint32(buf, int32(v))
+	}
+}
+
+// encodeTestSubChunk builds a version 9 sub-chunk whose layer 0 is `fill` everywhere
+// except local (1,2,3), which is `marker`.
+func encodeTestSubChunk(buf *bytes.Buffer, index int8, fill, marker uint32) {
+	buf.WriteByte(9)
+	buf.WriteByte(1)
+	buf.WriteByte(byte(index))
+	indices := make([]uint16, 4096)
+	indices[storageIndex(1, 2, 3)] = 1
+	encodeStorage(buf, 1, []uint32{fill, marker}, indices)
+}
+
+func TestDecodePalettedStorage(t *testing.T) {
+	for _, bits := range []int{1, 2, 3, 4, 5, 6, 8, 16} {
+		palette := []uint32{100, 200}
+		indices := make([]uint16, 4096)
+		for i := range indices {
+			indices[i] = uint16(i % 2)
+		}
+		var buf bytes.Buffer
+		encodeStorage(&buf, bits, palette, indices)
+
+		s, err := decodePalettedStorage(&buf)
+		if err != nil {
+			t.Fatalf("bits=%d: unexpected error: %v", bits, err)
+		}
+		if got := s.at(0, 0, 0); got != 100 {
+			t.Errorf("bits=%d: at(0,0,0) = %d, want 100", bits, got)
+		}
+		if got := s.at(0, 1, 0); got != 200 {
+			t.Errorf("bits=%d: at(0,1,0) = %d, want 200", bits, got)
+		}
+	}
+}
+
+func TestDecodePalettedStorage_SingleValue(t *testing.T) {
+	var buf bytes.Buffer
+	encodeStorage(&buf, 0, []uint32{77}, nil)
+	s, err := decodePalettedStorage(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.at(15, 15, 15); got != 77 {
+		t.Errorf("expected 77 everywhere, got %d", got)
+	}
+}
+
+func TestDecodePalettedStorage_Invalid(t *testing.T) {
+	// 7 bits per value is not a valid size
+	if _, err := decodePalettedStorage(bytes.NewBuffer([]byte{7<<1 | 1})); err == nil {
+		t.Error("expected error for invalid bit size")
+	}
+	// Truncated word data
+	if _, err := decodePalettedStorage(bytes.NewBuffer([]byte{1<<1 | 1, 0, 0})); err == nil {
+		t.Error("expected error for truncated storage")
+	}
+}
+
+func TestDecodeSubChunk(t *testing.T) {
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, -2, 1, 5)
+	layer, index, hasIndex, err := decodeSubChunk(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasIndex || index != -2 {
+		t.Errorf("expected index -2, got %d (hasIndex=%v)", index, hasIndex)
+	}
+	if layer.at(1, 2, 3) != 5 || layer.at(0, 0, 0) != 1 {
+		t.Errorf("unexpected layer contents")
+	}
+}
+
+func TestHandleLevelChunk(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(8, 64, 8, 0, 0)
+
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, 4, 1, 5)
+	pk := &packet.LevelChunk{
+		Position:      protocol.ChunkPos{0, 0},
+		SubChunkCount: 1,
+		RawPayload:    buf.Bytes(),
+	}
+
+	// Disabled by default
+	interceptServerPacket(pk, gs)
+	if gs.Blocks().SubChunkCount() != 0 {
+		t.Fatal("expected no chunk parsing when disabled")
+	}
+
+	gs.SetChunkParsing(true, 2)
+	interceptServerPacket(pk, gs)
+	e, ok := gs.Blocks().Get(protocol.BlockPos{1, 4*16 + 2, 3})
+	if !ok || e.RuntimeID != 5 {
+		t.Errorf("expected marker block 5, got %d (ok=%v)", e.RuntimeID, ok)
+	}
+
+	// Chunks outside the radius are skipped
+	far := *pk
+	far.Position = protocol.ChunkPos{10, 10}
+	interceptServerPacket(&far, gs)
+	if _, ok := gs.Blocks().Get(protocol.BlockPos{161, 66, 163}); ok {
+		t.Error("expected chunk outside radius to be skipped")
+	}
+}
+
+func TestHandleSubChunk(t *testing.T) {
+	gs := NewGameState()
+	gs.SetChunkParsing(true, 4)
+
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, 0, 1, 9)
+	pk := &packet.SubChunk{
+		Position: protocol.SubChunkPos{0, 4, 0},
+		SubChunkEntries: []protocol.SubChunkEntry{
+			{Offset: protocol.SubChunkOffset{1, -1, 0}, Result: protocol.SubChunkResultSuccess, RawPayload: buf.Bytes()},
+			{Offset: protocol.SubChunkOffset{0, 0, 0}, Result: protocol.SubChunkResultChunkNotFound},
+		},
+	}
+	interceptServerPacket(pk, gs)
+
+	if gs.Blocks().SubChunkCount() != 1 {
+		t.Fatalf("expected 1 sub-chunk, got %d", gs.Blocks().SubChunkCount())
+	}
+	e, ok := gs.Blocks().Get(protocol.BlockPos{16 + 1, 3*16 + 2, 3})
+	if !ok || e.RuntimeID != 9 {
+		t.Errorf("expected marker block 9, got %d (ok=%v)", e.RuntimeID, ok)
+	}
+}
+
+func TestHandleSubChunk_AllAir(t *testing.T) {
+	gs := newChunkTestState(t)
+	interceptServerPacket(&packet.SubChunk{
+		Position: protocol.SubChunkPos{0, 4, 0},
+		SubChunkEntries: []protocol.SubChunkEntry{
+			{Result: protocol.SubChunkResultSuccessAllAir},
+		},
+	}, gs)
+
+	name, ok := gs.BlockNameAt(protocol.BlockPos{5, 4*16 + 7, 9})
+	if !ok || !isAirBlock(name) {
+		t.Errorf("expected the all-air sub-chunk cached as air, got %q (ok=%v)", name, ok)
+	}
+}
+
+func TestBlockCache_UpdateBlockOverridesSubChunk(t *testing.T) {
+	gs := NewGameState()
+	gs.SetChunkParsing(true, 4)
+
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, 0, 1, 5)
+	layer, _, _, err := decodeSubChunk(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs.Blocks().SetSubChunk(protocol.SubChunkPos{0, 0, 0}, layer)
+
+	interceptServerPacket(&packet.UpdateBlock{Position: protocol.BlockPos{0, 0, 0}, NewBlockRuntimeID: 42}, gs)
+	if e, _ := gs.Blocks().Get(protocol.BlockPos{0, 0, 0}); e.RuntimeID != 42 {
+		t.Errorf("expected updated block 42, got %d", e.RuntimeID)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// CommandTreeEntry is a command with the details only the export includes.
+type CommandTreeEntry struct {
+	CommandInfo
+	PermissionLevel byte   `json:"permission_level"`
+	Flags           uint16 `json:"flags"`
+}
+
+// CommandTree is the full command surface of a Realm, for export. Parameter types
+// naming an enum can be looked up in Enums (fixed values) or SoftEnums (values the
+// server changes at runtime with UpdateSoftEnum).
+type CommandTree struct {
+	ReceivedAt time.Time           `json:"received_at"`
+	Commands   []CommandTreeEntry  `json:"commands"`
+	Enums      map[string][]string `json:"enums"`
+	SoftEnums  map[string][]string `json:"soft_enums"`
+}
+
+// parseCommandTree extracts commands, enums and soft enums from an AvailableCommands
+// packet, resolving enum values, which the packet stores as indices into a shared
+// value list.
+func parseCommandTree(pk *packet.AvailableCommands) CommandTree {
+	tree := CommandTree{
+		ReceivedAt: time.Now(),
+		Enums:      make(map[string][]string, len(pk.Enums)),
+		SoftEnums:  make(map[string][]string, len(pk.DynamicEnums)),
+	}
+	byName := make(map[string]int, len(pk.Commands))
+	for i, c := range pk.Commands {
+		byName[c.Name] = i
+	}
+	for _, info := range parseAvailableCommands(pk) {
+		c := pk.Commands[byName[info.Name]]
+		tree.Commands = append(tree.Commands, CommandTreeEntry{
+			CommandInfo:     info,
+			PermissionLevel: c.PermissionLevel,
+			Flags:           c.Flags,
+		})
+	}
+	for _, e := range pk.Enums {
+		values := make([]string, 0, len(e.ValueIndices))
+		for _, vi := range e.ValueIndices {
+			if int(vi) < len(pk.EnumValues) {
+				values = append(values, pk.EnumValues[vi])
+			}
+		}
+		tree.Enums[e.Type] = values
+	}
+	for _, e := range pk.DynamicEnums {
+		tree.SoftEnums[e.Type] = slices.Clone(e.Values)
+	}
+	return tree
+}
+
+// SetCommandTree replaces the exportable command tree.
+func (gs *GameState) SetCommandTree(tree CommandTree) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.commandTree = tree
+}
+
+// UpdateSoftEnum applies an UpdateSoftEnum action to the command tree's soft enums.
+func (gs *GameState) UpdateSoftEnum(enumType string, options []string, action byte) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.commandTree.SoftEnums == nil {
+		gs.commandTree.SoftEnums = make(map[string][]string)
+	}
+	values := gs.commandTree.SoftEnums[enumType]
+	switch action {
+	case packet.SoftEnumActionAdd:
+		for _, o := range options {
+			if !slices.Contains(values, o) {
+				values = append(values, o)
+			}
+		}
+	case packet.SoftEnumActionRemove:
+		values = slices.DeleteFunc(slices.Clone(values), func(v string) bool { return slices.Contains(options, v) })
+	case packet.SoftEnumActionSet:
+		values = slices.Clone(options)
+	}
+	gs.commandTree.SoftEnums[enumType] = values
+}
+
+// CommandTree returns a copy of the command tree from the last AvailableCommands.
+func (gs *GameState) CommandTree() CommandTree {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	tree := gs.commandTree
+	tree.Commands = slices.Clone(tree.Commands)
+	tree.Enums = maps.Clone(tree.Enums)
+	tree.SoftEnums = maps.Clone(tree.SoftEnums)
+	return tree
+}
+
+// CommandExportResult summarises a command tree written to a file.
+type CommandExportResult struct {
+	Path      string `json:"path"`
+	Commands  int    `json:"commands"`
+	Enums     int    `json:"enums"`
+	SoftEnums int    `json:"soft_enums"`
+	Bytes     int    `json:"bytes"`
+}
+
+// exportCommandTree writes tree to path as indented JSON, replacing the file atomically.
+func exportCommandTree(path string, tree CommandTree) (CommandExportResult, error) {
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return CommandExportResult{}, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return CommandExportResult{}, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return CommandExportResult{}, err
+	}
+	return CommandExportResult{
+		Path:      path,
+		Commands:  len(tree.Commands),
+		Enums:     len(tree.Enums),
+		SoftEnums: len(tree.SoftEnums),
+		Bytes:     len(data),
+	}, nil
+}
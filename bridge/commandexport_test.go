@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestCommandTreeExport(t *testing.T) {
+	gs := NewGameState()
+	interceptServerPacket(&packet.AvailableCommands{
+		EnumValues: []string{"kit", "k", "starter", "pvp"},
+		Enums: []protocol.CommandEnum{
+			{Type: "KitAliases", ValueIndices: []uint32{0, 1}},
+			{Type: "KitName", ValueIndices: []uint32{2, 3}},
+		},
+		DynamicEnums: []protocol.DynamicEnum{
+			{Type: "Warp", Values: []string{"spawn"}},
+		},
+		Commands: []protocol.Command{
+			{
+				Name:            "kit",
+				Description:     "Claim a kit.",
+				PermissionLevel: 1,
+				AliasesOffset:   0,
+				Overloads: []protocol.CommandOverload{{Parameters: []protocol.CommandParameter{
+					{Name: "name", Type: protocol.CommandArgValid | protocol.CommandArgEnum | 1},
+				}}},
+			},
+			{
+				Name:          "warp",
+				AliasesOffset: noCommandAliases,
+				Overloads: []protocol.CommandOverload{{Parameters: []protocol.CommandParameter{
+					{Name: "to", Type: protocol.CommandArgValid | protocol.CommandArgSoftEnum | 0},
+				}}},
+			},
+		},
+	}, gs)
+	interceptServerPacket(&packet.UpdateSoftEnum{EnumType: "Warp", Options: []string{"arena", "shop"}, ActionType: packet.SoftEnumActionAdd}, gs)
+	interceptServerPacket(&packet.UpdateSoftEnum{EnumType: "Warp", Options: []string{"spawn"}, ActionType: packet.SoftEnumActionRemove}, gs)
+
+	path := filepath.Join(t.TempDir(), "commands.json")
+	result, err := exportCommandTree(path, gs.CommandTree())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Commands != 2 || result.Enums != 2 || result.SoftEnums != 1 {
+		t.Errorf("unexpected summary %+v", result)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	var tree CommandTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatalf("parsing export: %v", err)
+	}
+	kit := tree.Commands[0]
+	if kit.Name != "kit" || kit.PermissionLevel != 1 || kit.Overloads[0][0].Type != "KitName" {
+		t.Errorf("unexpected kit entry %+v", kit)
+	}
+	if got := tree.Enums["KitName"]; !slices.Equal(got, []string{"starter", "pvp"}) {
+		t.Errorf("expected KitName [starter pvp], got %v", got)
+	}
+	if got := tree.Commands[1].Overloads[0][0].Type; got != "Warp" {
+		t.Errorf("expected soft enum type Warp, got %s", got)
+	}
+	if got := tree.SoftEnums["Warp"]; !slices.Equal(got, []string{"arena", "shop"}) {
+		t.Errorf("expected Warp [arena shop], got %v", got)
+	}
+}
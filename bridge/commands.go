@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Command send methods for the command tool.
+const (
+	commandMethodChat   = "chat"
+	commandMethodPacket = "packet"
+)
+
+// commandOutputTimeout bounds how long the command tool waits for CommandOutput after
+// sending a CommandRequest packet.
+const commandOutputTimeout = 5 * time.Second
+
+// minCommandResponseWait is the shortest time run_commands waits for a command's
+// response, even when no delay between commands was requested.
+const minCommandResponseWait = 100 * time.Millisecond
+
+// CommandResult reports the outcome of one command executed by run_commands.
+type CommandResult struct {
+	Command string   `json:"command"`
+	Success bool     `json:"success"`
+	Output  []string `json:"output,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// RunCommandsResult summarises a run_commands batch.
+type RunCommandsResult struct {
+	Results   []CommandResult `json:"results"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Skipped   int             `json:"skipped"`
+}
+
+// commandErrorMarkers are substrings of server responses that indicate a command failed.
+var commandErrorMarkers = []string{
+	"§c", // error responses are rendered in red
+	"commands.generic.",
+	"Unknown command",
+	"Syntax error",
+}
+
+// commandOutputFailed reports whether any response line looks like a command error.
+func commandOutputFailed(output []string) bool {
+	for _, line := range output {
+		for _, marker := range commandErrorMarkers {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runCommands sends each command in order, waiting delay after each one and collecting
+// the incoming chat messages received meanwhile as its output. It stops at the first
+// failure unless continueOnError is set; commands not attempted are counted as skipped.
+func runCommands(ctx context.Context, state *GameState, cmds []string, delay time.Duration, continueOnError bool) RunCommandsResult {
+	wait := max(delay, minCommandResponseWait)
+	result := RunCommandsResult{Results: make([]CommandResult, 0, len(cmds))}
+	for i, cmd := range cmds {
+		cmd = strings.TrimPrefix(strings.TrimSpace(cmd), "/")
+		cr := CommandResult{Command: cmd}
+
+		seq := state.ChatSeq()
+		if err := sendCommand(state, cmd); err != nil {
+			cr.Error = err.Error()
+		} else {
+			select {
+			case <-ctx.Done():
+				cr.Error = ctx.Err().Error()
+			case <-time.After(wait):
+			}
+			for _, msg := range state.ChatSince(seq) {
+				if msg.Type == "incoming" {
+					cr.Output = append(cr.Output, msg.Message)
+				}
+			}
+			if cr.Error == "" && commandOutputFailed(cr.Output) {
+				cr.Error = "command reported an error"
+			}
+		}
+		cr.Success = cr.Error == ""
+		result.Results = append(result.Results, cr)
+
+		if cr.Success {
+			result.Succeeded++
+			continue
+		}
+		result.Failed++
+		if !continueOnError || ctx.Err() != nil {
+			result.Skipped = len(cmds) - i - 1
+			break
+		}
+	}
+	return result
+}
+
+// PacketCommandResult is the structured response to a command sent as a CommandRequest.
+type PacketCommandResult struct {
+	Command      string   `json:"command"`
+	Success      bool     `json:"success"`
+	SuccessCount uint32   `json:"success_count"`
+	Output       []string `json:"output"`
+}
+
+// formatCommandOutputMessage renders a command output message with its parameters,
+// e.g. "commands.time.set [1000]".
+func formatCommandOutputMessage(m protocol.CommandOutputMessage) string {
+	if len(m.Parameters) == 0 {
+		return m.Message
+	}
+	return fmt.Sprintf("%s [%s]", m.Message, strings.Join(m.Parameters, ", "))
+}
+
+// newPacketCommandResult converts a CommandOutput packet into a PacketCommandResult.
+func newPacketCommandResult(cmd string, pk *packet.CommandOutput) PacketCommandResult {
+	result := PacketCommandResult{
+		Command:      cmd,
+		Success:      pk.SuccessCount > 0,
+		SuccessCount: pk.SuccessCount,
+		Output:       make([]string, 0, len(pk.OutputMessages)),
+	}
+	for _, m := range pk.OutputMessages {
+		result.Output = append(result.Output, formatCommandOutputMessage(m))
+	}
+	return result
+}
+
+// defaultCommandVersion asks the server to parse the command with its current command
+// version. The field holds a command syntax version, not the network protocol version.
+const defaultCommandVersion = "latest"
+
+// commandRequestOptions are the optional CommandRequest fields.
+type commandRequestOptions struct {
+	// Internal marks the command as internally issued, as command blocks and other
+	// non-player sources do. Servers may relax player-facing checks (such as the chat
+	// command permission prompt) for internal commands; most players want false.
+	Internal bool
+	// Version is the command syntax version; empty means defaultCommandVersion.
+	Version string
+}
+
+// sendCommandRequest executes cmd with a real CommandRequest packet and waits for the
+// matching CommandOutput, correlated by the command origin UUID. Unlike chat commands
+// this gives proper command parsing and a structured result, but some Realms disconnect
+// clients that send CommandRequest, which is why chat remains the default.
+func sendCommandRequest(ctx context.Context, state *GameState, cmd string, opts commandRequestOptions, timeout time.Duration) (PacketCommandResult, error) {
+	conn := state.ServerConn()
+	if conn == nil {
+		return PacketCommandResult{}, fmt.Errorf("server connection not available")
+	}
+	cmd = strings.TrimPrefix(cmd, "/")
+	version := opts.Version
+	if version == "" {
+		version = defaultCommandVersion
+	}
+	id := uuid.New()
+	output := state.AddCommandWaiter(id)
+	defer state.RemoveCommandWaiter(id)
+
+	if err := conn.WritePacket(&packet.CommandRequest{
+		CommandLine: "/" + cmd,
+		CommandOrigin: protocol.CommandOrigin{
+			Origin: protocol.CommandOriginPlayer,
+			UUID:   id,
+		},
+		Internal: opts.Internal,
+		Version:  version,
+	}); err != nil {
+		return PacketCommandResult{}, fmt.Errorf("command error: %w", err)
+	}
+
+	select {
+	case pk := <-output:
+		return newPacketCommandResult(cmd, pk), nil
+	case <-time.After(timeout):
+		return PacketCommandResult{}, fmt.Errorf("no command output received within %s", timeout)
+	case <-ctx.Done():
+		return PacketCommandResult{}, ctx.Err()
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestCommandOutputFailed(t *testing.T) {
+	tests := []struct {
+		output []string
+		want   bool
+	}{
+		{nil, false},
+		{[]string{"Set the time to 1000"}, false},
+		{[]string{"§cUnknown command: foo. Please check that the command exists and that you have permission to use it."}, true},
+		{[]string{"commands.generic.syntax"}, true},
+		{[]string{"ok", "Syntax error: Unexpected \"x\""}, true},
+	}
+	for _, tt := range tests {
+		if got := commandOutputFailed(tt.output); got != tt.want {
+			t.Errorf("commandOutputFailed(%q) = %v, expected %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestRunCommands_StopsOnFirstFailure(t *testing.T) {
+	gs := NewGameState() // no server connection, so every send fails
+	result := runCommands(context.Background(), gs, []string{"/time set day", "weather clear"}, 0, false)
+	if result.Failed != 1 || result.Skipped != 1 || result.Succeeded != 0 {
+		t.Errorf("expected 1 failed and 1 skipped, got %+v", result)
+	}
+	if len(result.Results) != 1 || result.Results[0].Command != "time set day" {
+		t.Errorf("expected single result for 'time set day', got %+v", result.Results)
+	}
+}
+
+func TestRunCommands_ContinueOnError(t *testing.T) {
+	gs := NewGameState()
+	result := runCommands(context.Background(), gs, []string{"a", "b", "c"}, time.Millisecond, true)
+	if result.Failed != 3 || result.Skipped != 0 || len(result.Results) != 3 {
+		t.Errorf("expected 3 failed results, got %+v", result)
+	}
+}
+
+func TestCommandOutput_DeliveredByUUID(t *testing.T) {
+	gs := NewGameState()
+	id := uuid.New()
+	ch := gs.AddCommandWaiter(id)
+
+	// Output for some other command is not delivered to our waiter.
+	interceptServerPacket(&packet.CommandOutput{CommandOrigin: protocol.CommandOrigin{UUID: uuid.New()}}, gs)
+	select {
+	case <-ch:
+		t.Fatal("expected no output for an unrelated UUID")
+	default:
+	}
+
+	interceptServerPacket(&packet.CommandOutput{
+		CommandOrigin: protocol.CommandOrigin{UUID: id},
+		SuccessCount:  1,
+		OutputMessages: []protocol.CommandOutputMessage{
+			{Success: true, Message: "commands.time.set", Parameters: []string{"1000"}},
+		},
+	}, gs)
+	select {
+	case pk := <-ch:
+		result := newPacketCommandResult("time set 1000", pk)
+		if !result.Success || len(result.Output) != 1 || result.Output[0] != "commands.time.set [1000]" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	default:
+		t.Fatal("expected output to be delivered")
+	}
+
+	if gs.DeliverCommandOutput(&packet.CommandOutput{CommandOrigin: protocol.CommandOrigin{UUID: id}}) {
+		t.Error("expected waiter to be removed after delivery")
+	}
+}
+
+func TestSendCommandRequest_NoConnection(t *testing.T) {
+	gs := NewGameState()
+	if _, err := sendCommandRequest(context.Background(), gs, "time set day", commandRequestOptions{}, time.Second); err == nil {
+		t.Error("expected error without a server connection")
+	}
+}
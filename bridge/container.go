@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Container read settings for get_container_contents.
+const (
+	defaultContainerWait  = time.Second
+	maxContainerWait      = 10 * time.Second
+	containerPollInterval = 50 * time.Millisecond
+)
+
+// OpenContainer is the container window the server opened with ContainerOpen. Its
+// contents arrive separately in InventoryContent; Ready is set once they have.
+type OpenContainer struct {
+	WindowID       byte              `json:"window_id"`
+	ContainerType  byte              `json:"container_type"`
+	Position       protocol.BlockPos `json:"position"`
+	EntityUniqueID int64             `json:"entity_unique_id,omitempty"` // for entity containers such as chest boats
+	Ready          bool              `json:"ready"`
+}
+
+// OpenContainerWindow records a container opened by the server. Its contents are not
+// ready until the InventoryContent for the window arrives.
+func (gs *GameState) OpenContainerWindow(p *packet.ContainerOpen) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.container = &OpenContainer{
+		WindowID:       p.WindowID,
+		ContainerType:  p.ContainerType,
+		Position:       p.ContainerPosition,
+		EntityUniqueID: p.ContainerEntityUniqueID,
+	}
+}
+
+// CloseContainerWindow forgets the open container if it has the given window ID.
+func (gs *GameState) CloseContainerWindow(windowID byte) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.container != nil && gs.container.WindowID == windowID {
+		gs.container = nil
+	}
+}
+
+// Container returns the open container. ok is false if none is open.
+func (gs *GameState) Container() (container OpenContainer, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.container == nil {
+		return OpenContainer{}, false
+	}
+	return *gs.container, true
+}
+
+// waitForContainer waits up to timeout for the open container's contents to arrive
+// and returns the container as it is then. ok is false if no container is open.
+func waitForContainer(ctx context.Context, state *GameState, timeout time.Duration) (container OpenContainer, ok bool, err error) {
+	ticker := time.NewTicker(containerPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		container, ok = state.Container()
+		if !ok || container.Ready {
+			return container, ok, nil
+		}
+		select {
+		case <-ctx.Done():
+			return OpenContainer{}, false, ctx.Err()
+		case <-deadline:
+			return container, true, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ContainerSlots returns all slots of a container window, including empty ones.
+func (gs *GameState) ContainerSlots(windowID byte) []InventorySlot {
+	gs.mu.RLock()
+	n := len(gs.inventory[windowID])
+	gs.mu.RUnlock()
+	return gs.WindowSlots(windowID, 0, n-1)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_ContainerReady(t *testing.T) {
+	gs := NewGameState()
+
+	interceptServerPacket(&packet.ContainerOpen{WindowID: 3, ContainerPosition: protocol.BlockPos{1, 64, 2}}, gs)
+	c, ok := gs.Container()
+	if !ok || c.Ready {
+		t.Fatalf("expected an open container that is not ready, got %+v (open %v)", c, ok)
+	}
+
+	// Contents of another window do not make the container ready.
+	interceptServerPacket(&packet.InventoryContent{WindowID: protocol.WindowIDInventory, Content: make([]protocol.ItemInstance, 36)}, gs)
+	if c, _ := gs.Container(); c.Ready {
+		t.Error("expected container not ready after another window's contents")
+	}
+
+	interceptServerPacket(&packet.InventoryContent{WindowID: 3, Content: make([]protocol.ItemInstance, 27)}, gs)
+	if c, _ := gs.Container(); !c.Ready {
+		t.Error("expected container ready after its contents arrived")
+	}
+	if n := len(gs.ContainerSlots(3)); n != 27 {
+		t.Errorf("expected 27 slots, got %d", n)
+	}
+
+	interceptServerPacket(&packet.ContainerClose{WindowID: 3}, gs)
+	if _, ok := gs.Container(); ok {
+		t.Error("expected no open container after ContainerClose")
+	}
+}
+
+func TestWaitForContainer(t *testing.T) {
+	gs := NewGameState()
+	if _, ok, _ := waitForContainer(context.Background(), gs, time.Second); ok {
+		t.Error("expected no container")
+	}
+
+	interceptServerPacket(&packet.ContainerOpen{WindowID: 5}, gs)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		interceptServerPacket(&packet.InventoryContent{WindowID: 5, Content: make([]protocol.ItemInstance, 5)}, gs)
+	}()
+	c, ok, err := waitForContainer(context.Background(), gs, time.Second)
+	if err != nil || !ok || !c.Ready {
+		t.Errorf("expected ready container, got %+v (open %v, err %v)", c, ok, err)
+	}
+
+	interceptServerPacket(&packet.ContainerOpen{WindowID: 6}, gs)
+	if c, ok, _ := waitForContainer(context.Background(), gs, 20*time.Millisecond); !ok || c.Ready {
+		t.Errorf("expected container not ready after timeout, got %+v", c)
+	}
+}
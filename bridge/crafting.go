@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// createdOutputSlot is the slot of the created output container that crafting results
+// appear in before being placed into the inventory.
+const createdOutputSlot = 50
+
+// maxCreativeCraftCount is the largest stack the craft tool creates in one request.
+const maxCreativeCraftCount = 64
+
+// RecipeInfo describes a crafting recipe received in CraftingData.
+type RecipeInfo struct {
+	NetworkID   uint32 `json:"network_id"`
+	RecipeID    string `json:"recipe_id"`
+	Block       string `json:"block"` // crafting block, e.g. "crafting_table"
+	Output      string `json:"output"`
+	OutputCount int    `json:"output_count"`
+}
+
+// recipeInfo extracts the crafting table recipes that can be crafted with a
+// CraftRecipeStackRequestAction. Must be called with at least a read lock held.
+func (gs *GameState) recipeInfo(r protocol.Recipe) (RecipeInfo, bool) {
+	var info RecipeInfo
+	var output []protocol.ItemStack
+	switch r := r.(type) {
+	case *protocol.ShapedRecipe:
+		info = RecipeInfo{NetworkID: r.RecipeNetworkID, RecipeID: r.RecipeID, Block: r.Block}
+		output = r.Output
+	case *protocol.ShapelessRecipe:
+		info = RecipeInfo{NetworkID: r.RecipeNetworkID, RecipeID: r.RecipeID, Block: r.Block}
+		output = r.Output
+	default:
+		return RecipeInfo{}, false
+	}
+	if len(output) > 0 {
+		info.Output = gs.resolveItemName(output[0].NetworkID)
+		info.OutputCount = int(output[0].Count)
+	}
+	return info, true
+}
+
+// CraftResult reports the outcome of a craft request.
+type CraftResult struct {
+	Item   string `json:"item"`
+	Count  int    `json:"count"`
+	Slot   int    `json:"slot"`
+	Method string `json:"method"` // "creative" or "recipe"
+}
+
+// craftDestination resolves the destination slot in the combined hotbar and inventory,
+// picking the first empty slot when slot is negative.
+func craftDestination(state *GameState, slot int) (int, error) {
+	if slot < 0 {
+		free, ok := state.FirstEmptySlot(protocol.WindowIDInventory, 0, inventoryWindows["inventory"].LastSlot)
+		if !ok {
+			return 0, fmt.Errorf("inventory is full")
+		}
+		return free, nil
+	}
+	if slot > inventoryWindows["inventory"].LastSlot {
+		return 0, fmt.Errorf("slot %d outside inventory slots 0-%d", slot, inventoryWindows["inventory"].LastSlot)
+	}
+	if _, full := state.InventoryItem(protocol.WindowIDInventory, slot); full {
+		return 0, fmt.Errorf("slot %d is not empty", slot)
+	}
+	return slot, nil
+}
+
+// createdOutput returns the slot info of a crafting request's output.
+func createdOutput(requestID int32) protocol.StackRequestSlotInfo {
+	return protocol.StackRequestSlotInfo{
+		Container:      protocol.FullContainerName{ContainerID: protocol.ContainerCreatedOutput},
+		Slot:           createdOutputSlot,
+		StackNetworkID: requestID,
+	}
+}
+
+// errNoCreativeContent explains why creative crafting is impossible when the server
+// never sent its creative inventory.
+var errNoCreativeContent = errors.New("no creative inventory received from the Realm: the player is probably not in creative mode, or the Realm does not send creative content. Switch with the command tool ('gamemode creative') and reconnect, or craft with a recipe instead")
+
+// craftCreative takes count of a creative inventory item into an inventory slot.
+func craftCreative(ctx context.Context, state *GameState, item string, count, slot int) (CraftResult, error) {
+	if state.CreativeItemCount() == 0 {
+		return CraftResult{}, errNoCreativeContent
+	}
+	creativeID, ok := state.CreativeItemID(item)
+	if !ok {
+		return CraftResult{}, fmt.Errorf("item %q is not in the creative inventory", item)
+	}
+	networkID, ok := state.ResolveItemNetworkID(item)
+	if !ok {
+		return CraftResult{}, fmt.Errorf("unknown item %q", item)
+	}
+	if count <= 0 || count > maxCreativeCraftCount {
+		return CraftResult{}, fmt.Errorf("count must be between 1 and %d", maxCreativeCraftCount)
+	}
+	slot, err := craftDestination(state, slot)
+	if err != nil {
+		return CraftResult{}, err
+	}
+
+	dst := playerInventoryWindow(slot)
+	resp, err := sendItemStackRequest(ctx, state, func(requestID int32) []protocol.StackRequestAction {
+		return []protocol.StackRequestAction{
+			&protocol.CraftCreativeStackRequestAction{CreativeItemNetworkID: creativeID, NumberOfCrafts: 1},
+			newPlaceAction(byte(count), createdOutput(requestID), slotInfo(dst, slot, protocol.ItemInstance{})),
+		}
+	})
+	if err != nil {
+		return CraftResult{}, err
+	}
+	state.UpdateInventorySlot(protocol.WindowIDInventory, slot, protocol.ItemInstance{
+		Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: networkID}, Count: uint16(count)},
+	})
+	state.ApplyItemStackResponse(resp)
+	return CraftResult{Item: item, Count: count, Slot: slot, Method: "creative"}, nil
+}
+
+// craftRecipe crafts a recipe from the ingredients already placed in the 2x2 crafting
+// grid (see move_item), consuming one of each ingredient per craft and placing the
+// result into an inventory slot.
+func craftRecipe(ctx context.Context, state *GameState, key string, crafts, slot int) (CraftResult, error) {
+	recipe, ok := state.FindRecipe(key)
+	if !ok {
+		return CraftResult{}, fmt.Errorf("unknown recipe %q", key)
+	}
+	if crafts <= 0 || recipe.OutputCount*crafts > maxCreativeCraftCount {
+		return CraftResult{}, fmt.Errorf("crafts must be positive and produce at most %d items", maxCreativeCraftCount)
+	}
+	slot, err := craftDestination(state, slot)
+	if err != nil {
+		return CraftResult{}, err
+	}
+
+	grid := inventoryWindows["crafting"]
+	var consume []protocol.StackRequestAction
+	for i := grid.FirstSlot; i <= grid.LastSlot; i++ {
+		item, ok := state.InventoryItem(grid.WindowID, i)
+		if !ok {
+			continue
+		}
+		if int(item.Stack.Count) < crafts {
+			return CraftResult{}, fmt.Errorf("crafting slot %d holds only %d items", i, item.Stack.Count)
+		}
+		a := &protocol.ConsumeStackRequestAction{}
+		a.Count, a.Source = byte(crafts), slotInfo(grid, i, item)
+		consume = append(consume, a)
+	}
+	if len(consume) == 0 {
+		return CraftResult{}, fmt.Errorf("crafting grid is empty; move the ingredients there first")
+	}
+
+	count := recipe.OutputCount * crafts
+	dst := playerInventoryWindow(slot)
+	resp, err := sendItemStackRequest(ctx, state, func(requestID int32) []protocol.StackRequestAction {
+		actions := []protocol.StackRequestAction{
+			&protocol.CraftRecipeStackRequestAction{RecipeNetworkID: recipe.NetworkID, NumberOfCrafts: byte(crafts)},
+		}
+		actions = append(actions, consume...)
+		return append(actions, newPlaceAction(byte(count), createdOutput(requestID), slotInfo(dst, slot, protocol.ItemInstance{})))
+	})
+	if err != nil {
+		return CraftResult{}, err
+	}
+	if networkID, ok := state.ResolveItemNetworkID(recipe.Output); ok {
+		state.UpdateInventorySlot(protocol.WindowIDInventory, slot, protocol.ItemInstance{
+			Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: networkID}, Count: uint16(count)},
+		})
+	}
+	state.ApplyItemStackResponse(resp)
+	return CraftResult{Item: recipe.Output, Count: count, Slot: slot, Method: "recipe"}, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestCreativeContent_IndexesByName(t *testing.T) {
+	gs := NewGameState()
+	gs.mu.Lock()
+	gs.itemRegistry[5] = "minecraft:stone"
+	gs.mu.Unlock()
+
+	interceptServerPacket(&packet.CreativeContent{Items: []protocol.CreativeItem{
+		{CreativeItemNetworkID: 10, Item: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 5}}},
+		{CreativeItemNetworkID: 11, Item: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 5}}},
+	}}, gs)
+
+	id, ok := gs.CreativeItemID("minecraft:stone")
+	if !ok || id != 10 {
+		t.Errorf("expected creative ID 10 for stone, got %d (ok=%v)", id, ok)
+	}
+}
+
+func TestCraftingData_FindRecipe(t *testing.T) {
+	gs := NewGameState()
+	gs.mu.Lock()
+	gs.itemRegistry[20] = "minecraft:stick"
+	gs.mu.Unlock()
+
+	interceptServerPacket(&packet.CraftingData{Recipes: []protocol.Recipe{
+		&protocol.ShapedRecipe{
+			RecipeID:        "minecraft:stick",
+			Block:           "crafting_table",
+			RecipeNetworkID: 7,
+			Output:          []protocol.ItemStack{{ItemType: protocol.ItemType{NetworkID: 20}, Count: 4}},
+		},
+		&protocol.FurnaceRecipe{},
+	}}, gs)
+
+	for _, key := range []string{"minecraft:stick", "7"} {
+		r, ok := gs.FindRecipe(key)
+		if !ok || r.NetworkID != 7 || r.Output != "minecraft:stick" || r.OutputCount != 4 {
+			t.Errorf("FindRecipe(%q) = %+v (ok=%v)", key, r, ok)
+		}
+	}
+	if _, ok := gs.FindRecipe("minecraft:missing"); ok {
+		t.Error("expected unknown recipe to be missing")
+	}
+}
+
+func TestCraftDestination(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 0, stackOf(5, 1, 1))
+
+	if slot, err := craftDestination(gs, -1); err != nil || slot != 1 {
+		t.Errorf("expected first empty slot 1, got %d (err=%v)", slot, err)
+	}
+	if _, err := craftDestination(gs, 0); err == nil {
+		t.Error("expected error for an occupied slot")
+	}
+	if _, err := craftDestination(gs, 36); err == nil {
+		t.Error("expected error for an out of range slot")
+	}
+}
+
+func TestCraftRecipe_EmptyGrid(t *testing.T) {
+	gs := NewGameState()
+	gs.AddRecipes([]protocol.Recipe{&protocol.ShapelessRecipe{RecipeID: "minecraft:planks", RecipeNetworkID: 3}}, false)
+	_, err := craftRecipe(context.Background(), gs, "minecraft:planks", 1, -1)
+	if err == nil || !strings.Contains(err.Error(), "crafting grid is empty") {
+		t.Errorf("expected empty grid error, got %v", err)
+	}
+}
+
+func TestCraftCreative_NoCreativeContent(t *testing.T) {
+	gs := NewGameState()
+	_, err := craftCreative(context.Background(), gs, "minecraft:stone", 1, -1)
+	if !errors.Is(err, errNoCreativeContent) {
+		t.Errorf("expected errNoCreativeContent, got %v", err)
+	}
+}
+
+func TestCraftCreative_UnknownItem(t *testing.T) {
+	gs := NewGameState()
+	gs.mu.Lock()
+	gs.creativeItems["minecraft:dirt"] = 1
+	gs.mu.Unlock()
+	_, err := craftCreative(context.Background(), gs, "minecraft:stone", 1, -1)
+	if err == nil || !strings.Contains(err.Error(), "not in the creative inventory") {
+		t.Errorf("expected creative inventory error, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// playerEyeHeight is the offset between the position reported in movement packets
+// (eye level) and the player's feet.
+const playerEyeHeight = 1.62
+
+// digDirections maps dig_column directions to block offsets.
+var digDirections = map[string]protocol.BlockPos{
+	"down":  {0, -1, 0},
+	"north": {0, 0, -1},
+	"south": {0, 0, 1},
+	"east":  {1, 0, 0},
+	"west":  {-1, 0, 0},
+}
+
+// Reasons reported by dig_column for stopping.
+const (
+	digStopDepthReached = "depth_reached"
+	digStopBedrock      = "bedrock"
+	digStopLava         = "lava"
+	digStopInterrupted  = "interrupted"
+	digStopError        = "error"
+)
+
+// DigResult summarises a dig_column run.
+type DigResult struct {
+	Steps       int               `json:"steps"`
+	BlocksMined int               `json:"blocks_mined"`
+	StoppedBy   string            `json:"stopped_by"`
+	StoppedAt   protocol.BlockPos `json:"stopped_at"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// playerFeetBlock returns the block position the player is standing in.
+func playerFeetBlock(state *GameState) protocol.BlockPos {
+	x, y, z, _, _, _ := state.Position()
+	return protocol.BlockPos{
+		int32(math.Floor(float64(x))),
+		int32(math.Floor(float64(y) - playerEyeHeight + 0.01)),
+		int32(math.Floor(float64(z))),
+	}
+}
+
+func addBlockPos(a, b protocol.BlockPos) protocol.BlockPos {
+	return protocol.BlockPos{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func isAirBlock(name string) bool {
+	return name == "minecraft:air" || strings.HasSuffix(name, "_air")
+}
+
+func isLavaBlock(name string) bool {
+	return strings.Contains(name, "lava")
+}
+
+func isBedrockBlock(name string) bool {
+	return name == "minecraft:bedrock"
+}
+
+// digTargets returns the blocks that must be cleared to step from feet in direction dir:
+// the block below for "down", or the feet and head blocks in front otherwise.
+func digTargets(feet protocol.BlockPos, dir string) []protocol.BlockPos {
+	next := addBlockPos(feet, digDirections[dir])
+	if dir == "down" {
+		return []protocol.BlockPos{next}
+	}
+	return []protocol.BlockPos{next, addBlockPos(next, protocol.BlockPos{0, 1, 0})}
+}
+
+// digHazard checks the block cache around the next step for bedrock or lava.
+// Positions not in the cache are assumed safe.
+func digHazard(blocks *blockNameView, targets []protocol.BlockPos) string {
+	for _, t := range targets {
+		name, ok := blocks.at(t)
+		if !ok {
+			continue
+		}
+		if isBedrockBlock(name) {
+			return digStopBedrock
+		}
+		if isLavaBlock(name) {
+			return digStopLava
+		}
+	}
+	// Lava flowing into the shaft from any side of the space we step into
+	for _, t := range targets {
+		for _, off := range []protocol.BlockPos{{1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}, {0, -1, 0}, {0, 1, 0}} {
+			if name, ok := blocks.at(addBlockPos(t, off)); ok && isLavaBlock(name) {
+				return digStopLava
+			}
+		}
+	}
+	return ""
+}
+
+// digColumn repeatedly breaks the block(s) in the given direction and teleports the
+// player into the cleared space until depth steps are taken or a hazard is found.
+func digColumn(ctx context.Context, conn *minecraft.Conn, state *GameState, dir string, depth int, delay time.Duration) DigResult {
+	var result DigResult
+	feet := playerFeetBlock(state)
+	result.StoppedAt = feet
+
+	for result.Steps < depth {
+		select {
+		case <-ctx.Done():
+			result.StoppedBy = digStopInterrupted
+			return result
+		default:
+		}
+
+		targets := digTargets(feet, dir)
+		blocks := state.BlockNames()
+		if hazard := digHazard(blocks, targets); hazard != "" {
+			result.StoppedBy = hazard
+			return result
+		}
+
+		for _, t := range targets {
+			if name, ok := blocks.at(t); ok && isAirBlock(name) {
+				continue
+			}
+			if err := breakBlock(conn, state, t); err != nil {
+				result.StoppedBy = digStopError
+				result.Error = err.Error()
+				return result
+			}
+			result.BlocksMined++
+		}
+
+		feet = targets[0]
+		if err := sendCommand(state, fmt.Sprintf("tp @s %.1f %d %.1f", float32(feet[0])+0.5, feet[1], float32(feet[2])+0.5)); err != nil {
+			result.StoppedBy = digStopError
+			result.Error = err.Error()
+			return result
+		}
+		result.Steps++
+		result.StoppedAt = feet
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				result.StoppedBy = digStopInterrupted
+				return result
+			}
+		}
+	}
+
+	result.StoppedBy = digStopDepthReached
+	return result
+}
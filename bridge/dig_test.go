@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestPlayerFeetBlock(t *testing.T) {
+	gs := NewGameState()
+	// Eye position of a player standing on top of a block at y=63
+	gs.UpdatePosition(10.5, 64+playerEyeHeight, -3.5, 0, 0)
+	got := playerFeetBlock(gs)
+	want := protocol.BlockPos{10, 64, -4}
+	if got != want {
+		t.Errorf("playerFeetBlock = %v, want %v", got, want)
+	}
+}
+
+func TestDigTargets(t *testing.T) {
+	feet := protocol.BlockPos{0, 64, 0}
+
+	down := digTargets(feet, "down")
+	if len(down) != 1 || down[0] != (protocol.BlockPos{0, 63, 0}) {
+		t.Errorf("down targets = %v", down)
+	}
+
+	east := digTargets(feet, "east")
+	if len(east) != 2 || east[0] != (protocol.BlockPos{1, 64, 0}) || east[1] != (protocol.BlockPos{1, 65, 0}) {
+		t.Errorf("east targets = %v", east)
+	}
+}
+
+func TestDigHazard(t *testing.T) {
+	gs := newChunkTestState(t)
+	stone := blockState{Name: "minecraft:stone"}
+	bedrock := blockState{Name: "minecraft:bedrock", Properties: map[string]any{"infiniburn_bit": byte(0)}}
+	lava := blockState{Name: "minecraft:flowing_lava", Properties: map[string]any{"liquid_depth": int32(2)}}
+
+	// Unknown positions are safe
+	if h := digHazard(gs.BlockNames(), []protocol.BlockPos{{8, 10, 8}}); h != "" {
+		t.Errorf("expected no hazard for uncached block, got %q", h)
+	}
+
+	target := []protocol.BlockPos{{8, 70, 8}}
+	tests := []struct {
+		blocks   map[protocol.BlockPos]blockState
+		expected string
+	}{
+		{map[protocol.BlockPos]blockState{target[0]: stone}, ""},
+		{map[protocol.BlockPos]blockState{target[0]: bedrock}, digStopBedrock},
+		{map[protocol.BlockPos]blockState{target[0]: lava}, digStopLava},
+		// Lava next to the space we'd step into
+		{map[protocol.BlockPos]blockState{target[0]: stone, {9, 70, 8}: lava}, digStopLava},
+	}
+	for i, tt := range tests {
+		sendTestChunk(gs, tt.blocks)
+		if h := digHazard(gs.BlockNames(), target); h != tt.expected {
+			t.Errorf("case %d: expected %q, got %q", i, tt.expected, h)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// defaultEmoteTicks is how long an emote plays when no length is given.
+const defaultEmoteTicks = 60
+
+// builtinEmotes maps names of emotes every player owns to their emote UUIDs.
+var builtinEmotes = map[string]string{
+	"wave":       "4c8ae710-df2e-47cd-814d-cc7bf21a3d67",
+	"clap":       "9a469a61-c83b-4ba9-b507-bdbe64430582",
+	"over_there": "ce5c0300-7f03-455d-aaf1-352e4927b54d",
+}
+
+// builtinEmoteNames returns the names of the built-in emotes, sorted.
+func builtinEmoteNames() []string {
+	names := make([]string, 0, len(builtinEmotes))
+	for name := range builtinEmotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveEmoteID returns the emote UUID for a built-in emote name or a UUID in
+// canonical 8-4-4-4-12 form, normalised to lower case.
+func resolveEmoteID(emote string) (string, error) {
+	if id, ok := builtinEmotes[strings.ToLower(emote)]; ok {
+		return id, nil
+	}
+	id, err := uuid.Parse(emote)
+	if err != nil || len(emote) != 36 {
+		return "", fmt.Errorf("emote must be a UUID like 4c8ae710-df2e-47cd-814d-cc7bf21a3d67 or one of %s", strings.Join(builtinEmoteNames(), ", "))
+	}
+	return id.String(), nil
+}
+
+// sendEmote plays an emote as the connected player. Every field of the packet is
+// filled in, including the length, XUID and platform ID that older protocol
+// versions lacked, so servers on either side of those changes accept it.
+func sendEmote(state *GameState, emoteID string, ticks uint32) error {
+	conn := state.ServerConn()
+	if conn == nil {
+		return fmt.Errorf("server connection not available")
+	}
+	_, xuid := state.Identity()
+	return conn.WritePacket(&packet.Emote{
+		EntityRuntimeID: state.EntityID(),
+		EmoteLength:     ticks,
+		EmoteID:         emoteID,
+		XUID:            xuid,
+		PlatformID:      "",
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestResolveEmoteID(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"wave", "4c8ae710-df2e-47cd-814d-cc7bf21a3d67", false},
+		{"Clap", "9a469a61-c83b-4ba9-b507-bdbe64430582", false},
+		{"D0C60245-538E-4EA4-9B6F-4DDD6F6D3C1B", "d0c60245-538e-4ea4-9b6f-4ddd6f6d3c1b", false},
+		{"{d0c60245-538e-4ea4-9b6f-4ddd6f6d3c1b}", "", true},
+		{"d0c60245538e4ea49b6f4ddd6f6d3c1b", "", true},
+		{"dance", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveEmoteID(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: expected error=%v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// attrHealth is the attribute carrying an entity's health in AddActor and
+// UpdateAttributes.
+const attrHealth = "minecraft:health"
+
+// healthFromAttributes returns the health attribute from a list of attribute values.
+func healthFromAttributes(attrs []protocol.AttributeValue) (float32, bool) {
+	for _, attr := range attrs {
+		if attr.Name == attrHealth {
+			return attr.Value, true
+		}
+	}
+	return 0, false
+}
+
+// healthFromMetadata returns the health carried in entity metadata. Mobs report health
+// through attributes; the metadata health key is used by entities without attributes,
+// such as boats and minecarts.
+func healthFromMetadata(metadata map[uint32]any) (float32, bool) {
+	switch v := metadata[protocol.EntityDataKeyStructuralIntegrity].(type) {
+	case int32:
+		return float32(v), true
+	case float32:
+		return v, true
+	}
+	return 0, false
+}
+
+// UpdateEntityHealth records the health of a tracked entity. Health for untracked
+// entities is ignored.
+func (gs *GameState) UpdateEntityHealth(runtimeID uint64, health float32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if e, ok := gs.entities[runtimeID]; ok {
+		e.Health = &health
+		gs.entities[runtimeID] = e
+	}
+}
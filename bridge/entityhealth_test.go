@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_SetActorData_Health(t *testing.T) {
+	gs := NewGameState()
+	gs.AddEntity(700, "minecraft:boat", mgl32.Vec3{0, 64, 0})
+	if gs.Entities()[0].Health != nil {
+		t.Fatal("expected no health before the server reports it")
+	}
+
+	interceptServerPacket(&packet.SetActorData{
+		EntityRuntimeID: 700,
+		EntityMetadata:  map[uint32]any{protocol.EntityDataKeyStructuralIntegrity: int32(4)},
+	}, gs)
+	// Metadata for an untracked entity is ignored.
+	interceptServerPacket(&packet.SetActorData{
+		EntityRuntimeID: 701,
+		EntityMetadata:  map[uint32]any{protocol.EntityDataKeyStructuralIntegrity: int32(1)},
+	}, gs)
+
+	entities := gs.Entities()
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(entities))
+	}
+	if h := entities[0].Health; h == nil || *h != 4 {
+		t.Errorf("expected health 4, got %v", h)
+	}
+}
+
+func TestIntercept_EntityHealthAttributes(t *testing.T) {
+	gs := NewGameState()
+	gs.SetIdentity("Steve", "123", 42)
+	gs.SetHealth(20)
+
+	interceptServerPacket(&packet.AddActor{
+		EntityRuntimeID: 99,
+		EntityType:      "minecraft:zombie",
+		Attributes:      []protocol.AttributeValue{{Name: attrHealth, Value: 20}},
+	}, gs)
+	if h := gs.Entities()[0].Health; h == nil || *h != 20 {
+		t.Errorf("expected spawn health 20, got %v", h)
+	}
+
+	interceptServerPacket(&packet.UpdateAttributes{
+		EntityRuntimeID: 99,
+		Attributes: []protocol.Attribute{
+			{AttributeValue: protocol.AttributeValue{Name: attrHealth, Value: 7}},
+		},
+	}, gs)
+	if h := gs.Entities()[0].Health; h == nil || *h != 7 {
+		t.Errorf("expected health 7, got %v", h)
+	}
+	if _, _, _, health, _ := gs.WorldInfo(); health != 20 {
+		t.Errorf("expected player health unchanged at 20, got %v", health)
+	}
+}
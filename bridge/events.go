@@ -0,0 +1,79 @@
+package main
+
+import (
+	"time"
+)
+
+// maxEvents is the number of events kept in the event log.
+const maxEvents = 200
+
+// Event types recorded in the event log.
+const (
+	EventJoined           = "joined"
+	EventDied             = "died"
+	EventPlayerJoined     = "player_joined"
+	EventPlayerLeft       = "player_left"
+	EventDimensionChanged = "dimension_changed"
+	EventDisconnected     = "disconnected"
+	EventTransferred      = "transferred"
+	EventReconnecting     = "reconnecting"
+	EventReconnected      = "reconnected"
+	EventReconnectFailed  = "reconnect_failed"
+)
+
+// Event is one entry of the session timeline. Seq increases by one per event, so a
+// caller can ask for the events after the last one it saw.
+type Event struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+}
+
+// RecordEvent appends an event to the event log.
+func (gs *GameState) RecordEvent(eventType, message string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.recordEventLocked(eventType, message)
+}
+
+// recordEventLocked is RecordEvent without locking. Callers must hold gs.mu.
+func (gs *GameState) recordEventLocked(eventType, message string) {
+	gs.eventSeq++
+	gs.events = append(gs.events, Event{
+		Seq:     gs.eventSeq,
+		Time:    time.Now(),
+		Type:    eventType,
+		Message: message,
+	})
+	if len(gs.events) > maxEvents {
+		gs.events = gs.events[len(gs.events)-maxEvents:]
+	}
+}
+
+// Events returns up to limit of the most recent events with a sequence number above
+// after, oldest first. A limit of 0 or less returns all of them.
+func (gs *GameState) Events(after uint64, limit int) []Event {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	result := make([]Event, 0, len(gs.events))
+	for _, e := range gs.events {
+		if e.Seq > after {
+			result = append(result, e)
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// setHealthLocked updates the player's health, recording a death when it drops to
+// zero. Callers must hold gs.mu.
+func (gs *GameState) setHealthLocked(h float32) {
+	if h <= 0 && gs.health > 0 {
+		gs.recordEventLocked(EventDied, "the player died")
+	}
+	gs.health = h
+	gs.notifyHealthLocked(h)
+}
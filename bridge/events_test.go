@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func eventTypes(events []Event) []string {
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestIntercept_Events(t *testing.T) {
+	gs := NewGameState()
+	gs.SetIdentity("Steve", "123", 42)
+	alex := uuid.New()
+
+	interceptServerPacket(&packet.PlayerList{
+		ActionType: packet.PlayerListActionAdd,
+		Entries:    []protocol.PlayerListEntry{{UUID: alex, XUID: "x1", Username: "Alex"}},
+	}, gs)
+	// Re-announcing a known player is not a join.
+	interceptServerPacket(&packet.PlayerList{
+		ActionType: packet.PlayerListActionAdd,
+		Entries:    []protocol.PlayerListEntry{{UUID: alex, XUID: "x1", Username: "Alex"}},
+	}, gs)
+	interceptServerPacket(&packet.SetHealth{Health: 20}, gs)
+	interceptServerPacket(&packet.SetHealth{Health: 0}, gs)
+	interceptServerPacket(&packet.UpdateAttributes{
+		EntityRuntimeID: 42,
+		Attributes:      []protocol.Attribute{{AttributeValue: protocol.AttributeValue{Name: "minecraft:health", Value: 0}}},
+	}, gs)
+	interceptServerPacket(&packet.ChangeDimension{Dimension: 1}, gs)
+	// Removal entries carry only the UUID.
+	interceptServerPacket(&packet.PlayerList{
+		ActionType: packet.PlayerListActionRemove,
+		Entries:    []protocol.PlayerListEntry{{UUID: alex}},
+	}, gs)
+	interceptServerPacket(&packet.Disconnect{Message: "kicked"}, gs)
+
+	events := gs.Events(0, 0)
+	want := []string{EventPlayerJoined, EventDied, EventDimensionChanged, EventPlayerLeft, EventDisconnected}
+	if got := eventTypes(events); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	if events[0].Message != "Alex joined" || events[3].Message != "Alex left" {
+		t.Errorf("expected player names in messages, got %q and %q", events[0].Message, events[3].Message)
+	}
+	if events[2].Message != "moved to nether" {
+		t.Errorf("expected nether in message, got %q", events[2].Message)
+	}
+	if len(gs.Players()) != 0 {
+		t.Errorf("expected Alex removed from the player list, got %v", gs.Players())
+	}
+}
+
+func TestEvents_AfterAndLimit(t *testing.T) {
+	gs := NewGameState()
+	for i := 0; i < maxEvents+10; i++ {
+		gs.RecordEvent(EventJoined, fmt.Sprintf("event %d", i))
+	}
+
+	all := gs.Events(0, 0)
+	if len(all) != maxEvents {
+		t.Fatalf("expected %d events kept, got %d", maxEvents, len(all))
+	}
+	if all[0].Seq != 11 {
+		t.Errorf("expected oldest kept event seq 11, got %d", all[0].Seq)
+	}
+
+	newer := gs.Events(all[len(all)-3].Seq, 0)
+	if len(newer) != 2 {
+		t.Errorf("expected 2 events after seq, got %d", len(newer))
+	}
+	if latest := gs.Events(0, 1); len(latest) != 1 || latest[0].Seq != uint64(maxEvents+10) {
+		t.Errorf("expected only the latest event, got %v", latest)
+	}
+}
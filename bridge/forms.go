@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Form types, as given by the "type" field of the form JSON.
+const (
+	formTypeModal  = "modal"       // two buttons, answered with true or false
+	formTypeMenu   = "form"        // list of buttons, answered with the button index
+	formTypeCustom = "custom_form" // list of elements, answered with one value per element
+)
+
+// PendingForm is a form the server sent with ModalFormRequest that has not been answered.
+type PendingForm struct {
+	ID         uint32         `json:"id"`
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Form       map[string]any `json:"form"`
+	ReceivedAt time.Time      `json:"received_at"`
+}
+
+// parseForm decodes the JSON of a ModalFormRequest.
+func parseForm(pk *packet.ModalFormRequest) (PendingForm, error) {
+	var data map[string]any
+	if err := json.Unmarshal(pk.FormData, &data); err != nil {
+		return PendingForm{}, fmt.Errorf("parsing form %d: %w", pk.FormID, err)
+	}
+	form := PendingForm{ID: pk.FormID, Form: data, ReceivedAt: time.Now()}
+	form.Type, _ = data["type"].(string)
+	form.Title, _ = data["title"].(string)
+	return form, nil
+}
+
+// validateFormResponse checks that response is a valid answer to form.
+func validateFormResponse(form PendingForm, response any) error {
+	switch form.Type {
+	case formTypeModal:
+		if _, ok := response.(bool); !ok {
+			return fmt.Errorf("modal form expects true or false")
+		}
+	case formTypeMenu:
+		buttons, _ := form.Form["buttons"].([]any)
+		n, ok := response.(float64)
+		if !ok || n != float64(int(n)) || n < 0 || int(n) >= len(buttons) {
+			return fmt.Errorf("menu form expects a button index from 0 to %d", len(buttons)-1)
+		}
+	case formTypeCustom:
+		content, _ := form.Form["content"].([]any)
+		values, ok := response.([]any)
+		if !ok || len(values) != len(content) {
+			return fmt.Errorf("custom form expects an array of %d values, one per element", len(content))
+		}
+	}
+	return nil
+}
+
+// submitForm answers the pending form with response (JSON), or closes it if cancel
+// is set, and closes the form on the client.
+func submitForm(state *GameState, form PendingForm, response string, cancel bool) error {
+	conn := state.ServerConn()
+	if conn == nil {
+		return fmt.Errorf("server connection not available")
+	}
+
+	pk := &packet.ModalFormResponse{FormID: form.ID}
+	if cancel {
+		pk.CancelReason = protocol.Option[uint8](packet.ModalFormCancelReasonUserClosed)
+	} else {
+		var value any
+		if err := json.Unmarshal([]byte(response), &value); err != nil {
+			return fmt.Errorf("invalid response JSON: %w", err)
+		}
+		if err := validateFormResponse(form, value); err != nil {
+			return err
+		}
+		pk.ResponseData = protocol.Option([]byte(response))
+	}
+	if err := conn.WritePacket(pk); err != nil {
+		return err
+	}
+	state.ClearPendingForm(form.ID)
+
+	if client := state.ClientConn(); client != nil {
+		_ = client.WritePacket(&packet.ClientBoundCloseForm{})
+	}
+	return nil
+}
+
+// SetPendingForm records a form awaiting an answer, replacing any earlier one.
+func (gs *GameState) SetPendingForm(form PendingForm) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.pendingForm = &form
+}
+
+// PendingForm returns the form awaiting an answer. ok is false if there is none.
+func (gs *GameState) PendingForm() (form PendingForm, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.pendingForm == nil {
+		return PendingForm{}, false
+	}
+	return *gs.pendingForm, true
+}
+
+// ClearPendingForm forgets the pending form if it has the given ID.
+func (gs *GameState) ClearPendingForm(id uint32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.pendingForm != nil && gs.pendingForm.ID == id {
+		gs.pendingForm = nil
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_ModalFormRequest(t *testing.T) {
+	gs := NewGameState()
+	interceptServerPacket(&packet.ModalFormRequest{
+		FormID:   7,
+		FormData: []byte(`{"type":"form","title":"Shop","content":"","buttons":[{"text":"Buy"},{"text":"Sell"}]}`),
+	}, gs)
+
+	form, ok := gs.PendingForm()
+	if !ok {
+		t.Fatal("expected a pending form")
+	}
+	if form.ID != 7 || form.Type != formTypeMenu || form.Title != "Shop" {
+		t.Errorf("unexpected form: %+v", form)
+	}
+
+	// The player answering in the client clears it.
+	interceptClientPacket(&packet.ModalFormResponse{FormID: 7}, gs)
+	if _, ok := gs.PendingForm(); ok {
+		t.Error("expected pending form cleared after client response")
+	}
+}
+
+func TestValidateFormResponse(t *testing.T) {
+	menu := PendingForm{Type: formTypeMenu, Form: map[string]any{"buttons": []any{"a", "b"}}}
+	custom := PendingForm{Type: formTypeCustom, Form: map[string]any{"content": []any{"a", "b"}}}
+	modal := PendingForm{Type: formTypeModal}
+
+	tests := []struct {
+		form     PendingForm
+		response string
+		wantErr  bool
+	}{
+		{modal, `true`, false},
+		{modal, `1`, true},
+		{menu, `1`, false},
+		{menu, `2`, true},
+		{menu, `0.5`, true},
+		{custom, `["Steve", 5]`, false},
+		{custom, `["Steve"]`, true},
+	}
+	for _, tt := range tests {
+		var value any
+		if err := json.Unmarshal([]byte(tt.response), &value); err != nil {
+			t.Fatal(err)
+		}
+		if err := validateFormResponse(tt.form, value); (err != nil) != tt.wantErr {
+			t.Errorf("%s %s: expected error %v, got %v", tt.form.Type, tt.response, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// gameRuleKind is the value type a game rule accepts.
+type gameRuleKind int
+
+const (
+	gameRuleBool gameRuleKind = iota
+	gameRuleInt
+)
+
+// knownGameRules maps lowercase Bedrock game rule names to their value type.
+// The server reports rule names in lowercase, so lookups are case-insensitive.
+var knownGameRules = map[string]gameRuleKind{
+	"commandblockoutput":        gameRuleBool,
+	"commandblocksenabled":      gameRuleBool,
+	"dodaylightcycle":           gameRuleBool,
+	"doentitydrops":             gameRuleBool,
+	"dofiretick":                gameRuleBool,
+	"doimmediaterespawn":        gameRuleBool,
+	"doinsomnia":                gameRuleBool,
+	"dolimitedcrafting":         gameRuleBool,
+	"domobloot":                 gameRuleBool,
+	"domobspawning":             gameRuleBool,
+	"dotiledrops":               gameRuleBool,
+	"doweathercycle":            gameRuleBool,
+	"drowningdamage":            gameRuleBool,
+	"falldamage":                gameRuleBool,
+	"firedamage":                gameRuleBool,
+	"freezedamage":              gameRuleBool,
+	"keepinventory":             gameRuleBool,
+	"mobgriefing":               gameRuleBool,
+	"naturalregeneration":       gameRuleBool,
+	"projectilescanbreakblocks": gameRuleBool,
+	"pvp":                       gameRuleBool,
+	"recipesunlock":             gameRuleBool,
+	"respawnblocksexplode":      gameRuleBool,
+	"sendcommandfeedback":       gameRuleBool,
+	"showbordereffect":          gameRuleBool,
+	"showcoordinates":           gameRuleBool,
+	"showdaysplayed":            gameRuleBool,
+	"showdeathmessages":         gameRuleBool,
+	"showrecipemessages":        gameRuleBool,
+	"showtags":                  gameRuleBool,
+	"tntexplodes":               gameRuleBool,
+	"tntexplosiondropdecay":     gameRuleBool,
+	"functioncommandlimit":      gameRuleInt,
+	"maxcommandchainlength":     gameRuleInt,
+	"playerssleepingpercentage": gameRuleInt,
+	"randomtickspeed":           gameRuleInt,
+	"spawnradius":               gameRuleInt,
+}
+
+// parseGameRule validates a game rule name and value against knownGameRules.
+// It returns the lowercase rule name and the typed value (bool or uint32, matching
+// what the server sends in GameRulesChanged).
+func parseGameRule(name, value string) (string, any, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	kind, ok := knownGameRules[key]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown game rule %q (known: %s)", name, strings.Join(knownGameRuleNames(), ", "))
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch kind {
+	case gameRuleBool:
+		switch value {
+		case "true":
+			return key, true, nil
+		case "false":
+			return key, false, nil
+		}
+		return "", nil, fmt.Errorf("game rule %s expects true or false, got %q", key, value)
+	default:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return "", nil, fmt.Errorf("game rule %s expects a non-negative integer, got %q", key, value)
+		}
+		return key, uint32(n), nil
+	}
+}
+
+// knownGameRuleNames returns the sorted list of known game rule names.
+func knownGameRuleNames() []string {
+	names := make([]string, 0, len(knownGameRules))
+	for name := range knownGameRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGameRule(t *testing.T) {
+	tests := []struct {
+		name, value string
+		wantName    string
+		want        any
+	}{
+		{"doDaylightCycle", "false", "dodaylightcycle", false},
+		{"KEEPINVENTORY", "TRUE", "keepinventory", true},
+		{"randomTickSpeed", "3", "randomtickspeed", uint32(3)},
+		{" spawnradius ", " 10 ", "spawnradius", uint32(10)},
+	}
+	for _, tt := range tests {
+		name, value, err := parseGameRule(tt.name, tt.value)
+		if err != nil {
+			t.Errorf("parseGameRule(%q, %q) unexpected error: %v", tt.name, tt.value, err)
+			continue
+		}
+		if name != tt.wantName || value != tt.want {
+			t.Errorf("parseGameRule(%q, %q) = (%q, %#v), want (%q, %#v)", tt.name, tt.value, name, value, tt.wantName, tt.want)
+		}
+	}
+}
+
+func TestParseGameRule_Errors(t *testing.T) {
+	tests := []struct {
+		name, value, wantErr string
+	}{
+		{"notARule", "true", "unknown game rule"},
+		{"doDaylightCycle", "1", "expects true or false"},
+		{"doDaylightCycle", "yes", "expects true or false"},
+		{"randomTickSpeed", "fast", "expects a non-negative integer"},
+		{"randomTickSpeed", "-1", "expects a non-negative integer"},
+	}
+	for _, tt := range tests {
+		_, _, err := parseGameRule(tt.name, tt.value)
+		if err == nil {
+			t.Errorf("parseGameRule(%q, %q) expected error", tt.name, tt.value)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseGameRule(%q, %q) error = %v, want containing %q", tt.name, tt.value, err, tt.wantErr)
+		}
+	}
+}
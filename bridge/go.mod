@@ -4,6 +4,7 @@ go 1.25.5
 
 require (
 	github.com/go-gl/mathgl v1.1.0
+	github.com/google/uuid v1.6.0
 	github.com/mark3labs/mcp-go v0.32.0
 	github.com/sandertv/gophertunnel v1.52.2
 	golang.org/x/oauth2 v0.23.0
@@ -12,7 +13,6 @@ require (
 require (
 	github.com/df-mc/jsonc v1.0.5 // indirect
 	github.com/go-jose/go-jose/v4 v4.1.0 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/klauspost/compress v1.18.1 // indirect
 	github.com/sandertv/go-raknet v1.14.3-0.20250305181847-6af3e95113d6 // indirect
 	github.com/spf13/cast v1.7.1 // indirect
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Search settings for get_hazards.
+const (
+	defaultHazardRadius = 8
+	maxHazardRadius     = 16
+	maxHazards          = 50
+	voidHazardDistance  = 8 // blocks above the bottom of the world
+)
+
+// Hazard kinds reported by get_hazards.
+const (
+	hazardKindBlock  = "block"
+	hazardKindEntity = "entity"
+	hazardKindVoid   = "void"
+)
+
+// hazardBlocks lists the block name fragments of hazardous blocks, most specific
+// first. A block is a hazard if its name contains a fragment, e.g. "minecraft:soul_fire"
+// is fire and "minecraft:soul_campfire" is a campfire.
+var hazardBlocks = []string{
+	"lava",
+	"campfire",
+	"fire",
+	"magma",
+	"cactus",
+	"sweet_berry_bush",
+	"powder_snow",
+	"wither_rose",
+	"pointed_dripstone",
+}
+
+// hostileEntities lists the entity types that attack the player on sight.
+var hostileEntities = map[string]bool{
+	"minecraft:blaze":              true,
+	"minecraft:bogged":             true,
+	"minecraft:breeze":             true,
+	"minecraft:cave_spider":        true,
+	"minecraft:creeper":            true,
+	"minecraft:drowned":            true,
+	"minecraft:elder_guardian":     true,
+	"minecraft:endermite":          true,
+	"minecraft:evocation_illager":  true,
+	"minecraft:ghast":              true,
+	"minecraft:guardian":           true,
+	"minecraft:hoglin":             true,
+	"minecraft:husk":               true,
+	"minecraft:magma_cube":         true,
+	"minecraft:phantom":            true,
+	"minecraft:piglin_brute":       true,
+	"minecraft:pillager":           true,
+	"minecraft:ravager":            true,
+	"minecraft:shulker":            true,
+	"minecraft:silverfish":         true,
+	"minecraft:skeleton":           true,
+	"minecraft:slime":              true,
+	"minecraft:spider":             true,
+	"minecraft:stray":              true,
+	"minecraft:vex":                true,
+	"minecraft:vindicator":         true,
+	"minecraft:warden":             true,
+	"minecraft:witch":              true,
+	"minecraft:wither":             true,
+	"minecraft:wither_skeleton":    true,
+	"minecraft:zoglin":             true,
+	"minecraft:zombie":             true,
+	"minecraft:zombie_villager_v2": true,
+}
+
+// blockHazard returns the hazard a block poses, if any.
+func blockHazard(name string) (string, bool) {
+	for _, fragment := range hazardBlocks {
+		if strings.Contains(name, fragment) {
+			return fragment, true
+		}
+	}
+	return "", false
+}
+
+// isHazardBlock reports whether standing on or in a block hurts the player.
+func isHazardBlock(name string) bool {
+	_, ok := blockHazard(name)
+	return ok
+}
+
+// Hazard is a nearby threat found by get_hazards.
+type Hazard struct {
+	Kind      string     `json:"kind"` // block, entity or void
+	Name      string     `json:"name"`
+	Position  mgl32.Vec3 `json:"position"`
+	Distance  float64    `json:"distance"`
+	RuntimeID uint64     `json:"runtime_id,omitempty"` // for entities
+}
+
+// findHazards returns the hazards within radius blocks of the player's feet, nearest
+// first and at most maxHazards: hazard blocks from the block cache, hostile mobs, and
+// the bottom of the world when the player is close to it.
+func findHazards(state *GameState, radius int) []Hazard {
+	x, y, z, _, _, dimension := state.Position()
+	feet := mgl32.Vec3{x, y - playerEyeHeight, z}
+	feetBlock := playerFeetBlock(state)
+	distance := func(p mgl32.Vec3) float64 { return float64(p.Sub(feet).Len()) }
+
+	var hazards []Hazard
+	blocks := state.BlockNames()
+	r := int32(radius)
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			for dz := -r; dz <= r; dz++ {
+				pos := addBlockPos(feetBlock, protocol.BlockPos{dx, dy, dz})
+				name, ok := blocks.at(pos)
+				if !ok {
+					continue
+				}
+				if hazard, ok := blockHazard(name); ok {
+					centre := mgl32.Vec3{float32(pos[0]) + 0.5, float32(pos[1]) + 0.5, float32(pos[2]) + 0.5}
+					hazards = append(hazards, Hazard{Kind: hazardKindBlock, Name: hazard, Position: centre, Distance: distance(centre)})
+				}
+			}
+		}
+	}
+
+	for _, e := range state.Entities() {
+		if !hostileEntities[e.Type] {
+			continue
+		}
+		if d := distance(e.Position); d <= float64(radius) {
+			hazards = append(hazards, Hazard{Kind: hazardKindEntity, Name: e.Type, Position: e.Position, Distance: d, RuntimeID: e.RuntimeID})
+		}
+	}
+
+	minY, _ := dimensionHeight(dimension)
+	if above := float64(feet.Y()) - minY; above <= voidHazardDistance {
+		hazards = append(hazards, Hazard{
+			Kind:     hazardKindVoid,
+			Name:     "void",
+			Position: mgl32.Vec3{feet.X(), float32(minY), feet.Z()},
+			Distance: math.Max(0, above),
+		})
+	}
+
+	sort.SliceStable(hazards, func(i, j int) bool { return hazards[i].Distance < hazards[j].Distance })
+	if len(hazards) > maxHazards {
+		hazards = hazards[:maxHazards]
+	}
+	return hazards
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestBlockHazard(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"minecraft:lava", "lava"},
+		{"minecraft:flowing_lava", "lava"},
+		{"minecraft:soul_fire", "fire"},
+		{"minecraft:soul_campfire", "campfire"},
+		{"minecraft:magma", "magma"},
+		{"minecraft:stone", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := blockHazard(tt.name); got != tt.want {
+			t.Errorf("blockHazard(%q): expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFindHazards(t *testing.T) {
+	gs := newChunkTestState(t)
+	lava := blockState{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(0)}}
+	sendTestChunk(gs, map[protocol.BlockPos]blockState{
+		{11, 66, 8}: lava,
+		{9, 66, 8}:  {Name: "minecraft:stone"},
+		{14, 66, 8}: lava, // out of range
+	})
+	gs.AddEntity(7, "minecraft:zombie", mgl32.Vec3{8.5, 66, 10.5})
+	gs.AddEntity(8, "minecraft:cow", mgl32.Vec3{8.5, 66, 9.5})
+	gs.AddEntity(9, "minecraft:creeper", mgl32.Vec3{30, 66, 8})
+
+	hazards := findHazards(gs, 4)
+	if len(hazards) != 2 {
+		t.Fatalf("expected 2 hazards, got %+v", hazards)
+	}
+	if h := hazards[0]; h.Kind != hazardKindEntity || h.Name != "minecraft:zombie" || h.RuntimeID != 7 || h.Distance != 2 {
+		t.Errorf("expected zombie 2 blocks away first, got %+v", h)
+	}
+	if h := hazards[1]; h.Kind != hazardKindBlock || h.Name != "lava" || h.Position != (mgl32.Vec3{11.5, 66.5, 8.5}) {
+		t.Errorf("expected lava at (11,66,8) second, got %+v", h)
+	}
+}
+
+func TestFindHazards_Void(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(0.5, -60+playerEyeHeight, 0.5, 0, 0)
+	hazards := findHazards(gs, 4)
+	if len(hazards) != 1 || hazards[0].Kind != hazardKindVoid || hazards[0].Distance != 4 {
+		t.Errorf("expected void 4 blocks below, got %+v", hazards)
+	}
+}
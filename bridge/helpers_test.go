@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestTrimSpace(t *testing.T) {
@@ -87,3 +89,40 @@ func TestReadChunksFile_WhitespaceLines(t *testing.T) {
 		t.Errorf("expected trimmed chunk1, got %q", chunks[0])
 	}
 }
+
+func TestReconnectWait(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{3, 60 * time.Second},
+		{4, 2 * time.Minute},
+		{10, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := reconnectWait(tt.attempt); got != tt.want {
+			t.Errorf("reconnectWait(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForReconnect_Timeout(t *testing.T) {
+	gs := NewGameState()
+	gs.SetStatus(StatusDisconnected)
+	_, err := waitForReconnect(context.Background(), gs, nil, 100*time.Millisecond)
+	if err == nil {
+		t.Error("expected timeout error when no session is established")
+	}
+}
+
+func TestWaitForReconnect_Cancelled(t *testing.T) {
+	gs := NewGameState()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := waitForReconnect(ctx, gs, nil, time.Minute)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
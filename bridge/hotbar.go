@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// hotbarSize is the number of hotbar slots (0-8).
+const hotbarSize = 9
+
+// HeldItem is the selected hotbar slot and the item in it.
+type HeldItem struct {
+	Slot  int    `json:"slot"`
+	Item  string `json:"item"` // empty if the slot is empty
+	Count int    `json:"count"`
+}
+
+// SetHeldSlot records the selected hotbar slot.
+func (gs *GameState) SetHeldSlot(slot int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.heldSlot = slot
+}
+
+// HeldItem returns the selected hotbar slot and the item tracked in it.
+func (gs *GameState) HeldItem() HeldItem {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	held := HeldItem{Slot: gs.heldSlot}
+	if items := gs.inventory[protocol.WindowIDInventory]; gs.heldSlot < len(items) {
+		if item := items[gs.heldSlot]; item.Stack.Count > 0 {
+			held.Item = gs.resolveItemName(item.Stack.NetworkID)
+			held.Count = int(item.Stack.Count)
+		}
+	}
+	return held
+}
+
+// selectHotbarSlot makes slot the held hotbar slot: the server is told with
+// MobEquipment and the client's selection is moved to match with PlayerHotBar.
+func selectHotbarSlot(state *GameState, slot int) (HeldItem, error) {
+	if slot < 0 || slot >= hotbarSize {
+		return HeldItem{}, fmt.Errorf("hotbar slot must be 0-%d, got %d", hotbarSize-1, slot)
+	}
+	conn := state.ServerConn()
+	if conn == nil {
+		return HeldItem{}, fmt.Errorf("server connection not available")
+	}
+
+	item, _ := state.InventoryItem(protocol.WindowIDInventory, slot)
+	if err := conn.WritePacket(&packet.MobEquipment{
+		EntityRuntimeID: state.EntityID(),
+		NewItem:         item,
+		InventorySlot:   byte(slot),
+		HotBarSlot:      byte(slot),
+		WindowID:        protocol.WindowIDInventory,
+	}); err != nil {
+		return HeldItem{}, err
+	}
+	state.SetHeldSlot(slot)
+
+	if client := state.ClientConn(); client != nil {
+		_ = client.WritePacket(&packet.PlayerHotBar{
+			SelectedHotBarSlot: uint32(slot),
+			WindowID:           protocol.WindowIDInventory,
+			SelectHotBarSlot:   true,
+		})
+	}
+	return state.HeldItem(), nil
+}
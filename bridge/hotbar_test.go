@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestHeldItem_TracksSelection(t *testing.T) {
+	gs := NewGameState()
+	gs.itemRegistry[5] = "minecraft:diamond_pickaxe"
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 3, protocol.ItemInstance{
+		Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 5}, Count: 1},
+	})
+
+	interceptClientPacket(&packet.MobEquipment{HotBarSlot: 3, WindowID: protocol.WindowIDInventory}, gs)
+	held := gs.HeldItem()
+	if held.Slot != 3 || held.Item != "minecraft:diamond_pickaxe" || held.Count != 1 {
+		t.Errorf("unexpected held item: %+v", held)
+	}
+
+	interceptServerPacket(&packet.PlayerHotBar{SelectedHotBarSlot: 7, SelectHotBarSlot: true}, gs)
+	if held := gs.HeldItem(); held.Slot != 7 || held.Item != "" {
+		t.Errorf("expected empty slot 7 held, got %+v", held)
+	}
+}
+
+func TestSelectHotbarSlot_Range(t *testing.T) {
+	gs := NewGameState()
+	for _, slot := range []int{-1, hotbarSize} {
+		if _, err := selectHotbarSlot(gs, slot); err == nil || !strings.Contains(err.Error(), "0-8") {
+			t.Errorf("slot %d: expected range error, got %v", slot, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "time"
+
+// playerInputTick is the normal PlayerAuthInput cadence, one game tick.
+const playerInputTick = 50 * time.Millisecond
+
+// idleInputInterval is the PlayerAuthInput cadence while the agent is idle and
+// idle throttling is on: slow enough to save bandwidth, frequent enough that the
+// server keeps treating the player as present.
+const idleInputInterval = time.Second
+
+// SetIdleThrottle sets how long after the last tool call PlayerAuthInput slows to
+// idleInputInterval. 0 disables throttling.
+func (gs *GameState) SetIdleThrottle(after time.Duration) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.idleThrottleAfter = after
+}
+
+// InputInterval returns the delay before the next PlayerAuthInput at now: one tick,
+// or idleInputInterval once the agent has been idle for the throttle period. The
+// next tool call restores the full rate from the following packet.
+func (gs *GameState) InputInterval(now time.Time) time.Duration {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.idleThrottleAfter > 0 && now.Sub(gs.lastToolCall) >= gs.idleThrottleAfter {
+		return idleInputInterval
+	}
+	return playerInputTick
+}
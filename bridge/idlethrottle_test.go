@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInputInterval(t *testing.T) {
+	gs := NewGameState()
+	now := time.Now()
+	if d := gs.InputInterval(now.Add(time.Hour)); d != playerInputTick {
+		t.Errorf("expected full rate with throttling off, got %s", d)
+	}
+
+	gs.SetIdleThrottle(time.Minute)
+	tests := []struct {
+		idle     time.Duration
+		expected time.Duration
+	}{
+		{0, playerInputTick},
+		{30 * time.Second, playerInputTick},
+		{time.Minute, idleInputInterval},
+		{time.Hour, idleInputInterval},
+	}
+	for _, tt := range tests {
+		gs.mu.Lock()
+		gs.lastToolCall = now.Add(-tt.idle)
+		gs.mu.Unlock()
+		if d := gs.InputInterval(now); d != tt.expected {
+			t.Errorf("idle %s: expected %s, got %s", tt.idle, tt.expected, d)
+		}
+	}
+
+	// A tool call restores the full rate; anti-idle actions do not
+	gs.SetAntiIdle(true, time.Minute)
+	gs.mu.Lock()
+	gs.lastActivity = now.Add(-time.Hour)
+	gs.lastToolCall = now.Add(-time.Hour)
+	gs.mu.Unlock()
+	if !gs.AntiIdleDue(time.Now()) {
+		t.Fatal("expected anti-idle action to be due")
+	}
+	if d := gs.InputInterval(time.Now()); d != idleInputInterval {
+		t.Errorf("expected anti-idle action to keep the idle rate, got %s", d)
+	}
+	gs.MarkActivity()
+	if d := gs.InputInterval(time.Now()); d != playerInputTick {
+		t.Errorf("expected full rate after a tool call, got %s", d)
+	}
+}
@@ -4,18 +4,26 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
 // interceptClientPacket processes a packet from the client heading to the server.
 // It updates state but never modifies the packet.
 func interceptClientPacket(pk packet.Packet, state *GameState) {
+	logFilteredPacket(pk, packetDirClient, state)
+	state.RecordRawPacket(pk, packetDirClient)
 	switch p := pk.(type) {
+	case *packet.PacketViolationWarning:
+		state.RecordViolation(p, packetDirClient)
 	case *packet.PlayerAuthInput:
 		state.UpdatePosition(
 			p.Position.X(), p.Position.Y(), p.Position.Z(),
 			p.Pitch, p.Yaw,
 		)
+		if flying, ok := flightInput(p); ok {
+			state.SetFlying(flying)
+		}
 		logPlayerAuthInputBuilding(p, state)
 	case *packet.Text:
 		if p.TextType == packet.TextTypeChat {
@@ -31,17 +39,52 @@ func interceptClientPacket(pk packet.Packet, state *GameState) {
 	case *packet.PlayerAction:
 		logPlayerAction(p, state)
 	case *packet.MobEquipment:
+		if p.WindowID == protocol.WindowIDInventory {
+			state.SetHeldSlot(int(p.HotBarSlot))
+		}
 		logMobEquipment(p, state)
+	case *packet.ContainerClose:
+		// The player closed the container in the client.
+		state.CloseContainerWindow(p.WindowID)
+	case *packet.ModalFormResponse:
+		// The player answered the form in the client.
+		state.ClearPendingForm(p.FormID)
+	}
+}
+
+// ownServerResponse reports whether pk answers a request the bridge sent itself
+// rather than the client. Such packets are not relayed to the client. Item stack
+// responses to the bridge's requests that share a packet with responses to the
+// client's are removed from it.
+func ownServerResponse(pk packet.Packet, state *GameState) bool {
+	switch p := pk.(type) {
+	case *packet.SubChunk:
+		return state.TakeOwnSubChunkResponse(p.Dimension, p.Position, time.Now())
+	case *packet.ItemStackResponse:
+		var clientResponses []protocol.ItemStackResponse
+		for _, resp := range p.Responses {
+			if !isBridgeStackRequestID(resp.RequestID) {
+				clientResponses = append(clientResponses, resp)
+			}
+		}
+		if len(clientResponses) == len(p.Responses) {
+			return false
+		}
+		p.Responses = clientResponses
+		return len(clientResponses) == 0
 	}
+	return false
 }
 
 // interceptServerPacket processes a packet from the server heading to the client.
 // It updates state but never modifies the packet.
 func interceptServerPacket(pk packet.Packet, state *GameState) {
+	logFilteredPacket(pk, packetDirServer, state)
+	state.RecordRawPacket(pk, packetDirServer)
 	switch p := pk.(type) {
 	case *packet.MovePlayer:
 		if p.EntityRuntimeID == state.EntityID() {
-			state.UpdatePosition(
+			state.RecordServerMove(
 				p.Position.X(), p.Position.Y(), p.Position.Z(),
 				p.Pitch, p.Yaw,
 			)
@@ -49,6 +92,7 @@ func interceptServerPacket(pk packet.Packet, state *GameState) {
 
 	case *packet.ChangeDimension:
 		state.SetDimension(p.Dimension)
+		state.RecordEvent(EventDimensionChanged, "moved to "+dimensionName(p.Dimension))
 		slog.Debug("dimension changed", "dimension", p.Dimension)
 
 	case *packet.InventoryContent:
@@ -64,36 +108,84 @@ func interceptServerPacket(pk packet.Packet, state *GameState) {
 			Message: p.Message,
 			Type:    "incoming",
 		})
+		state.NotifyChat(p.SourceName, p.Message)
 
 	case *packet.PlayerList:
 		if p.ActionType == packet.PlayerListActionAdd {
 			for _, entry := range p.Entries {
-				state.AddPlayer(entry.XUID, entry.Username)
+				state.SetPlayerListIdentity(entry)
+				if state.AddPlayerEntry(entry.UUID, entry.XUID, entry.Username) {
+					state.RecordEvent(EventPlayerJoined, entry.Username+" joined")
+					state.NotifyPlayerJoined(entry.Username)
+				}
 			}
 		} else if p.ActionType == packet.PlayerListActionRemove {
 			for _, entry := range p.Entries {
-				state.RemovePlayer(entry.XUID)
+				if player, ok := state.RemovePlayerEntry(entry.UUID, entry.XUID); ok {
+					state.RecordEvent(EventPlayerLeft, player.Username+" left")
+				}
 			}
 		}
 
+	case *packet.SetSpawnPosition:
+		if p.SpawnType == packet.SpawnTypePlayer {
+			state.SetPlayerSpawn(p.Position, p.Dimension)
+		} else {
+			state.SetWorldSpawn(p.Position)
+		}
+
+	case *packet.Disconnect:
+		state.RecordEvent(EventDisconnected, "disconnected by the server: "+p.Message)
+
+	case *packet.Transfer:
+		state.RecordTransfer(p.Address, p.Port)
+		slog.Info("server sent transfer", "address", p.Address, "port", p.Port, "follow", state.FollowTransfers())
+
 	case *packet.SetTime:
 		state.SetWorldTime(int64(p.Time))
 
+	case *packet.CommandOutput:
+		state.DeliverCommandOutput(p)
+
+	case *packet.GameRulesChanged:
+		state.UpdateGameRules(p.GameRules)
+
 	case *packet.UpdateAttributes:
 		if p.EntityRuntimeID == state.EntityID() {
 			for _, attr := range p.Attributes {
 				state.SetAttribute(attr.Name, attr.Value)
 			}
+		} else {
+			for _, attr := range p.Attributes {
+				if attr.Name == attrHealth {
+					state.UpdateEntityHealth(p.EntityRuntimeID, attr.Value)
+				}
+			}
 		}
 
+	case *packet.PacketViolationWarning:
+		state.RecordViolation(p, packetDirServer)
+	case *packet.UpdateAbilities:
+		state.SetAbilities(p.AbilityData)
+	case *packet.AdventureSettings:
+		state.SetAdventureFlags(p.Flags, p.PlayerUniqueID)
+
 	case *packet.SetHealth:
 		state.SetHealth(float32(p.Health))
 
 	case *packet.AddActor:
 		state.AddEntity(p.EntityRuntimeID, p.EntityType, p.Position)
+		if health, ok := healthFromAttributes(p.Attributes); ok {
+			state.UpdateEntityHealth(p.EntityRuntimeID, health)
+		} else if health, ok := healthFromMetadata(p.EntityMetadata); ok {
+			state.UpdateEntityHealth(p.EntityRuntimeID, health)
+		}
 
 	case *packet.AddPlayer:
 		state.AddEntity(p.EntityRuntimeID, p.Username, p.Position)
+		state.SetPlayerEntityIdentity(p.UUID, p.EntityRuntimeID, p.BuildPlatform)
+	case *packet.PlayerSkin:
+		state.SetPlayerSkin(p.UUID, p.Skin, p.NewSkinName)
 
 	case *packet.RemoveActor:
 		// EntityUniqueID is int64; our entity map uses uint64 runtime IDs.
@@ -102,16 +194,74 @@ func interceptServerPacket(pk packet.Packet, state *GameState) {
 
 	case *packet.MoveActorDelta:
 		state.UpdateEntityPosition(p.EntityRuntimeID, p.Position)
+	case *packet.SetActorMotion:
+		state.UpdateEntityVelocity(p.EntityRuntimeID, p.Velocity)
+	case *packet.SetActorData:
+		if health, ok := healthFromMetadata(p.EntityMetadata); ok {
+			state.UpdateEntityHealth(p.EntityRuntimeID, health)
+		}
 
 	case *packet.UpdateBlock:
+		if p.Layer == 0 {
+			applyBlockChange(p.Position, p.NewBlockRuntimeID, state)
+		}
 		logUpdateBlock(p, state)
+	case *packet.UpdateSubChunkBlocks:
+		// Extra holds layer 1 (e.g. water in waterlogged blocks), which the cache
+		// does not track.
+		for _, b := range p.Blocks {
+			applyBlockChange(b.BlockPos, b.BlockRuntimeID, state)
+		}
+	case *packet.ModalFormRequest:
+		if form, err := parseForm(p); err != nil {
+			slog.Warn("ignoring form", "error", err)
+		} else {
+			state.SetPendingForm(form)
+		}
+	case *packet.SetPlayerGameType:
+		state.SetGameMode(p.GameType)
+	case *packet.PlayerHotBar:
+		if p.SelectHotBarSlot && p.WindowID == protocol.WindowIDInventory {
+			state.SetHeldSlot(int(p.SelectedHotBarSlot))
+		}
+	case *packet.AvailableCommands:
+		state.SetAvailableCommands(parseAvailableCommands(p))
+		state.SetCommandTree(parseCommandTree(p))
+
+	case *packet.UpdateSoftEnum:
+		state.UpdateSoftEnum(p.EnumType, p.Options, p.ActionType)
+	case *packet.BlockActorData:
+		state.SetBlockEntity(p.Position, p.NBTData)
+	case *packet.LevelChunk:
+		handleLevelChunk(p, state)
+	case *packet.SubChunk:
+		handleSubChunk(p, state)
 	case *packet.LevelEvent:
 		logLevelEvent(p, state)
+	case *packet.CreativeContent:
+		state.SetCreativeItems(p.Items)
+
+	case *packet.CraftingData:
+		state.AddRecipes(p.Recipes, p.ClearRecipes)
+
 	case *packet.ItemStackResponse:
+		for _, resp := range p.Responses {
+			state.DeliverItemStackResponse(resp)
+		}
 		logItemStackResponse(p, state)
 	case *packet.ContainerOpen:
+		state.OpenContainerWindow(p)
 		logContainerOpen(p, state)
 	case *packet.ContainerClose:
+		state.CloseContainerWindow(p.WindowID)
 		logContainerClose(p, state)
 	}
 }
+
+// applyBlockChange stores a changed layer 0 block in the block cache and tells the
+// watchers and placement pacer about it.
+func applyBlockChange(pos protocol.BlockPos, runtimeID uint32, state *GameState) {
+	state.Blocks().Set(pos, runtimeID)
+	state.NotifyBlockChanged(pos, state.ResolveBlockName(runtimeID))
+	state.ConfirmPlacement(pos, runtimeID)
+}
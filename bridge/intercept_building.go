@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
@@ -10,7 +12,7 @@ import (
 // --- Client → Server ---
 
 func logInventoryTransaction(p *packet.InventoryTransaction, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirClient, "InventoryTransaction") {
 		return
 	}
 	switch td := p.TransactionData.(type) {
@@ -49,7 +51,7 @@ func logInventoryTransaction(p *packet.InventoryTransaction, state *GameState) {
 }
 
 func logPlayerAction(p *packet.PlayerAction, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirClient, "PlayerAction") {
 		return
 	}
 	// Only log building-relevant actions
@@ -75,7 +77,7 @@ func logPlayerAction(p *packet.PlayerAction, state *GameState) {
 }
 
 func logMobEquipment(p *packet.MobEquipment, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirClient, "MobEquipment") {
 		return
 	}
 	slog.Info("pkt", "dir", "C→S", "pkt", "MobEquipment",
@@ -86,7 +88,7 @@ func logMobEquipment(p *packet.MobEquipment, state *GameState) {
 }
 
 func logPlayerAuthInputBuilding(p *packet.PlayerAuthInput, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirClient, "PlayerAuthInput") {
 		return
 	}
 	if p.InputData.Load(packet.InputFlagPerformItemInteraction) {
@@ -113,7 +115,7 @@ func logPlayerAuthInputBuilding(p *packet.PlayerAuthInput, state *GameState) {
 // --- Server → Client ---
 
 func logUpdateBlock(p *packet.UpdateBlock, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirServer, "UpdateBlock") {
 		return
 	}
 	slog.Info("pkt", "dir", "S→C", "pkt", "UpdateBlock",
@@ -126,7 +128,7 @@ func logUpdateBlock(p *packet.UpdateBlock, state *GameState) {
 }
 
 func logLevelEvent(p *packet.LevelEvent, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirServer, "LevelEvent") {
 		return
 	}
 	var eventName string
@@ -150,7 +152,7 @@ func logLevelEvent(p *packet.LevelEvent, state *GameState) {
 }
 
 func logItemStackResponse(p *packet.ItemStackResponse, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirServer, "ItemStackResponse") {
 		return
 	}
 	for _, resp := range p.Responses {
@@ -162,7 +164,7 @@ func logItemStackResponse(p *packet.ItemStackResponse, state *GameState) {
 }
 
 func logContainerOpen(p *packet.ContainerOpen, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirServer, "ContainerOpen") {
 		return
 	}
 	slog.Info("pkt", "dir", "S→C", "pkt", "ContainerOpen",
@@ -173,7 +175,7 @@ func logContainerOpen(p *packet.ContainerOpen, state *GameState) {
 }
 
 func logContainerClose(p *packet.ContainerClose, state *GameState) {
-	if !state.VerbosePacketLog() {
+	if !state.ShouldLogPacket(packetDirServer, "ContainerClose") {
 		return
 	}
 	slog.Info("pkt", "dir", "S→C", "pkt", "ContainerClose",
@@ -182,3 +184,45 @@ func logContainerClose(p *packet.ContainerClose, state *GameState) {
 		"serverSide", p.ServerSide,
 	)
 }
+
+// --- Any packet (filtered) ---
+
+// packetLoggers lists the packet types that have a dedicated building logger above.
+var packetLoggers = map[string]bool{
+	"InventoryTransaction": true,
+	"PlayerAction":         true,
+	"MobEquipment":         true,
+	"PlayerAuthInput":      true,
+	"UpdateBlock":          true,
+	"LevelEvent":           true,
+	"ItemStackResponse":    true,
+	"ContainerOpen":        true,
+	"ContainerClose":       true,
+}
+
+// maxGenericPacketLog caps the length of the dump written for generic packets.
+const maxGenericPacketLog = 2000
+
+// packetTypeName returns the gophertunnel type name of a packet, e.g. "UpdateBlock".
+func packetTypeName(pk packet.Packet) string {
+	name := fmt.Sprintf("%T", pk)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// logFilteredPacket logs packets without a dedicated logger when the packet log
+// filter explicitly names their type. It runs on every relayed packet, so it returns
+// before naming the packet while verbose logging is off.
+func logFilteredPacket(pk packet.Packet, dir string, state *GameState) {
+	if !state.VerbosePacketLog() {
+		return
+	}
+	name := packetTypeName(pk)
+	if packetLoggers[name] || !state.PacketLogFilterNames(dir, name) {
+		return
+	}
+	dump := fmt.Sprintf("%+v", pk)
+	if len(dump) > maxGenericPacketLog {
+		dump = dump[:maxGenericPacketLog] + "..."
+	}
+	slog.Info("pkt", "dir", dir, "pkt", name, "data", dump)
+}
@@ -435,3 +435,81 @@ func TestLogContainerClose(t *testing.T) {
 		t.Errorf("expected log to contain ContainerClose, got: %s", output)
 	}
 }
+
+// --- packet log filter ---
+
+func TestPacketLogFilter_SuppressesUnlistedTypes(t *testing.T) {
+	gs := NewGameState()
+	gs.SetVerbosePacketLog(true)
+	gs.SetPacketLogFilter([]string{"UpdateBlock"}, "")
+
+	output := captureLogs(t, func() {
+		logContainerClose(&packet.ContainerClose{WindowID: 1}, gs)
+	})
+	if output != "" {
+		t.Errorf("expected ContainerClose to be filtered out, got: %s", output)
+	}
+
+	output = captureLogs(t, func() {
+		logUpdateBlock(&packet.UpdateBlock{Position: protocol.BlockPos{1, 2, 3}}, gs)
+	})
+	if !strings.Contains(output, "pkt=UpdateBlock") {
+		t.Errorf("expected UpdateBlock to be logged, got: %s", output)
+	}
+}
+
+func TestPacketLogFilter_Direction(t *testing.T) {
+	gs := NewGameState()
+	gs.SetVerbosePacketLog(true)
+	gs.SetPacketLogFilter(nil, packetDirServer)
+
+	output := captureLogs(t, func() {
+		logMobEquipment(&packet.MobEquipment{}, gs)
+	})
+	if output != "" {
+		t.Errorf("expected client packet to be filtered out, got: %s", output)
+	}
+	output = captureLogs(t, func() {
+		logContainerClose(&packet.ContainerClose{WindowID: 1}, gs)
+	})
+	if !strings.Contains(output, "pkt=ContainerClose") {
+		t.Errorf("expected server packet to be logged, got: %s", output)
+	}
+}
+
+func TestLogFilteredPacket_Generic(t *testing.T) {
+	gs := NewGameState()
+	gs.SetVerbosePacketLog(true)
+
+	// Without an explicit filter entry, packets with no dedicated logger stay quiet.
+	output := captureLogs(t, func() {
+		logFilteredPacket(&packet.SetTime{Time: 1000}, packetDirServer, gs)
+	})
+	if output != "" {
+		t.Errorf("expected no output without filter, got: %s", output)
+	}
+
+	gs.SetPacketLogFilter([]string{"SetTime"}, "")
+	output = captureLogs(t, func() {
+		logFilteredPacket(&packet.SetTime{Time: 1000}, packetDirServer, gs)
+	})
+	if !strings.Contains(output, "pkt=SetTime") || !strings.Contains(output, "Time:1000") {
+		t.Errorf("expected SetTime to be logged, got: %s", output)
+	}
+	gs.SetPacketLogFilter([]string{"SetTime"}, packetDirClient)
+	output = captureLogs(t, func() {
+		logFilteredPacket(&packet.SetTime{Time: 1000}, packetDirServer, gs)
+	})
+	if output != "" {
+		t.Errorf("expected server packet to be filtered out by direction, got: %s", output)
+	}
+
+	gs.SetPacketLogFilter([]string{"SetTime"}, "")
+	gs.SetVerbosePacketLog(false)
+	output = captureLogs(t, func() {
+		logFilteredPacket(&packet.SetTime{Time: 1000}, packetDirServer, gs)
+	})
+	if output != "" {
+		t.Errorf("expected no output with verbose logging off, got: %s", output)
+	}
+}
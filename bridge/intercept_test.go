@@ -2,8 +2,10 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
@@ -258,6 +260,36 @@ func TestIntercept_UpdateAttributes_OtherEntity(t *testing.T) {
 	}
 }
 
+func TestIntercept_UpdateAttributes_Hunger(t *testing.T) {
+	gs := NewGameState()
+	gs.SetIdentity("Steve", "123", 42)
+	if _, ok := gs.Hunger(); ok {
+		t.Error("expected hunger unknown before UpdateAttributes")
+	}
+
+	interceptServerPacket(&packet.UpdateAttributes{
+		EntityRuntimeID: 99, // other entities are ignored
+		Attributes: []protocol.Attribute{
+			{AttributeValue: protocol.AttributeValue{Name: attrHunger, Value: 2}},
+		},
+	}, gs)
+	if _, ok := gs.Hunger(); ok {
+		t.Error("expected hunger of another entity to be ignored")
+	}
+
+	interceptServerPacket(&packet.UpdateAttributes{
+		EntityRuntimeID: 42,
+		Attributes: []protocol.Attribute{
+			{AttributeValue: protocol.AttributeValue{Name: attrHunger, Value: 17}},
+			{AttributeValue: protocol.AttributeValue{Name: attrSaturation, Value: 3.5}},
+		},
+	}, gs)
+	hunger, ok := gs.Hunger()
+	if !ok || hunger.Food != 17 || hunger.Saturation != 3.5 {
+		t.Errorf("expected food 17 and saturation 3.5, got %+v (ok=%v)", hunger, ok)
+	}
+}
+
 func TestIntercept_SetHealth(t *testing.T) {
 	gs := NewGameState()
 	pk := &packet.SetHealth{Health: 18}
@@ -340,3 +372,110 @@ func TestIntercept_MoveActorDelta(t *testing.T) {
 		t.Errorf("expected position (100,200,300), got %v", e.Position)
 	}
 }
+
+func TestIntercept_SetActorMotion(t *testing.T) {
+	gs := NewGameState()
+	gs.AddEntity(700, "minecraft:arrow", mgl32.Vec3{0, 64, 0})
+
+	interceptServerPacket(&packet.SetActorMotion{
+		EntityRuntimeID: 700,
+		Velocity:        mgl32.Vec3{0.5, -0.1, 1.5},
+	}, gs)
+	// Motion for an untracked entity is ignored.
+	interceptServerPacket(&packet.SetActorMotion{
+		EntityRuntimeID: 701,
+		Velocity:        mgl32.Vec3{1, 1, 1},
+	}, gs)
+
+	entities := gs.Entities()
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(entities))
+	}
+	if v := entities[0].Velocity; v != (mgl32.Vec3{0.5, -0.1, 1.5}) {
+		t.Errorf("expected velocity (0.5,-0.1,1.5), got %v", v)
+	}
+}
+
+func TestIntercept_GameRulesChanged(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdateGameRules([]protocol.GameRule{{Name: "dodaylightcycle", Value: true}})
+
+	pk := &packet.GameRulesChanged{
+		GameRules: []protocol.GameRule{
+			{Name: "dodaylightcycle", Value: false},
+			{Name: "randomtickspeed", Value: uint32(0)},
+		},
+	}
+	interceptServerPacket(pk, gs)
+
+	rules := gs.GameRules()
+	if rules["dodaylightcycle"] != false {
+		t.Errorf("expected dodaylightcycle=false, got %v", rules["dodaylightcycle"])
+	}
+	if rules["randomtickspeed"] != uint32(0) {
+		t.Errorf("expected randomtickspeed=0, got %#v", rules["randomtickspeed"])
+	}
+}
+
+func TestGameRuleChangedSince(t *testing.T) {
+	gs := NewGameState()
+	gs.InitFromGameData(minecraft.GameData{GameRules: []protocol.GameRule{{Name: "dodaylightcycle", Value: false}}})
+	sent := time.Now()
+	if gs.GameRuleChangedSince("dodaylightcycle", false, sent) {
+		t.Error("expected the StartGame value not to count as a change")
+	}
+
+	interceptServerPacket(&packet.GameRulesChanged{GameRules: []protocol.GameRule{{Name: "dodaylightcycle", Value: false}}}, gs)
+	if !gs.GameRuleChangedSince("dodaylightcycle", false, sent) {
+		t.Error("expected GameRulesChanged after the command to confirm the rule")
+	}
+	if gs.GameRuleChangedSince("dodaylightcycle", true, sent) {
+		t.Error("expected a different value not to confirm the rule")
+	}
+	if gs.GameRuleChangedSince("dodaylightcycle", false, time.Now()) {
+		t.Error("expected a change before since not to count")
+	}
+}
+
+func TestIntercept_UpdateSubChunkBlocks(t *testing.T) {
+	gs := NewGameState()
+	gs.LearnBlock(100, "minecraft:stone")
+	gs.LearnBlock(101, "minecraft:water")
+
+	interceptServerPacket(&packet.UpdateSubChunkBlocks{
+		Position: protocol.BlockPos{0, 4, 0},
+		Blocks: []protocol.BlockChangeEntry{
+			{BlockPos: protocol.BlockPos{1, 64, 2}, BlockRuntimeID: 100},
+			{BlockPos: protocol.BlockPos{3, 65, 4}, BlockRuntimeID: 0},
+			{BlockPos: protocol.BlockPos{-5, 70, 15}, BlockRuntimeID: 100},
+		},
+		Extra: []protocol.BlockChangeEntry{
+			{BlockPos: protocol.BlockPos{7, 64, 7}, BlockRuntimeID: 101},
+		},
+	}, gs)
+
+	tests := []struct {
+		pos      protocol.BlockPos
+		expected uint32
+	}{
+		{protocol.BlockPos{1, 64, 2}, 100},
+		{protocol.BlockPos{3, 65, 4}, 0},
+		{protocol.BlockPos{-5, 70, 15}, 100},
+	}
+	for _, tt := range tests {
+		e, ok := gs.Blocks().Get(tt.pos)
+		if !ok {
+			t.Errorf("expected block at %v to be cached", tt.pos)
+			continue
+		}
+		if e.RuntimeID != tt.expected {
+			t.Errorf("block at %v: expected runtime ID %d, got %d", tt.pos, tt.expected, e.RuntimeID)
+		}
+	}
+	if _, ok := gs.Blocks().Get(protocol.BlockPos{7, 64, 7}); ok {
+		t.Error("expected layer 1 change not to be cached")
+	}
+	if n := gs.Blocks().Len(); n != 3 {
+		t.Errorf("expected 3 cached blocks, got %d", n)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// IsLoadedResult reports what the block cache holds for a coordinate. Loaded means
+// sub-chunks of the chunk column are cached; BlockKnown means the block itself is,
+// either from a sub-chunk or an individual block update.
+type IsLoadedResult struct {
+	Position      [3]int32   `json:"position"`
+	Chunk         [2]int32   `json:"chunk"`
+	Dimension     string     `json:"dimension"`
+	Loaded        bool       `json:"loaded"`
+	BlockKnown    bool       `json:"block_known"`
+	SubChunks     int        `json:"sub_chunks,omitempty"`
+	LoadedAt      *time.Time `json:"loaded_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	AgeSecs       float64    `json:"age_seconds,omitempty"` // since the last load or update
+	ChunkDistance int32      `json:"chunk_distance"`        // in chunks, along the farther axis
+	BlockDistance float64    `json:"block_distance"`        // horizontal, from the player to the coordinate
+	WithinRadius  bool       `json:"within_radius"`         // inside the chunk radius the cache keeps
+}
+
+// isLoaded checks whether the block cache of the player's dimension has data for pos.
+func isLoaded(state *GameState, pos protocol.BlockPos, now time.Time) IsLoadedResult {
+	x, _, z, _, _, dim := state.Position()
+	chunk := protocol.ChunkPos{pos[0] >> 4, pos[2] >> 4}
+	_, radius := state.ChunkParsing()
+
+	dcx := chunk[0] - int32(math.Floor(float64(x)))>>4
+	dcz := chunk[1] - int32(math.Floor(float64(z)))>>4
+	dist := max(abs32(dcx), abs32(dcz))
+	result := IsLoadedResult{
+		Position:      [3]int32{pos[0], pos[1], pos[2]},
+		Chunk:         [2]int32{chunk[0], chunk[1]},
+		Dimension:     dimensionName(dim),
+		ChunkDistance: dist,
+		BlockDistance: math.Hypot(float64(pos[0])+0.5-float64(x), float64(pos[2])+0.5-float64(z)),
+		WithinRadius:  dist <= radius,
+	}
+
+	blocks := state.BlocksIn(dim)
+	_, result.BlockKnown = blocks.Get(pos)
+	col, ok := blocks.Column(chunk)
+	if !ok {
+		return result
+	}
+	result.Loaded = true
+	result.SubChunks = col.SubChunks
+	result.LoadedAt, result.UpdatedAt = &col.Loaded, &col.Updated
+	result.AgeSecs = now.Sub(col.Updated).Seconds()
+	return result
+}
+
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func testLayer() *palettedStorage {
+	return &palettedStorage{palette: []uint32{0}, indices: make([]uint16, 4096)}
+}
+
+func TestBlockCache_Column(t *testing.T) {
+	c := NewBlockCache(DefaultBlockCacheSize)
+	if _, ok := c.Column(protocol.ChunkPos{0, 0}); ok {
+		t.Fatal("expected no column in an empty cache")
+	}
+	c.SetSubChunk(protocol.SubChunkPos{0, 0, 0}, testLayer())
+	c.SetSubChunk(protocol.SubChunkPos{0, 1, 0}, testLayer())
+	col, ok := c.Column(protocol.ChunkPos{0, 0})
+	if !ok {
+		t.Fatal("expected column to be loaded")
+	}
+	if col.SubChunks != 2 {
+		t.Errorf("expected 2 sub-chunks, got %d", col.SubChunks)
+	}
+
+	time.Sleep(time.Millisecond)
+	c.SetSubChunk(protocol.SubChunkPos{0, 1, 0}, testLayer())
+	if col2, _ := c.Column(protocol.ChunkPos{0, 0}); !col2.Loaded.Equal(col.Loaded) || col2.SubChunks != 2 {
+		t.Errorf("expected a re-sent sub-chunk to keep the load time and count, got %+v", col2)
+	}
+
+	time.Sleep(time.Millisecond)
+	c.Set(protocol.BlockPos{1, 2, 3}, 5)
+	if col2, _ := c.Column(protocol.ChunkPos{0, 0}); !col2.Updated.After(col.Updated) {
+		t.Error("expected block update to bump the column's update time")
+	}
+
+	// Columns go with their sub-chunks once the player moves out of the radius
+	c.SetSubChunkRadius(1)
+	c.SetCenter(protocol.BlockPos{100, 64, 0})
+	if _, ok := c.Column(protocol.ChunkPos{0, 0}); ok {
+		t.Error("expected evicted column to be gone")
+	}
+	if c.SubChunkCount() != 0 {
+		t.Errorf("expected no sub-chunks left, got %d", c.SubChunkCount())
+	}
+
+	// Sub-chunks outside the radius are not stored unless pinned
+	c.SetSubChunk(protocol.SubChunkPos{0, 0, 0}, testLayer())
+	if _, ok := c.Column(protocol.ChunkPos{0, 0}); ok {
+		t.Error("expected a distant sub-chunk not to be stored")
+	}
+	c.SetPinnedSubChunk(protocol.SubChunkPos{0, 0, 0}, testLayer())
+	if col, ok := c.Column(protocol.ChunkPos{0, 0}); !ok || col.SubChunks != 1 {
+		t.Errorf("expected the pinned sub-chunk to be stored, got %+v (ok=%v)", col, ok)
+	}
+	c.UnpinSubChunks()
+	if _, ok := c.Column(protocol.ChunkPos{0, 0}); ok {
+		t.Error("expected the unpinned distant sub-chunk to be dropped")
+	}
+}
+
+func TestIsLoaded(t *testing.T) {
+	gs := NewGameState()
+	gs.SetChunkParsing(true, 4)
+	gs.UpdatePosition(8.5, 65.62, 8.5, 0, 0)
+
+	now := time.Now()
+	r := isLoaded(gs, protocol.BlockPos{40, 64, 8}, now)
+	if r.Loaded || r.BlockKnown {
+		t.Errorf("expected nothing loaded, got %+v", r)
+	}
+	if r.Chunk != [2]int32{2, 0} || r.ChunkDistance != 2 || !r.WithinRadius {
+		t.Errorf("unexpected chunk fields %+v", r)
+	}
+	if r.BlockDistance != 32 {
+		t.Errorf("expected block distance 32, got %v", r.BlockDistance)
+	}
+
+	gs.BlocksIn(0).SetSubChunk(protocol.SubChunkPos{2, 4, 0}, testLayer())
+	r = isLoaded(gs, protocol.BlockPos{40, 64, 8}, time.Now().Add(time.Second))
+	if !r.Loaded || !r.BlockKnown || r.SubChunks != 1 || r.LoadedAt == nil {
+		t.Errorf("expected loaded column, got %+v", r)
+	}
+	if r.AgeSecs < 1 {
+		t.Errorf("expected age of at least 1s, got %v", r.AgeSecs)
+	}
+
+	if r := isLoaded(gs, protocol.BlockPos{-200, 64, 8}, now); r.WithinRadius || r.ChunkDistance != 13 {
+		t.Errorf("expected distant chunk outside the radius, got %+v", r)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Page sizes for get_item_registry.
+const (
+	defaultItemRegistryLimit = 100
+	maxItemRegistryLimit     = 1000
+)
+
+// ItemRegistryEntry is one item name and its network ID on the connected Realm.
+type ItemRegistryEntry struct {
+	Name      string `json:"name"`
+	NetworkID int32  `json:"network_id"`
+}
+
+// ItemRegistryPage is a page of the item registry, sorted by name. Total counts
+// all entries matching the filter; NextOffset is set when more remain.
+type ItemRegistryPage struct {
+	Total      int                 `json:"total"`
+	Offset     int                 `json:"offset"`
+	Items      []ItemRegistryEntry `json:"items"`
+	NextOffset *int                `json:"next_offset,omitempty"`
+}
+
+// ItemRegistry returns up to limit registry entries whose name contains filter
+// (case-insensitive), starting at offset.
+func (gs *GameState) ItemRegistry(filter string, offset, limit int) ItemRegistryPage {
+	gs.mu.RLock()
+	filter = strings.ToLower(filter)
+	var matches []ItemRegistryEntry
+	for id, name := range gs.itemRegistry {
+		if strings.Contains(strings.ToLower(name), filter) {
+			matches = append(matches, ItemRegistryEntry{Name: name, NetworkID: id})
+		}
+	}
+	gs.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+	page := ItemRegistryPage{Total: len(matches), Offset: offset, Items: []ItemRegistryEntry{}}
+	if offset >= len(matches) {
+		return page
+	}
+	end := min(offset+limit, len(matches))
+	page.Items = matches[offset:end]
+	if end < len(matches) {
+		page.NextOffset = &end
+	}
+	return page
+}
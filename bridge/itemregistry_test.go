@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestItemRegistry(t *testing.T) {
+	gs := NewGameState()
+	gs.InitFromGameData(minecraft.GameData{
+		Items: []protocol.ItemEntry{
+			{RuntimeID: 1, Name: "minecraft:stone"},
+			{RuntimeID: 2, Name: "minecraft:oak_planks"},
+			{RuntimeID: 3, Name: "minecraft:stone_bricks"},
+			{RuntimeID: 4, Name: "minecraft:cobblestone"},
+		},
+	})
+
+	page := gs.ItemRegistry("STONE", 0, 2)
+	if page.Total != 3 {
+		t.Errorf("expected 3 matches, got %d", page.Total)
+	}
+	if len(page.Items) != 2 || page.Items[0].Name != "minecraft:cobblestone" || page.Items[1].NetworkID != 1 {
+		t.Errorf("unexpected first page: %+v", page.Items)
+	}
+	if page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Fatalf("expected next offset 2, got %v", page.NextOffset)
+	}
+
+	page = gs.ItemRegistry("stone", *page.NextOffset, 2)
+	if len(page.Items) != 1 || page.Items[0].Name != "minecraft:stone_bricks" || page.NextOffset != nil {
+		t.Errorf("unexpected last page: %+v (next=%v)", page.Items, page.NextOffset)
+	}
+
+	if page := gs.ItemRegistry("", 10, 5); page.Total != 4 || len(page.Items) != 0 {
+		t.Errorf("expected empty page past the end, got %+v", page)
+	}
+}
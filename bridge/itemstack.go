@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// itemStackResponseTimeout bounds how long inventory actions wait for the server to
+// accept or reject an item stack request.
+const itemStackResponseTimeout = 3 * time.Second
+
+// MoveItemResult reports the outcome of a move_item request.
+type MoveItemResult struct {
+	Action      string `json:"action"` // "place", "take", or "swap"
+	Item        string `json:"item"`
+	Count       int    `json:"count"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// slotInfo builds the request slot info for a window slot holding item.
+func slotInfo(w inventoryWindow, slot int, item protocol.ItemInstance) protocol.StackRequestSlotInfo {
+	return protocol.StackRequestSlotInfo{
+		Container:      protocol.FullContainerName{ContainerID: w.ContainerID},
+		Slot:           byte(slot),
+		StackNetworkID: item.StackNetworkID,
+	}
+}
+
+// newPlaceAction builds an action moving count items from src to dst.
+func newPlaceAction(count byte, src, dst protocol.StackRequestSlotInfo) *protocol.PlaceStackRequestAction {
+	a := &protocol.PlaceStackRequestAction{}
+	a.Count, a.Source, a.Destination = count, src, dst
+	return a
+}
+
+// newTakeAction builds an action taking count items from src into dst (the cursor).
+func newTakeAction(count byte, src, dst protocol.StackRequestSlotInfo) *protocol.TakeStackRequestAction {
+	a := &protocol.TakeStackRequestAction{}
+	a.Count, a.Source, a.Destination = count, src, dst
+	return a
+}
+
+// sendItemStackRequest sends a single item stack request with the actions returned by
+// build and waits for the server's response, returning an error if it is rejected. build
+// receives the request ID, which crafting actions use as the stack ID of their output.
+func sendItemStackRequest(ctx context.Context, state *GameState, build func(requestID int32) []protocol.StackRequestAction) (protocol.ItemStackResponse, error) {
+	conn := state.ServerConn()
+	if conn == nil {
+		return protocol.ItemStackResponse{}, fmt.Errorf("server connection not available")
+	}
+	id, response := state.AddItemStackWaiter()
+	defer state.RemoveItemStackWaiter(id)
+
+	if err := conn.WritePacket(&packet.ItemStackRequest{
+		Requests: []protocol.ItemStackRequest{{RequestID: id, Actions: build(id)}},
+	}); err != nil {
+		return protocol.ItemStackResponse{}, fmt.Errorf("item stack request: %w", err)
+	}
+
+	select {
+	case resp := <-response:
+		if resp.Status != protocol.ItemStackResponseStatusOK {
+			return resp, fmt.Errorf("server rejected item stack request (status %d)", resp.Status)
+		}
+		return resp, nil
+	case <-time.After(itemStackResponseTimeout):
+		return protocol.ItemStackResponse{}, fmt.Errorf("no item stack response within %s", itemStackResponseTimeout)
+	case <-ctx.Done():
+		return protocol.ItemStackResponse{}, ctx.Err()
+	}
+}
+
+// moveItem moves count items from a source window slot to a destination window slot.
+// A count of 0 moves the whole stack. If the destination holds a different item, the
+// two stacks are swapped instead. Slots are validated against the tracked inventory,
+// and the tracked inventory is updated once the server accepts the request.
+func moveItem(ctx context.Context, state *GameState, srcName string, srcSlot int, dstName string, dstSlot int, count int) (MoveItemResult, error) {
+	src, ok := inventoryWindows[srcName]
+	if !ok {
+		return MoveItemResult{}, fmt.Errorf("unknown source window %q", srcName)
+	}
+	dst, ok := inventoryWindows[dstName]
+	if !ok {
+		return MoveItemResult{}, fmt.Errorf("unknown destination window %q", dstName)
+	}
+	if !src.contains(srcSlot) {
+		return MoveItemResult{}, fmt.Errorf("source slot %d outside %s slots %d-%d", srcSlot, srcName, src.FirstSlot, src.LastSlot)
+	}
+	if !dst.contains(dstSlot) {
+		return MoveItemResult{}, fmt.Errorf("destination slot %d outside %s slots %d-%d", dstSlot, dstName, dst.FirstSlot, dst.LastSlot)
+	}
+	if src.WindowID == dst.WindowID && srcSlot == dstSlot {
+		return MoveItemResult{}, fmt.Errorf("source and destination are the same slot")
+	}
+
+	srcItem, ok := state.InventoryItem(src.WindowID, srcSlot)
+	if !ok {
+		return MoveItemResult{}, fmt.Errorf("%s slot %d is empty", srcName, srcSlot)
+	}
+	available := int(srcItem.Stack.Count)
+	if count <= 0 {
+		count = available
+	}
+	if count > available {
+		return MoveItemResult{}, fmt.Errorf("%s slot %d holds only %d items", srcName, srcSlot, available)
+	}
+	dstItem, dstFull := state.InventoryItem(dst.WindowID, dstSlot)
+
+	result := MoveItemResult{
+		Item:        state.ResolveItemName(srcItem.Stack.NetworkID),
+		Count:       count,
+		Source:      fmt.Sprintf("%s:%d", srcName, srcSlot),
+		Destination: fmt.Sprintf("%s:%d", dstName, dstSlot),
+	}
+	srcInfo, dstInfo := slotInfo(src, srcSlot, srcItem), slotInfo(dst, dstSlot, dstItem)
+
+	var action protocol.StackRequestAction
+	switch {
+	case dstFull && dstItem.Stack.NetworkID != srcItem.Stack.NetworkID:
+		result.Action = "swap"
+		result.Count = available
+		action = &protocol.SwapStackRequestAction{Source: srcInfo, Destination: dstInfo}
+	case dstName == "cursor":
+		result.Action = "take"
+		action = newTakeAction(byte(count), srcInfo, dstInfo)
+	default:
+		result.Action = "place"
+		action = newPlaceAction(byte(count), srcInfo, dstInfo)
+	}
+
+	resp, err := sendItemStackRequest(ctx, state, func(int32) []protocol.StackRequestAction {
+		return []protocol.StackRequestAction{action}
+	})
+	if err != nil {
+		return result, err
+	}
+	state.ApplyItemMove(src.WindowID, srcSlot, dst.WindowID, dstSlot, count, result.Action == "swap")
+	state.ApplyItemStackResponse(resp)
+	return result, nil
+}
+
+// DropItemResult reports the outcome of a drop_item request.
+type DropItemResult struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+	Slot  int    `json:"slot"`
+}
+
+// findItemSlot returns the first hotbar/inventory slot holding the named item.
+func findItemSlot(state *GameState, item string) (int, bool) {
+	for _, s := range state.WindowSlots(protocol.WindowIDInventory, 0, inventoryWindows["inventory"].LastSlot) {
+		if s.Item == item {
+			return s.Slot, true
+		}
+	}
+	return 0, false
+}
+
+// dropItem drops count items from a hotbar/inventory slot onto the ground. The slot may
+// be given directly, found by item name, or both, in which case the slot must hold that
+// item. A count of 0 drops the whole stack.
+func dropItem(ctx context.Context, state *GameState, slot int, item string, count int) (DropItemResult, error) {
+	if slot < 0 {
+		if item == "" {
+			return DropItemResult{}, fmt.Errorf("either slot or item is required")
+		}
+		var ok bool
+		if slot, ok = findItemSlot(state, item); !ok {
+			return DropItemResult{}, fmt.Errorf("no %s in inventory", item)
+		}
+	}
+	if slot > inventoryWindows["inventory"].LastSlot {
+		return DropItemResult{}, fmt.Errorf("slot %d outside inventory slots 0-%d", slot, inventoryWindows["inventory"].LastSlot)
+	}
+	held, ok := state.InventoryItem(protocol.WindowIDInventory, slot)
+	if !ok {
+		return DropItemResult{}, fmt.Errorf("slot %d is empty", slot)
+	}
+	name := state.ResolveItemName(held.Stack.NetworkID)
+	if item != "" && name != item {
+		return DropItemResult{}, fmt.Errorf("slot %d holds %s, not %s", slot, name, item)
+	}
+	if count <= 0 {
+		count = int(held.Stack.Count)
+	}
+	if count > int(held.Stack.Count) {
+		return DropItemResult{}, fmt.Errorf("slot %d holds only %d items", slot, held.Stack.Count)
+	}
+
+	src := slotInfo(playerInventoryWindow(slot), slot, held)
+	resp, err := sendItemStackRequest(ctx, state, func(int32) []protocol.StackRequestAction {
+		return []protocol.StackRequestAction{&protocol.DropStackRequestAction{Count: byte(count), Source: src}}
+	})
+	if err != nil {
+		return DropItemResult{}, err
+	}
+	held.Stack.Count -= uint16(count)
+	if held.Stack.Count == 0 {
+		held = protocol.ItemInstance{}
+	}
+	state.UpdateInventorySlot(protocol.WindowIDInventory, slot, held)
+	state.ApplyItemStackResponse(resp)
+	return DropItemResult{Item: name, Count: count, Slot: slot}, nil
+}
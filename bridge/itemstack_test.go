@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func stackOf(networkID int32, count uint16, stackID int32) protocol.ItemInstance {
+	return protocol.ItemInstance{
+		StackNetworkID: stackID,
+		Stack:          protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: networkID}, Count: count},
+	}
+}
+
+func TestMoveItem_Validation(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 0, stackOf(5, 10, 1))
+
+	tests := []struct {
+		name                 string
+		srcWindow, dstWindow string
+		srcSlot, dstSlot     int
+		count                int
+		wantErr              string
+	}{
+		{"unknown window", "backpack", "hotbar", 0, 1, 0, "unknown source window"},
+		{"slot out of range", "hotbar", "inventory", 9, 10, 0, "outside hotbar slots"},
+		{"same slot", "hotbar", "hotbar", 0, 0, 0, "same slot"},
+		{"empty source", "hotbar", "hotbar", 1, 2, 0, "is empty"},
+		{"too many", "hotbar", "hotbar", 0, 1, 11, "holds only 10"},
+		{"no connection", "hotbar", "inventory", 0, 9, 5, "server connection not available"},
+	}
+	for _, tt := range tests {
+		_, err := moveItem(context.Background(), gs, tt.srcWindow, tt.srcSlot, tt.dstWindow, tt.dstSlot, tt.count)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestApplyItemMove(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 0, stackOf(5, 10, 1))
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 9, stackOf(5, 2, 2))
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 10, stackOf(7, 1, 3))
+
+	// Partial move onto a matching stack.
+	gs.ApplyItemMove(protocol.WindowIDInventory, 0, protocol.WindowIDInventory, 9, 4, false)
+	if item, _ := gs.InventoryItem(protocol.WindowIDInventory, 0); item.Stack.Count != 6 {
+		t.Errorf("expected 6 items left in source, got %d", item.Stack.Count)
+	}
+	if item, _ := gs.InventoryItem(protocol.WindowIDInventory, 9); item.Stack.Count != 6 || item.StackNetworkID != 2 {
+		t.Errorf("expected 6 items in destination keeping stack ID 2, got %+v", item)
+	}
+
+	// Full move into an empty slot empties the source.
+	gs.ApplyItemMove(protocol.WindowIDInventory, 0, protocol.WindowIDUI, 0, 6, false)
+	if _, ok := gs.InventoryItem(protocol.WindowIDInventory, 0); ok {
+		t.Error("expected source slot to be empty")
+	}
+	if item, ok := gs.InventoryItem(protocol.WindowIDUI, 0); !ok || item.Stack.Count != 6 {
+		t.Errorf("expected 6 items on the cursor, got %+v", item)
+	}
+
+	// Swap.
+	gs.ApplyItemMove(protocol.WindowIDInventory, 9, protocol.WindowIDInventory, 10, 6, true)
+	if item, _ := gs.InventoryItem(protocol.WindowIDInventory, 9); item.Stack.NetworkID != 7 {
+		t.Errorf("expected item 7 in slot 9 after swap, got %d", item.Stack.NetworkID)
+	}
+}
+
+func TestDeliverItemStackResponse(t *testing.T) {
+	gs := NewGameState()
+	id, ch := gs.AddItemStackWaiter()
+	next, _ := gs.AddItemStackWaiter()
+	if id != firstBridgeStackRequestID || next != id-2 {
+		t.Errorf("expected request IDs %d and %d, got %d and %d", firstBridgeStackRequestID, firstBridgeStackRequestID-2, id, next)
+	}
+
+	if gs.DeliverItemStackResponse(protocol.ItemStackResponse{RequestID: -1}) {
+		t.Error("expected client request IDs to be ignored")
+	}
+	if !gs.DeliverItemStackResponse(protocol.ItemStackResponse{RequestID: id, Status: protocol.ItemStackResponseStatusError}) {
+		t.Fatal("expected response to be delivered")
+	}
+	if resp := <-ch; resp.Status != protocol.ItemStackResponseStatusError {
+		t.Errorf("expected error status, got %d", resp.Status)
+	}
+}
+
+func TestOwnItemStackResponse(t *testing.T) {
+	gs := NewGameState()
+	id, ch := gs.AddItemStackWaiter()
+
+	client := &packet.ItemStackResponse{Responses: []protocol.ItemStackResponse{{RequestID: -1}}}
+	if ownServerResponse(client, gs) || len(client.Responses) != 1 {
+		t.Errorf("expected the client's response to be relayed, got %+v", client.Responses)
+	}
+
+	own := &packet.ItemStackResponse{Responses: []protocol.ItemStackResponse{{RequestID: id}}}
+	interceptServerPacket(own, gs)
+	if !ownServerResponse(own, gs) {
+		t.Error("expected the response to the bridge's request to be held back")
+	}
+	if resp := <-ch; resp.RequestID != id {
+		t.Errorf("expected the response delivered to the waiter, got %+v", resp)
+	}
+
+	// A late response, after the waiter gave up, is still the bridge's.
+	mixed := &packet.ItemStackResponse{Responses: []protocol.ItemStackResponse{{RequestID: -3}, {RequestID: id - 2}}}
+	if ownServerResponse(mixed, gs) {
+		t.Error("expected a packet with the client's response to be relayed")
+	}
+	if len(mixed.Responses) != 1 || mixed.Responses[0].RequestID != -3 {
+		t.Errorf("expected only the client's response left, got %+v", mixed.Responses)
+	}
+}
+
+func TestApplyItemStackResponse(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 3, stackOf(5, 10, 1))
+	gs.ApplyItemStackResponse(protocol.ItemStackResponse{
+		ContainerInfo: []protocol.StackResponseContainerInfo{{
+			Container: protocol.FullContainerName{ContainerID: protocol.ContainerHotBar},
+			SlotInfo:  []protocol.StackResponseSlotInfo{{Slot: 3, HotbarSlot: 3, Count: 8, StackNetworkID: 42}},
+		}},
+	})
+	item, _ := gs.InventoryItem(protocol.WindowIDInventory, 3)
+	if item.Stack.Count != 8 || item.StackNetworkID != 42 {
+		t.Errorf("expected count 8 and stack ID 42, got %+v", item)
+	}
+}
+
+func TestDropItem_Validation(t *testing.T) {
+	gs := NewGameState()
+	gs.mu.Lock()
+	gs.itemRegistry[5] = "minecraft:dirt"
+	gs.itemRegistry[7] = "minecraft:stone"
+	gs.mu.Unlock()
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 4, stackOf(5, 10, 1))
+
+	tests := []struct {
+		name    string
+		slot    int
+		item    string
+		count   int
+		wantErr string
+	}{
+		{"nothing given", -1, "", 0, "either slot or item"},
+		{"item not held", -1, "minecraft:stone", 0, "no minecraft:stone"},
+		{"empty slot", 3, "", 0, "slot 3 is empty"},
+		{"wrong item", 4, "minecraft:stone", 0, "holds minecraft:dirt"},
+		{"too many", 4, "", 11, "holds only 10"},
+		{"found by name, no connection", -1, "minecraft:dirt", 2, "server connection not available"},
+	}
+	for _, tt := range tests {
+		_, err := dropItem(context.Background(), gs, tt.slot, tt.item, tt.count)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Limits for load_area.
+const (
+	maxLoadAreaChunks      = 64 // chunk columns per call
+	defaultLoadAreaTimeout = 10 * time.Second
+	maxLoadAreaTimeout     = time.Minute
+)
+
+// LoadAreaResult reports the outcome of a load_area request, in sub-chunks.
+// Missing counts sub-chunks the server had not answered when the wait ended.
+type LoadAreaResult struct {
+	Chunks    int `json:"chunks"`
+	Requested int `json:"requested"`
+	Loaded    int `json:"loaded"`
+	AllAir    int `json:"all_air"`
+	NotFound  int `json:"not_found"`
+	Missing   int `json:"missing"`
+}
+
+// ownSubChunkResponseWindow is how long a response to a SubChunkRequest the bridge
+// sent is held back from the client. Later responses are relayed.
+const ownSubChunkResponseWindow = 2 * maxLoadAreaTimeout
+
+// subChunkRequestKey identifies the SubChunk response to a SubChunkRequest, which
+// carries the request's dimension and base position.
+type subChunkRequestKey struct {
+	dimension int32
+	pos       protocol.SubChunkPos
+}
+
+// RecordOwnSubChunkRequest records a SubChunkRequest the bridge is about to send,
+// so that the response is not relayed to the client, which never asked for it.
+func (gs *GameState) RecordOwnSubChunkRequest(dimension int32, pos protocol.SubChunkPos, now time.Time) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	for key, sent := range gs.ownSubChunkRequests {
+		if now.Sub(sent) > ownSubChunkResponseWindow {
+			delete(gs.ownSubChunkRequests, key)
+		}
+	}
+	if gs.ownSubChunkRequests == nil {
+		gs.ownSubChunkRequests = make(map[subChunkRequestKey]time.Time)
+	}
+	gs.ownSubChunkRequests[subChunkRequestKey{dimension, pos}] = now
+}
+
+// TakeOwnSubChunkResponse reports whether a SubChunk response with the given
+// dimension and base position answers a request the bridge sent, and forgets it.
+func (gs *GameState) TakeOwnSubChunkResponse(dimension int32, pos protocol.SubChunkPos, now time.Time) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	key := subChunkRequestKey{dimension, pos}
+	sent, ok := gs.ownSubChunkRequests[key]
+	if !ok {
+		return false
+	}
+	delete(gs.ownSubChunkRequests, key)
+	return now.Sub(sent) <= ownSubChunkResponseWindow
+}
+
+// subChunkLoad tracks the sub-chunks of a load_area request awaiting a SubChunk
+// response. done is closed once every one has been answered.
+type subChunkLoad struct {
+	dimension int32
+	pending   map[protocol.SubChunkPos]bool
+	result    LoadAreaResult
+	done      chan struct{}
+}
+
+// StartSubChunkLoad registers the sub-chunks about to be requested, replacing any
+// earlier load, and releases the sub-chunks pinned by earlier loads.
+func (gs *GameState) StartSubChunkLoad(dimension int32, chunks int, positions []protocol.SubChunkPos) *subChunkLoad {
+	gs.BlocksIn(dimension).UnpinSubChunks()
+	load := &subChunkLoad{
+		dimension: dimension,
+		pending:   make(map[protocol.SubChunkPos]bool, len(positions)),
+		result:    LoadAreaResult{Chunks: chunks, Requested: len(positions)},
+		done:      make(chan struct{}),
+	}
+	for _, pos := range positions {
+		load.pending[pos] = true
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.subChunkLoad = load
+	return load
+}
+
+// ClaimSubChunk records a SubChunk response entry against the active load and
+// reports whether the sub-chunk was requested by it.
+func (gs *GameState) ClaimSubChunk(dimension int32, pos protocol.SubChunkPos, result byte) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	load := gs.subChunkLoad
+	if load == nil || load.dimension != dimension || !load.pending[pos] {
+		return false
+	}
+	delete(load.pending, pos)
+	switch result {
+	case protocol.SubChunkResultSuccess:
+		load.result.Loaded++
+	case protocol.SubChunkResultSuccessAllAir:
+		load.result.AllAir++
+	default:
+		load.result.NotFound++
+	}
+	if len(load.pending) == 0 {
+		close(load.done)
+	}
+	return true
+}
+
+// FinishSubChunkLoad ends a load and returns its result.
+func (gs *GameState) FinishSubChunkLoad(load *subChunkLoad) LoadAreaResult {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.subChunkLoad == load {
+		gs.subChunkLoad = nil
+	}
+	result := load.result
+	result.Missing = len(load.pending)
+	return result
+}
+
+// areaChunks returns the chunk columns covering the blocks between two corners.
+func areaChunks(x1, z1, x2, z2 int32) []protocol.ChunkPos {
+	minX, maxX := min(x1, x2)>>4, max(x1, x2)>>4
+	minZ, maxZ := min(z1, z2)>>4, max(z1, z2)>>4
+	var chunks []protocol.ChunkPos
+	for cx := minX; cx <= maxX; cx++ {
+		for cz := minZ; cz <= maxZ; cz++ {
+			chunks = append(chunks, protocol.ChunkPos{cx, cz})
+		}
+	}
+	return chunks
+}
+
+// loadArea requests every sub-chunk of the chunk columns covering the area from the
+// server and waits until all are answered or the timeout passes. Loaded sub-chunks
+// are pinned in the block cache. The server only answers for terrain it has loaded,
+// so columns far from the player typically come back as not found.
+func loadArea(ctx context.Context, state *GameState, x1, z1, x2, z2 int32, timeout time.Duration) (LoadAreaResult, error) {
+	chunks := areaChunks(x1, z1, x2, z2)
+	if len(chunks) > maxLoadAreaChunks {
+		return LoadAreaResult{}, fmt.Errorf("area covers %d chunks, max %d", len(chunks), maxLoadAreaChunks)
+	}
+	conn := state.ServerConn()
+	if conn == nil {
+		return LoadAreaResult{}, fmt.Errorf("server connection not available")
+	}
+
+	_, _, _, _, _, dimension := state.Position()
+	minIndex := minSubChunkIndex(dimension)
+	minY, maxY := dimensionHeight(dimension)
+	count := int(maxY-minY) / 16
+
+	offsets := make([]protocol.SubChunkOffset, count)
+	for i := range offsets {
+		offsets[i] = protocol.SubChunkOffset{0, int8(i), 0}
+	}
+	var positions []protocol.SubChunkPos
+	for _, c := range chunks {
+		for i := range count {
+			positions = append(positions, protocol.SubChunkPos{c[0], minIndex + int32(i), c[1]})
+		}
+	}
+
+	load := state.StartSubChunkLoad(dimension, len(chunks), positions)
+	for _, c := range chunks {
+		base := protocol.SubChunkPos{c[0], minIndex, c[1]}
+		state.RecordOwnSubChunkRequest(dimension, base, time.Now())
+		if err := conn.WritePacket(&packet.SubChunkRequest{
+			Dimension: dimension,
+			Position:  base,
+			Offsets:   offsets,
+		}); err != nil {
+			state.FinishSubChunkLoad(load)
+			return LoadAreaResult{}, fmt.Errorf("SubChunkRequest: %w", err)
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-load.done:
+	case <-timer.C:
+	case <-ctx.Done():
+		state.FinishSubChunkLoad(load)
+		return LoadAreaResult{}, ctx.Err()
+	}
+	return state.FinishSubChunkLoad(load), nil
+}
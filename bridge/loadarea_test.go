@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestAreaChunks(t *testing.T) {
+	chunks := areaChunks(20, -1, 0, 15)
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d: %v", len(chunks), chunks)
+	}
+	if chunks[0] != (protocol.ChunkPos{0, -1}) || chunks[3] != (protocol.ChunkPos{1, 0}) {
+		t.Errorf("unexpected chunks %v", chunks)
+	}
+}
+
+func TestSubChunkLoad(t *testing.T) {
+	gs := NewGameState()
+	// Chunk parsing is off and the area is far from the player
+	far := []protocol.SubChunkPos{{40, 0, 40}, {40, 1, 40}, {40, 2, 40}}
+	load := gs.StartSubChunkLoad(0, 1, far)
+
+	var buf bytes.Buffer
+	encodeTestSubChunk(&buf, 0, 1, 9)
+	interceptServerPacket(&packet.SubChunk{
+		Position: protocol.SubChunkPos{40, 0, 40},
+		SubChunkEntries: []protocol.SubChunkEntry{
+			{Offset: protocol.SubChunkOffset{0, 0, 0}, Result: protocol.SubChunkResultSuccess, RawPayload: buf.Bytes()},
+			{Offset: protocol.SubChunkOffset{0, 1, 0}, Result: protocol.SubChunkResultSuccessAllAir},
+			{Offset: protocol.SubChunkOffset{1, 0, 0}, Result: protocol.SubChunkResultSuccess, RawPayload: buf.Bytes()},
+		},
+	}, gs)
+
+	select {
+	case <-load.done:
+		t.Fatal("expected load to wait for the last sub-chunk")
+	default:
+	}
+	if e, ok := gs.Blocks().Get(protocol.BlockPos{40*16 + 1, 2, 40*16 + 3}); !ok || e.RuntimeID != 9 {
+		t.Errorf("expected requested sub-chunk to be cached, got %d (ok=%v)", e.RuntimeID, ok)
+	}
+	if gs.Blocks().SubChunkCount() != 1 {
+		t.Errorf("expected only the requested sub-chunk cached, got %d", gs.Blocks().SubChunkCount())
+	}
+
+	interceptServerPacket(&packet.SubChunk{
+		Position: protocol.SubChunkPos{40, 2, 40},
+		SubChunkEntries: []protocol.SubChunkEntry{
+			{Result: protocol.SubChunkResultChunkNotFound},
+		},
+	}, gs)
+	select {
+	case <-load.done:
+	default:
+		t.Fatal("expected load to complete")
+	}
+
+	result := gs.FinishSubChunkLoad(load)
+	want := LoadAreaResult{Chunks: 1, Requested: 3, Loaded: 1, AllAir: 1, NotFound: 1}
+	if result != want {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+
+	// Pinned sub-chunks survive eviction of distant sub-chunks
+	gs.BlocksIn(0).SetSubChunk(protocol.SubChunkPos{0, 0, 0}, nil)
+	if gs.Blocks().SubChunkCount() != 2 {
+		t.Errorf("expected pinned sub-chunk kept, got %d sub-chunks", gs.Blocks().SubChunkCount())
+	}
+	gs.StartSubChunkLoad(0, 0, nil)
+	gs.BlocksIn(0).SetSubChunk(protocol.SubChunkPos{0, 0, 0}, nil)
+	if gs.Blocks().SubChunkCount() != 1 {
+		t.Errorf("expected unpinned sub-chunk evicted, got %d sub-chunks", gs.Blocks().SubChunkCount())
+	}
+}
+
+func TestOwnSubChunkResponse(t *testing.T) {
+	gs := NewGameState()
+	now := time.Now()
+	base := protocol.SubChunkPos{3, -4, 5}
+	gs.RecordOwnSubChunkRequest(0, base, now)
+
+	if ownServerResponse(&packet.SubChunk{Dimension: 0, Position: protocol.SubChunkPos{3, -4, 6}}, gs) {
+		t.Error("expected a response to another request to be relayed")
+	}
+	if ownServerResponse(&packet.SubChunk{Dimension: 1, Position: base}, gs) {
+		t.Error("expected a response in another dimension to be relayed")
+	}
+	if !ownServerResponse(&packet.SubChunk{Dimension: 0, Position: base}, gs) {
+		t.Error("expected the response to the bridge's request to be held back")
+	}
+	if ownServerResponse(&packet.SubChunk{Dimension: 0, Position: base}, gs) {
+		t.Error("expected a second response to be relayed")
+	}
+
+	gs.RecordOwnSubChunkRequest(0, base, now)
+	if gs.TakeOwnSubChunkResponse(0, base, now.Add(ownSubChunkResponseWindow+time.Second)) {
+		t.Error("expected a late response to be relayed")
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,8 +18,27 @@ import (
 func main() {
 	listenAddr := flag.String("listen", ":19132", "Address for the Minecraft proxy listener")
 	invite := flag.String("invite", "", "Realm invite code (overrides REALM_INVITE env / .realm-invite file)")
+	realmName := flag.String("realm-name", "", "Name of a Realm the account owns or has joined to connect to, instead of an invite code (case-insensitive)")
+	tokenFile := flag.String("token-file", "", "File caching the Xbox Live token, e.g. one per account (overrides REALM_TOKEN_FILE env; default .realm-token)")
 	authOnly := flag.Bool("auth", false, "Authenticate with Xbox Live and exit")
 	verbosePackets := flag.Bool("verbose-packets", false, "Enable verbose building packet logging")
+	parseChunks := flag.Bool("parse-chunks", false, "Decode chunk data into the block cache (CPU intensive)")
+	blockStatesFile := flag.String("block-states", "", "Canonical block state table (canonical_block_states.nbt) used to name cached blocks on servers with sequential block runtime IDs")
+	chunkRadius := flag.Int("chunk-radius", DefaultChunkRadius, "Radius in chunks around the player to decode when -parse-chunks is set")
+	invalidPackets := flag.String("invalid-packets", InvalidPacketsOff, "Validate relayed packets by re-serializing them, and drop or forward (logging them) those that fail; off relays them unchecked")
+	resourcePacks := flag.String("resource-packs", ResourcePacksDownload, "How to answer the Realm's resource pack negotiation: download (fetch all packs) or skip (claim they are present)")
+	strictProtocol := flag.Bool("strict-protocol", false, "Refuse clients whose protocol version differs from the proxy's")
+	displayName := flag.String("display-name", "", "Display name to use in outgoing chat instead of the account's name")
+	presetsFile := flag.String("build-presets-file", "", "JSON file to load build presets from and save them to (default: presets last for the session only)")
+	waypointsFile := flag.String("waypoints-file", "", "JSON file to load waypoints from and save them to (default: waypoints last for the session only)")
+	followTransfers := flag.Bool("follow-transfers", false, "When the server sends a Transfer, dial the new server and keep relaying the client to it instead of letting the client leave the proxy")
+	reconnectAttempts := flag.Int("reconnect-attempts", DefaultReconnectAttempts, "Times to try reconnecting to the Realm when it drops while the client stays connected (0 = end the session)")
+	wait := flag.Duration("wait", 0, "Keep polling a sleeping Realm for up to this long while it starts (e.g. 3m) instead of giving up after 10 join attempts")
+	idleThrottle := flag.Duration("idle-throttle", 0, "Slow the keep-alive PlayerAuthInput from every tick to once a second after no tool call for this long (e.g. 5m; 0 = never)")
+	mcpHTTP := flag.String("mcp-http", "", "Serve MCP over HTTP with Server-Sent Events on this address (e.g. :8080, bound to 127.0.0.1 without a host) instead of stdio, for remote agents")
+	mcpToken := flag.String("mcp-token", "", "Bearer token MCP HTTP clients must send (required with -mcp-http; default: $MCP_HTTP_TOKEN)")
+	chatHistory := flag.Int("chat-history", DefaultChatHistory, "Number of chat messages kept for get_chat_history")
+	blockCacheSize := flag.Int("block-cache-size", DefaultBlockCacheSize, "Maximum number of blocks kept in the block cache (0 = unbounded)")
 	flag.Parse()
 
 	// Log to file (stdout is MCP stdio, stderr may not be visible)
@@ -31,7 +52,12 @@ func main() {
 	})))
 
 	// Load Xbox Live token
-	tokenSource, err := getTokenSource()
+	tokenPath := resolveTokenFile(*tokenFile)
+	if err := checkTokenFileWritable(tokenPath); err != nil {
+		slog.Error("token file is not writable", "file", tokenPath, "error", err)
+		os.Exit(1)
+	}
+	tokenSource, err := getTokenSource(tokenPath)
 	if err != nil {
 		slog.Error("authentication failed", "error", err)
 		os.Exit(1)
@@ -41,10 +67,11 @@ func main() {
 		fmt.Fprintln(os.Stderr, "Authentication successful")
 		os.Exit(0)
 	}
+	xbl := newXBLTokenCache(tokenSource, realmsRelyingParty)
 
-	// Resolve realm invite code
+	// Resolve realm invite code (optional fallback when -realm-name is set)
 	inviteCode := *invite
-	if inviteCode == "" {
+	if inviteCode == "" && *realmName == "" {
 		var err error
 		inviteCode, err = getRealmInvite()
 		if err != nil {
@@ -53,40 +80,126 @@ func main() {
 		}
 	}
 
+	if *invalidPackets != InvalidPacketsOff && *invalidPackets != InvalidPacketsDrop && *invalidPackets != InvalidPacketsForward {
+		fmt.Fprintf(os.Stderr, "invalid -invalid-packets value %q (want off, drop or forward)\n", *invalidPackets)
+		os.Exit(2)
+	}
+
+	if *resourcePacks != ResourcePacksDownload && *resourcePacks != ResourcePacksSkip {
+		fmt.Fprintf(os.Stderr, "invalid -resource-packs value %q (want download or skip)\n", *resourcePacks)
+		os.Exit(2)
+	}
+
+	mcpHTTPToken := *mcpToken
+	if mcpHTTPToken == "" {
+		mcpHTTPToken = os.Getenv("MCP_HTTP_TOKEN")
+	}
+	if *mcpHTTP != "" && mcpHTTPToken == "" {
+		fmt.Fprintln(os.Stderr, "-mcp-http requires a bearer token: set -mcp-token or MCP_HTTP_TOKEN")
+		os.Exit(2)
+	}
+
+	if *chatHistory < 1 {
+		fmt.Fprintf(os.Stderr, "invalid -chat-history value %d (must be at least 1)\n", *chatHistory)
+		os.Exit(2)
+	}
+
+	if *displayName != "" {
+		if err := validateDisplayName(*displayName); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -display-name: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
 	// Create game state
 	state := NewGameState()
 	state.SetVerbosePacketLog(*verbosePackets)
+	state.SetBlockCacheSize(*blockCacheSize)
+	state.SetChatHistorySize(*chatHistory)
+	state.SetInvalidPacketMode(*invalidPackets)
+	state.SetStrictProtocol(*strictProtocol)
+	state.SetResourcePackMode(*resourcePacks)
+	state.SetDisplayNameOverride(*displayName)
+	state.SetFollowTransfers(*followTransfers)
+	state.SetReconnectAttempts(*reconnectAttempts)
+	state.SetRealmWait(*wait)
+	state.SetIdleThrottle(*idleThrottle)
+	if *presetsFile != "" {
+		if err := state.LoadBuildPresets(*presetsFile); err != nil {
+			slog.Error("failed to load build presets", "file", *presetsFile, "error", err)
+			os.Exit(1)
+		}
+	}
+	if *waypointsFile != "" {
+		if err := state.LoadWaypoints(*waypointsFile); err != nil {
+			slog.Error("failed to load waypoints", "file", *waypointsFile, "error", err)
+			os.Exit(1)
+		}
+	}
+	state.SetChunkParsing(*parseChunks, *chunkRadius)
+	if *blockStatesFile != "" {
+		states, err := loadBlockStates(*blockStatesFile)
+		if err != nil {
+			slog.Error("failed to load block states", "file", *blockStatesFile, "error", err)
+			os.Exit(1)
+		}
+		state.SetBlockStates(states)
+	}
 
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		"minecraft",
 		"1.0.0",
 		server.WithToolCapabilities(false),
+		server.WithPromptCapabilities(false),
+		server.WithToolHandlerMiddleware(activityMiddleware(state)),
+		server.WithToolHandlerMiddleware(shutdownMiddleware(state)),
+		server.WithToolHandlerMiddleware(permissionMiddleware(state)),
 	)
 
 	// Register all tools
 	registerQueryTools(mcpServer, state)
 	registerActionTools(mcpServer, state)
+	registerPrompts(mcpServer)
 
-	// Setup context with signal handling
+	// Setup context with signal handling. On a signal, tool calls are refused and the
+	// active operation gets a bounded grace period before the proxy is torn down.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+	shutdown := sync.OnceFunc(func() {
+		slog.Info("shutting down...")
+		gracefulShutdown(state, shutdownGracePeriod)
+		cancel()
+		stopServing()
+	})
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigCh
-		slog.Info("shutting down...")
-		cancel()
+		shutdown()
 	}()
 
 	// Start proxy in background goroutine
-	go startProxy(ctx, *listenAddr, inviteCode, tokenSource, state)
+	proxyDone := make(chan struct{})
+	go func() {
+		defer close(proxyDone)
+		startProxy(ctx, *listenAddr, realmTarget{Name: *realmName, InviteCode: inviteCode}, tokenSource, xbl, state)
+	}()
 
-	// Serve MCP over stdio (blocks)
-	slog.Info("MCP server starting on stdio")
-	if err := server.ServeStdio(mcpServer); err != nil {
+	// Serve MCP (blocks until stdin closes, the HTTP listener fails, or shutdown)
+	err = serveMCP(serveCtx, mcpServer, *mcpHTTP, mcpHTTPToken)
+	if err != nil && serveCtx.Err() == nil {
 		slog.Error("MCP server error", "error", err)
 		os.Exit(1)
 	}
+	shutdown()
+
+	// Give the proxy time to close its connections
+	select {
+	case <-proxyDone:
+	case <-time.After(shutdownCancelWait):
+	}
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serveMCP serves the MCP server until ctx is cancelled or the transport ends.
+// With an empty httpAddr it speaks stdio, returning when stdin closes; otherwise
+// it serves HTTP with Server-Sent Events on httpAddr so a remote agent holding
+// token can connect.
+func serveMCP(ctx context.Context, mcpServer *server.MCPServer, httpAddr, token string) error {
+	if httpAddr == "" {
+		slog.Info("MCP server starting on stdio")
+		return server.NewStdioServer(mcpServer).Listen(ctx, os.Stdin, os.Stdout)
+	}
+	if token == "" {
+		return errors.New("MCP HTTP requires a bearer token")
+	}
+	ln, err := net.Listen("tcp", mcpListenAddress(httpAddr))
+	if err != nil {
+		return fmt.Errorf("MCP HTTP listen: %w", err)
+	}
+	slog.Info("MCP server starting on HTTP", "addr", ln.Addr().String(), "sse", "/sse", "message", "/message")
+	return serveMCPHTTP(ctx, mcpServer, ln, token)
+}
+
+// mcpListenAddress binds an address without a host to the loopback interface, so
+// the MCP server is only exposed to other machines when asked for explicitly.
+func mcpListenAddress(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host != "" {
+		return addr
+	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// requireBearer rejects requests that do not carry token as a bearer token.
+func requireBearer(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveMCPHTTP serves the SSE transport on ln to clients holding token until ctx
+// is cancelled, then closes open sessions and waits up to shutdownCancelWait for
+// in-flight requests.
+func serveMCPHTTP(ctx context.Context, mcpServer *server.MCPServer, ln net.Listener, token string) error {
+	httpServer := &http.Server{}
+	sse := server.NewSSEServer(mcpServer, server.WithHTTPServer(httpServer))
+	httpServer.Handler = requireBearer(token, sse)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownCancelWait)
+	defer cancel()
+	if err := sse.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("MCP HTTP shutdown: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
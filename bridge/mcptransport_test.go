@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestServeMCPHTTP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveMCPHTTP(ctx, server.NewMCPServer("test", "1.0.0"), ln, "secret") }()
+
+	url := "http://" + ln.Addr().String() + "/sse"
+	for _, auth := range []string{"", "Bearer wrong"} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET /sse: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: expected 401, got %d", auth, resp.StatusCode)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /sse: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "event: endpoint") {
+		t.Errorf("expected endpoint event, got %q (err=%v)", line, err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(shutdownCancelWait + time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestServeMCP_BadAddress(t *testing.T) {
+	if err := serveMCP(context.Background(), server.NewMCPServer("test", "1.0.0"), "not an address", "secret"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+	if err := serveMCP(context.Background(), server.NewMCPServer("test", "1.0.0"), "127.0.0.1:0", ""); err == nil {
+		t.Error("expected error without a token")
+	}
+}
+
+func TestMCPListenAddress(t *testing.T) {
+	tests := []struct {
+		addr, expected string
+	}{
+		{":8080", "127.0.0.1:8080"},
+		{"0.0.0.0:8080", "0.0.0.0:8080"},
+		{"192.168.1.20:8080", "192.168.1.20:8080"},
+		{"not an address", "not an address"},
+	}
+	for _, tt := range tests {
+		if got := mcpListenAddress(tt.addr); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.addr, tt.expected, got)
+		}
+	}
+}
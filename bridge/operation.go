@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// OperationProgress reports the progress of a long-running operation such as
+// upload_structure or place_blocks.
+type OperationProgress struct {
+	ID            uint64    `json:"id"`
+	Name          string    `json:"name"`
+	Current       int       `json:"current"`
+	Total         int       `json:"total"`
+	Failures      int       `json:"failures"`
+	Reconnects    int       `json:"reconnects"`
+	StartedAt     time.Time `json:"started_at"`
+	ElapsedSecs   float64   `json:"elapsed_seconds"`
+	RatePerSecond float64   `json:"rate_per_second"`
+}
+
+// StartOperation registers name as the active long-running operation and returns its
+// ID and a context derived from ctx that CancelOperation cancels. The operation must
+// select on the returned context and call EndOperation when done. A newer operation
+// replaces the progress of an older one still running, which CancelOperation still
+// cancels.
+func (gs *GameState) StartOperation(ctx context.Context, name string, total int) (uint64, context.Context) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	ctx, cancel := context.WithCancel(ctx)
+	gs.nextOperationID++
+	if gs.operationCancels == nil {
+		gs.operationCancels = make(map[uint64]context.CancelFunc)
+	}
+	gs.operationCancels[gs.nextOperationID] = cancel
+	gs.operation = &OperationProgress{
+		ID:        gs.nextOperationID,
+		Name:      name,
+		Total:     total,
+		StartedAt: time.Now(),
+	}
+	return gs.nextOperationID, ctx
+}
+
+// UpdateOperation records the progress of operation id.
+func (gs *GameState) UpdateOperation(id uint64, current, failures int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.operation != nil && gs.operation.ID == id {
+		gs.operation.Current = current
+		gs.operation.Failures = failures
+	}
+}
+
+// SetOperationReconnects records how often operation id has waited for the Realm
+// session to come back, if it is the active operation.
+func (gs *GameState) SetOperationReconnects(id uint64, reconnects int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.operation != nil && gs.operation.ID == id {
+		gs.operation.Reconnects = reconnects
+	}
+}
+
+// EndOperation releases the context of operation id and clears its progress if it
+// is the active operation.
+func (gs *GameState) EndOperation(id uint64) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if cancel, ok := gs.operationCancels[id]; ok {
+		cancel()
+		delete(gs.operationCancels, id)
+	}
+	if gs.operation != nil && gs.operation.ID == id {
+		gs.operation = nil
+	}
+}
+
+// CancelOperation cancels every running operation and returns the progress of the
+// active one at the time of cancellation. ok is false if no operation is active.
+func (gs *GameState) CancelOperation() (progress OperationProgress, ok bool) {
+	gs.mu.Lock()
+	for _, cancel := range gs.operationCancels {
+		cancel()
+	}
+	gs.mu.Unlock()
+	return gs.Operation()
+}
+
+// Operation returns the progress of the active operation. ok is false if none is running.
+func (gs *GameState) Operation() (progress OperationProgress, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.operation == nil {
+		return OperationProgress{}, false
+	}
+	progress = *gs.operation
+	elapsed := time.Since(progress.StartedAt).Seconds()
+	progress.ElapsedSecs = elapsed
+	if elapsed > 0 {
+		progress.RatePerSecond = float64(progress.Current) / elapsed
+	}
+	return progress, true
+}
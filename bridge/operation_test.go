@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOperationProgress(t *testing.T) {
+	gs := NewGameState()
+	if _, ok := gs.Operation(); ok {
+		t.Fatal("expected no operation initially")
+	}
+
+	id, _ := gs.StartOperation(context.Background(), "upload_structure", 10)
+	gs.UpdateOperation(id, 4, 1)
+	p, ok := gs.Operation()
+	if !ok {
+		t.Fatal("expected an active operation")
+	}
+	if p.Name != "upload_structure" || p.Current != 4 || p.Total != 10 || p.Failures != 1 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+	gs.SetOperationReconnects(id, 2)
+	if p, _ := gs.Operation(); p.Reconnects != 2 || p.Failures != 1 {
+		t.Errorf("expected 2 reconnects kept apart from 1 failure, got %+v", p)
+	}
+
+	// A newer operation replaces the old one, which can no longer update or clear it.
+	newer, _ := gs.StartOperation(context.Background(), "place_blocks", 3)
+	gs.UpdateOperation(id, 9, 0)
+	gs.EndOperation(id)
+	if p, ok := gs.Operation(); !ok || p.ID != newer || p.Current != 0 {
+		t.Errorf("expected newer operation untouched, got %+v (ok=%v)", p, ok)
+	}
+
+	gs.EndOperation(newer)
+	if _, ok := gs.Operation(); ok {
+		t.Error("expected operation cleared after EndOperation")
+	}
+}
+
+func TestCancelOperation(t *testing.T) {
+	gs := NewGameState()
+	if _, ok := gs.CancelOperation(); ok {
+		t.Error("expected nothing to cancel")
+	}
+
+	id, ctx := gs.StartOperation(context.Background(), "upload_structure", 10)
+	gs.UpdateOperation(id, 7, 0)
+	p, ok := gs.CancelOperation()
+	if !ok || p.Current != 7 {
+		t.Errorf("expected cancelled progress at 7, got %+v (ok=%v)", p, ok)
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Error("expected operation context to be cancelled")
+	}
+	gs.EndOperation(id)
+}
+
+func TestCancelOperation_Replaced(t *testing.T) {
+	gs := NewGameState()
+	older, olderCtx := gs.StartOperation(context.Background(), "upload_structure", 10)
+	newer, newerCtx := gs.StartOperation(context.Background(), "place_blocks", 3)
+
+	if p, ok := gs.CancelOperation(); !ok || p.ID != newer {
+		t.Errorf("expected the newer operation's progress, got %+v (ok=%v)", p, ok)
+	}
+	for name, ctx := range map[string]context.Context{"older": olderCtx, "newer": newerCtx} {
+		select {
+		case <-ctx.Done():
+		default:
+			t.Errorf("expected the %s operation to be cancelled", name)
+		}
+	}
+
+	gs.EndOperation(older)
+	gs.EndOperation(newer)
+	if len(gs.operationCancels) != 0 {
+		t.Errorf("expected every cancel func released, got %d", len(gs.operationCancels))
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Adaptive pacing of place_blocks. The delay between placements shrinks while the
+// server keeps confirming them with UpdateBlock and doubles when confirmations go
+// missing. If the first placements are never confirmed, pacing falls back to a
+// fixed delay.
+const (
+	DefaultPlacementDelay = 100 * time.Millisecond // fixed delay and adaptive starting point
+	minPlacementDelay     = 10 * time.Millisecond
+	maxPlacementDelay     = time.Second
+	placementAckTimeout   = 2 * time.Second // a placement unconfirmed this long counts as missed
+	placementProbeCount   = 5               // placements without any confirmation before falling back
+)
+
+// PacingReport describes how place_blocks paced its placements.
+type PacingReport struct {
+	Mode            string  `json:"mode"` // adaptive or fixed
+	DelayMs         int64   `json:"delay_ms"`
+	FellBack        bool    `json:"fell_back,omitempty"` // adaptive pacing saw no confirmations
+	Confirmed       int     `json:"confirmed"`
+	Missed          int     `json:"missed"`
+	AvgAckLatencyMs float64 `json:"avg_ack_latency_ms,omitempty"`
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+}
+
+// placementPacer chooses the delay between placements and hands confirmations to the
+// placements waiting for them. Confirmations arrive from the intercept pipeline, so it
+// has its own lock.
+type placementPacer struct {
+	mu        sync.Mutex
+	adaptive  bool
+	fellBack  bool
+	delay     time.Duration
+	pending   map[protocol.BlockPos]time.Time
+	waiters   map[protocol.BlockPos]chan uint32
+	sent      int
+	confirmed int
+	missed    int
+	latency   time.Duration // total over confirmed placements
+	started   time.Time
+}
+
+// newPlacementPacer returns a pacer that adapts its delay, or one that always waits
+// delay when adaptive is false.
+func newPlacementPacer(adaptive bool, delay time.Duration) *placementPacer {
+	return &placementPacer{
+		adaptive: adaptive,
+		delay:    delay,
+		pending:  make(map[protocol.BlockPos]time.Time),
+		waiters:  make(map[protocol.BlockPos]chan uint32),
+		started:  time.Now(),
+	}
+}
+
+// await records a placement about to be sent for pos at now. It returns a channel
+// receiving the runtime ID the server's UpdateBlock sets at pos, or nil once pacing
+// has fallen back because the server sends no confirmations to wait for.
+func (p *placementPacer) await(pos protocol.BlockPos, now time.Time) <-chan uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent++
+	p.pending[pos] = now
+	if p.fellBack {
+		return nil
+	}
+	ch := make(chan uint32, 1)
+	p.waiters[pos] = ch
+	return ch
+}
+
+// stopWaiting drops the waiter for pos after its placement failed or timed out.
+func (p *placementPacer) stopWaiting(pos protocol.BlockPos) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.waiters, pos)
+}
+
+// confirmedAny reports whether the server has confirmed any placement of the batch.
+func (p *placementPacer) confirmedAny() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.confirmed > 0
+}
+
+// confirm records an UpdateBlock setting runtimeID at pos and passes it to the
+// placement waiting for it. It reports whether a placement was pending there.
+func (p *placementPacer) confirm(pos protocol.BlockPos, runtimeID uint32, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sentAt, ok := p.pending[pos]
+	if !ok {
+		return false
+	}
+	delete(p.pending, pos)
+	if ch, ok := p.waiters[pos]; ok {
+		ch <- runtimeID
+		delete(p.waiters, pos)
+	}
+	p.confirmed++
+	p.latency += now.Sub(sentAt)
+	if p.adaptive {
+		p.delay = max(minPlacementDelay, p.delay*9/10)
+	}
+	return true
+}
+
+// next returns the delay to wait before the next placement, first expiring
+// placements that have gone unconfirmed for too long.
+func (p *placementPacer) next(now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for pos, sentAt := range p.pending {
+		if now.Sub(sentAt) < placementAckTimeout {
+			continue
+		}
+		delete(p.pending, pos)
+		p.missed++
+		if p.adaptive {
+			p.delay = min(maxPlacementDelay, p.delay*2)
+		}
+	}
+	if p.adaptive && p.confirmed == 0 && p.sent >= placementProbeCount {
+		// No confirmations to steer by: UpdateBlock is not arriving.
+		p.adaptive, p.fellBack = false, true
+		p.delay = DefaultPlacementDelay
+	}
+	return p.delay
+}
+
+// report summarises the pacing after placed blocks were placed.
+func (p *placementPacer) report(placed int) PacingReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := PacingReport{
+		Mode:      "fixed",
+		DelayMs:   p.delay.Milliseconds(),
+		FellBack:  p.fellBack,
+		Confirmed: p.confirmed,
+		Missed:    p.missed,
+	}
+	if p.adaptive || p.fellBack {
+		r.Mode = "adaptive"
+	}
+	if p.confirmed > 0 {
+		r.AvgAckLatencyMs = float64(p.latency.Milliseconds()) / float64(p.confirmed)
+	}
+	if elapsed := time.Since(p.started).Seconds(); elapsed > 0 {
+		r.BlocksPerSecond = float64(placed) / elapsed
+	}
+	return r
+}
+
+// SetPlacementPacer sets the pacer UpdateBlock confirmations are reported to, or nil.
+func (gs *GameState) SetPlacementPacer(p *placementPacer) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.placementPacer = p
+}
+
+// ConfirmPlacement reports an UpdateBlock setting runtimeID at pos to the active
+// placement pacer.
+func (gs *GameState) ConfirmPlacement(pos protocol.BlockPos, runtimeID uint32) {
+	gs.mu.RLock()
+	p := gs.placementPacer
+	gs.mu.RUnlock()
+	if p != nil {
+		p.confirm(pos, runtimeID, time.Now())
+	}
+}
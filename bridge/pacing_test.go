@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestPlacementPacer_Adaptive(t *testing.T) {
+	p := newPlacementPacer(true, DefaultPlacementDelay)
+	now := time.Now()
+	for i := int32(0); i < 30; i++ {
+		pos := protocol.BlockPos{i, 64, 0}
+		p.await(pos, now)
+		if !p.confirm(pos, 1, now.Add(20*time.Millisecond)) {
+			t.Fatalf("expected placement %d to be confirmed", i)
+		}
+		p.next(now)
+	}
+	if d := p.next(now); d != minPlacementDelay {
+		t.Errorf("expected delay to converge to %s, got %s", minPlacementDelay, d)
+	}
+
+	// A placement that goes unconfirmed doubles the delay
+	p.await(protocol.BlockPos{99, 64, 0}, now)
+	if d := p.next(now.Add(placementAckTimeout)); d != 2*minPlacementDelay {
+		t.Errorf("expected delay %s after a miss, got %s", 2*minPlacementDelay, d)
+	}
+
+	r := p.report(31)
+	if r.Mode != "adaptive" || r.Confirmed != 30 || r.Missed != 1 || r.AvgAckLatencyMs != 20 {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
+
+func TestPlacementPacer_Bounds(t *testing.T) {
+	p := newPlacementPacer(true, maxPlacementDelay)
+	p.confirmed = 1 // stay adaptive
+	now := time.Now()
+	p.await(protocol.BlockPos{0, 64, 0}, now)
+	if d := p.next(now.Add(placementAckTimeout)); d != maxPlacementDelay {
+		t.Errorf("expected delay capped at %s, got %s", maxPlacementDelay, d)
+	}
+}
+
+func TestPlacementPacer_FallBack(t *testing.T) {
+	p := newPlacementPacer(true, 40*time.Millisecond)
+	now := time.Now()
+	for i := int32(0); i < placementProbeCount; i++ {
+		p.await(protocol.BlockPos{i, 64, 0}, now)
+	}
+	if d := p.next(now); d != DefaultPlacementDelay {
+		t.Errorf("expected fallback delay %s, got %s", DefaultPlacementDelay, d)
+	}
+	if r := p.report(placementProbeCount); !r.FellBack || r.Mode != "adaptive" {
+		t.Errorf("expected fell_back report, got %+v", r)
+	}
+}
+
+func TestPlacementPacer_Fixed(t *testing.T) {
+	gs := NewGameState()
+	p := newPlacementPacer(false, 250*time.Millisecond)
+	gs.SetPlacementPacer(p)
+	now := time.Now()
+	pos := protocol.BlockPos{1, 64, 1}
+	p.await(pos, now)
+	gs.ConfirmPlacement(pos, 1)
+	if d := p.next(now); d != 250*time.Millisecond {
+		t.Errorf("expected fixed delay, got %s", d)
+	}
+	if r := p.report(1); r.Mode != "fixed" || r.Confirmed != 1 {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
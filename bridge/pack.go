@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// The behavior pack's chunk receiver answers the chat message packProbe with packReply
+// (see behavior_pack/scripts/chunk-receiver.js), which ping_pack uses as a liveness probe.
+const (
+	packProbe = "hello"
+	packReply = "world"
+)
+
+// Defaults for ping_pack.
+const (
+	defaultPackPingTimeout = 5 * time.Second
+	packPingPollInterval   = 50 * time.Millisecond
+)
+
+// PackPingResult reports whether the behavior pack answered a probe.
+type PackPingResult struct {
+	Responsive bool   `json:"responsive"`
+	LatencyMs  int64  `json:"latency_ms,omitempty"`
+	Status     string `json:"status"` // "ok" or "no_response"
+}
+
+// pingPack sends the pack probe and waits up to timeout for the pack's reply in chat.
+func pingPack(ctx context.Context, state *GameState, timeout time.Duration) (PackPingResult, error) {
+	seq := state.ChatSeq()
+	start := time.Now()
+	if err := sendChat(state, packProbe); err != nil {
+		return PackPingResult{}, err
+	}
+	return waitForPackReply(ctx, state, seq, start, timeout)
+}
+
+// waitForPackReply polls chat received after seq for the pack's reply to a probe sent
+// at start.
+func waitForPackReply(ctx context.Context, state *GameState, seq uint64, start time.Time, timeout time.Duration) (PackPingResult, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(packPingPollInterval)
+	defer ticker.Stop()
+	for {
+		for _, msg := range state.ChatSince(seq) {
+			if msg.Type == "incoming" && msg.Message == packReply {
+				return PackPingResult{
+					Responsive: true,
+					LatencyMs:  msg.Time.Sub(start).Milliseconds(),
+					Status:     "ok",
+				}, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return PackPingResult{}, ctx.Err()
+		case <-deadline.C:
+			return PackPingResult{Status: "no_response"}, nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPingPack_NoConnection(t *testing.T) {
+	gs := NewGameState()
+	if _, err := pingPack(context.Background(), gs, 10*time.Millisecond); err == nil {
+		t.Error("expected error without a server connection")
+	}
+}
+
+func TestWaitForPackReply(t *testing.T) {
+	gs := NewGameState()
+	seq := gs.ChatSeq()
+	start := time.Now()
+	gs.AppendChat(ChatMessage{Time: start.Add(30 * time.Millisecond), Message: packReply, Type: "outgoing"})
+	gs.AppendChat(ChatMessage{Time: start.Add(40 * time.Millisecond), Message: "§8[chunk-recv] chatSend", Type: "incoming"})
+	gs.AppendChat(ChatMessage{Time: start.Add(50 * time.Millisecond), Message: packReply, Type: "incoming"})
+
+	result, err := waitForPackReply(context.Background(), gs, seq, start, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Responsive || result.Status != "ok" || result.LatencyMs != 50 {
+		t.Errorf("expected responsive with 50ms latency, got %+v", result)
+	}
+}
+
+func TestWaitForPackReply_Timeout(t *testing.T) {
+	gs := NewGameState()
+	result, err := waitForPackReply(context.Background(), gs, gs.ChatSeq(), time.Now(), 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Responsive || result.Status != "no_response" {
+		t.Errorf("expected no_response, got %+v", result)
+	}
+}
@@ -5,8 +5,32 @@ import (
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+// packetNames holds the type name of every packet gophertunnel knows in either
+// direction, as returned by packetTypeName.
+var packetNames = func() map[string]bool {
+	names := make(map[string]bool)
+	for _, pool := range []packet.Pool{packet.NewClientPool(), packet.NewServerPool()} {
+		for _, newPacket := range pool {
+			names[packetTypeName(newPacket())] = true
+		}
+	}
+	return names
+}()
+
+// unknownPacketNames returns the names in types that are not packet type names.
+func unknownPacketNames(types []string) []string {
+	var unknown []string
+	for _, t := range types {
+		if !packetNames[t] {
+			unknown = append(unknown, t)
+		}
+	}
+	return unknown
+}
+
 // playerActionName returns a readable name for building-relevant player actions.
 func playerActionName(action int32) string {
 	switch action {
@@ -104,3 +104,18 @@ func TestFormatVec3(t *testing.T) {
 		}
 	}
 }
+
+func TestUnknownPacketNames(t *testing.T) {
+	for _, name := range []string{"UpdateBlock", "Text", "SetTime", "PlayerAuthInput", "ItemStackResponse"} {
+		if !packetNames[name] {
+			t.Errorf("expected %s to be a known packet type", name)
+		}
+	}
+	unknown := unknownPacketNames([]string{"UpdateBlock", "UpdateBlocks", "Text", "text"})
+	if len(unknown) != 2 || unknown[0] != "UpdateBlocks" || unknown[1] != "text" {
+		t.Errorf("expected UpdateBlocks and text to be rejected, got %v", unknown)
+	}
+	if unknown := unknownPacketNames(nil); len(unknown) != 0 {
+		t.Errorf("expected no unknown names for an empty filter, got %v", unknown)
+	}
+}
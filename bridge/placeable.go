@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// PlaceableCheck reports whether place_blocks can place a block, and why not.
+type PlaceableCheck struct {
+	Block             string   `json:"block"`
+	Placeable         bool     `json:"placeable"`
+	InItemRegistry    bool     `json:"in_item_registry"`
+	GameMode          string   `json:"game_mode"`
+	InCreativeContent bool     `json:"in_creative_content"`
+	InventorySlot     *int     `json:"inventory_slot,omitempty"`
+	Reasons           []string `json:"reasons,omitempty"`
+}
+
+// checkBlockPlaceable runs the checks place_blocks depends on: the block name must
+// resolve to an item network ID, and the player must either be in creative mode with
+// the block in the creative inventory or hold the block in their inventory.
+func checkBlockPlaceable(state *GameState, block string) PlaceableCheck {
+	_, _, gameMode, _, _ := state.WorldInfo()
+	check := PlaceableCheck{Block: block, GameMode: gameModeName(gameMode)}
+
+	_, check.InItemRegistry = state.ResolveItemNetworkID(block)
+	_, check.InCreativeContent = state.CreativeItemID(block)
+	if slot, ok := findItemSlot(state, block); ok {
+		check.InventorySlot = &slot
+	}
+	creative := gameMode == packet.GameTypeCreative
+
+	if !check.InItemRegistry {
+		check.Reasons = append(check.Reasons, fmt.Sprintf("%q is not in the item registry; check the name (e.g. minecraft:stone)", block))
+	}
+	switch {
+	case check.InventorySlot != nil:
+	case creative && check.InCreativeContent:
+	case creative && state.CreativeItemCount() == 0:
+		// Creative content not received; assume the creative inventory has it.
+	case creative:
+		check.Reasons = append(check.Reasons, "not available in the creative inventory")
+	default:
+		check.Reasons = append(check.Reasons, fmt.Sprintf("not in the inventory and the game mode is %s, not creative", check.GameMode))
+	}
+	check.Placeable = len(check.Reasons) == 0
+	return check
+}
+
+// SetGameMode updates the player's game mode.
+func (gs *GameState) SetGameMode(mode int32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.gameMode = mode
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestCheckBlockPlaceable(t *testing.T) {
+	gs := NewGameState()
+	gs.itemRegistry[1] = "minecraft:stone"
+	gs.itemRegistry[2] = "minecraft:dirt"
+	gs.itemRegistry[3] = "minecraft:bedrock"
+	gs.SetCreativeItems([]protocol.CreativeItem{
+		{CreativeItemNetworkID: 10, Item: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 1}}},
+	})
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 4, protocol.ItemInstance{
+		Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 2}, Count: 16},
+	})
+
+	tests := []struct {
+		name     string
+		mode     int32
+		block    string
+		want     bool
+		wantSlot bool
+	}{
+		{"unknown name", packet.GameTypeCreative, "minecraft:stonee", false, false},
+		{"creative content", packet.GameTypeCreative, "minecraft:stone", true, false},
+		{"not in creative content", packet.GameTypeCreative, "minecraft:bedrock", false, false},
+		{"survival without item", packet.GameTypeSurvival, "minecraft:stone", false, false},
+		{"survival with item", packet.GameTypeSurvival, "minecraft:dirt", true, true},
+	}
+	for _, tt := range tests {
+		interceptServerPacket(&packet.SetPlayerGameType{GameType: tt.mode}, gs)
+		check := checkBlockPlaceable(gs, tt.block)
+		if check.Placeable != tt.want {
+			t.Errorf("%s: expected placeable %v, got %+v", tt.name, tt.want, check)
+		}
+		if (check.InventorySlot != nil) != tt.wantSlot {
+			t.Errorf("%s: expected inventory slot %v, got %v", tt.name, tt.wantSlot, check.InventorySlot)
+		}
+		if !check.Placeable && len(check.Reasons) == 0 {
+			t.Errorf("%s: expected a reason", tt.name)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Placement retry defaults for place_blocks.
+const (
+	DefaultPlaceAttempts = 3
+	placeRetryBackoff    = 100 * time.Millisecond // doubled after each failed attempt
+)
+
+// errUnknownBlockName marks placement failures that retrying cannot fix.
+var errUnknownBlockName = errors.New("unknown block name")
+
+// Placement failures found by waiting for the server's confirmation; both are retried.
+var (
+	errPlacementRejected    = errors.New("server rejected the placement")
+	errPlacementUnconfirmed = errors.New("server did not confirm the placement")
+)
+
+// BlockCoord is a block position in a place_blocks result.
+type BlockCoord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
+}
+
+// FailedPlacement is a block that could not be placed.
+type FailedPlacement struct {
+	BlockCoord
+	BlockName string `json:"block_name"`
+	Error     string `json:"error"`
+}
+
+// PlaceBlocksResult is the outcome of a place_blocks batch.
+type PlaceBlocksResult struct {
+	Placed      []BlockCoord      `json:"placed"`
+	Failed      []FailedPlacement `json:"failed"`
+	Teleports   int               `json:"teleports"`
+	Skipped     int               `json:"skipped"`
+	Interrupted bool              `json:"interrupted,omitempty"`
+	Pacing      PacingReport      `json:"pacing"`
+}
+
+// blockPlacement is one block of a batch to place. AgainstFace optionally names the
+// side of the target holding the neighbor to place against.
+type blockPlacement struct {
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Z           int    `json:"z"`
+	BlockName   string `json:"block_name"`
+	AgainstFace string `json:"against_face"`
+}
+
+// placeOptions controls how placeBlocks places a batch.
+type placeOptions struct {
+	delay           time.Duration
+	fixedDelay      bool // otherwise delay is where adaptive pacing starts
+	reach           float64
+	policy          string
+	moveDelay       time.Duration
+	attempts        int
+	continueOnError bool
+	mode            string
+	unknown         string
+}
+
+// placeOptionsFromRequest reads and validates the placement arguments shared by the
+// tools that place blocks. Arguments a tool does not declare take their defaults.
+func placeOptionsFromRequest(req mcp.CallToolRequest, continueOnError bool) (placeOptions, error) {
+	_, fixedDelay := req.GetArguments()["delay_ms"]
+	opts := placeOptions{
+		delay:           time.Duration(req.GetInt("delay_ms", int(DefaultPlacementDelay/time.Millisecond))) * time.Millisecond,
+		fixedDelay:      fixedDelay,
+		reach:           req.GetFloat("reach", DefaultReachDistance),
+		policy:          req.GetString("on_out_of_reach", outOfReachTeleport),
+		moveDelay:       time.Duration(req.GetInt("move_delay_ms", int(DefaultMoveDelay/time.Millisecond))) * time.Millisecond,
+		attempts:        req.GetInt("attempts", DefaultPlaceAttempts),
+		continueOnError: req.GetBool("continue_on_error", continueOnError),
+		mode:            req.GetString("mode", placeModeReplace),
+		unknown:         req.GetString("unknown_blocks", unknownBlocksPlace),
+	}
+	switch {
+	case opts.delay < 0:
+		return placeOptions{}, fmt.Errorf("delay_ms must not be negative")
+	case opts.mode != placeModeReplace && opts.mode != placeModeKeep:
+		return placeOptions{}, fmt.Errorf("invalid mode %q (want replace or keep)", opts.mode)
+	case opts.unknown != unknownBlocksPlace && opts.unknown != unknownBlocksSkip:
+		return placeOptions{}, fmt.Errorf("invalid unknown_blocks %q (want place or skip)", opts.unknown)
+	case opts.reach <= 0:
+		return placeOptions{}, fmt.Errorf("reach must be positive")
+	case opts.attempts < 1:
+		return placeOptions{}, fmt.Errorf("attempts must be at least 1")
+	}
+	return opts, nil
+}
+
+// placeBlocks places a batch of blocks in order as a tracked operation named op,
+// teleporting into reach and pacing placements as opts says. Cancelling the
+// operation stops the batch and marks the result interrupted. Unless
+// opts.continueOnError is set, the first block that cannot be placed aborts the
+// batch with an error.
+func placeBlocks(ctx context.Context, state *GameState, conn *minecraft.Conn, op string, blocks []blockPlacement, opts placeOptions) (PlaceBlocksResult, error) {
+	strategy := placementStrategy(state)
+	slog.Info(op+": placement strategy", "strategy", strategy, "blocks", len(blocks))
+
+	opID, ctx := state.StartOperation(ctx, op, len(blocks))
+	defer state.EndOperation(opID)
+
+	pacer := newPlacementPacer(!opts.fixedDelay, opts.delay)
+	state.SetPlacementPacer(pacer)
+	defer state.SetPlacementPacer(nil)
+
+	result := PlaceBlocksResult{Placed: []BlockCoord{}, Failed: []FailedPlacement{}}
+	for i, b := range blocks {
+		state.UpdateOperation(opID, i, len(result.Failed))
+		select {
+		case <-ctx.Done():
+			result.Interrupted = true
+			result.Pacing = pacer.report(len(result.Placed))
+			return result, nil
+		default:
+		}
+
+		coord := BlockCoord{X: b.X, Y: b.Y, Z: b.Z}
+		pos := protocol.BlockPos{int32(b.X), int32(b.Y), int32(b.Z)}
+		if skipPlacement(state, pos, opts.mode, opts.unknown) {
+			result.Skipped++
+			continue
+		}
+		moved, err := ensureInReach(ctx, state, pos[0], pos[1], pos[2], opts.reach, opts.policy, opts.moveDelay)
+		if moved {
+			result.Teleports++
+		}
+		if err == nil {
+			err = retryPlacement(ctx, opts.attempts, func() error {
+				return placeConfirmed(ctx, state, pacer, pos, func() error {
+					return placeBlock(ctx, conn, state, pos[0], pos[1], pos[2], b.BlockName, b.AgainstFace, strategy)
+				})
+			})
+		}
+		if err != nil {
+			slog.Warn(op+": placement failed", "index", i, "block", b.BlockName, "error", err)
+			if !opts.continueOnError {
+				return PlaceBlocksResult{}, fmt.Errorf("failed at block %d (%s at %d,%d,%d) after placing %d: %w", i, b.BlockName, b.X, b.Y, b.Z, len(result.Placed), err)
+			}
+			result.Failed = append(result.Failed, FailedPlacement{BlockCoord: coord, BlockName: b.BlockName, Error: err.Error()})
+		} else {
+			result.Placed = append(result.Placed, coord)
+		}
+
+		if d := pacer.next(time.Now()); d > 0 && i < len(blocks)-1 {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				// Reported as interrupted at the top of the loop.
+			}
+		}
+	}
+
+	result.Pacing = pacer.report(len(result.Placed))
+	return result, nil
+}
+
+// Placement modes for place_blocks: replace places over whatever is there, keep only
+// places into air.
+const (
+	placeModeReplace = "replace"
+	placeModeKeep    = "keep"
+)
+
+// What keep mode does with positions whose block is not known from the block cache:
+// place (aggressive, as in replace mode) or skip (conservative).
+const (
+	unknownBlocksPlace = "place"
+	unknownBlocksSkip  = "skip"
+)
+
+// skipPlacement reports whether keep mode leaves pos alone because it holds a block
+// other than air. Positions missing from the cache, or cached with a runtime ID the
+// block registry has not learned, follow the unknown policy.
+func skipPlacement(state *GameState, pos protocol.BlockPos, mode, unknown string) bool {
+	if mode != placeModeKeep {
+		return false
+	}
+	name, ok := state.BlockNameAt(pos)
+	if !ok || strings.HasPrefix(name, "rid:") {
+		return unknown == unknownBlocksSkip
+	}
+	return !isAirBlock(name)
+}
+
+// Placement strategies, chosen from the StartGame ServerAuthoritativeInventory flag.
+const (
+	// placementTransaction claims the block is held in hotbar slot 0 without the server
+	// knowing the item. Accepted by worlds with client-authoritative inventory.
+	placementTransaction = "transaction"
+	// placementItemStack first puts the block in a hotbar slot with item stack requests
+	// (taking it from the creative inventory if needed) and selects that slot, so the
+	// held item matches the server's view of the inventory.
+	placementItemStack = "item_stack"
+)
+
+// placementStrategy returns the placement strategy for the Realm's inventory mode.
+func placementStrategy(state *GameState) string {
+	if state.SessionInfo().ServerAuthoritativeInventory {
+		return placementItemStack
+	}
+	return placementTransaction
+}
+
+// preparePlacement returns the item to report as held when placing blockName and the
+// hotbar slot holding it, according to strategy.
+func preparePlacement(ctx context.Context, state *GameState, strategy, blockName string) (protocol.ItemInstance, int, error) {
+	networkID, ok := state.ResolveItemNetworkID(blockName)
+	if !ok {
+		return protocol.ItemInstance{}, 0, fmt.Errorf("%w %q (not in item registry)", errUnknownBlockName, blockName)
+	}
+	if strategy != placementItemStack {
+		return protocol.ItemInstance{
+			Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: networkID}, Count: 1},
+		}, 0, nil
+	}
+
+	slot, ok := hotbarSlotHolding(state, blockName)
+	if !ok {
+		free, hasFree := state.FirstEmptySlot(protocol.WindowIDInventory, 0, hotbarSize-1)
+		if !hasFree {
+			return protocol.ItemInstance{}, 0, fmt.Errorf("no %s in the hotbar and no empty hotbar slot to take it into", blockName)
+		}
+		if _, err := craftCreative(ctx, state, blockName, maxCreativeCraftCount, free); err != nil {
+			return protocol.ItemInstance{}, 0, fmt.Errorf("taking %s into the hotbar: %w", blockName, err)
+		}
+		slot = free
+	}
+	if state.HeldItem().Slot != slot {
+		if _, err := selectHotbarSlot(state, slot); err != nil {
+			return protocol.ItemInstance{}, 0, err
+		}
+	}
+	item, _ := state.InventoryItem(protocol.WindowIDInventory, slot)
+	return item, slot, nil
+}
+
+// hotbarSlotHolding returns the first hotbar slot holding the named item.
+func hotbarSlotHolding(state *GameState, item string) (int, bool) {
+	for _, s := range state.WindowSlots(protocol.WindowIDInventory, 0, hotbarSize-1) {
+		if s.Item == item {
+			return s.Slot, true
+		}
+	}
+	return 0, false
+}
+
+// retryPlacement calls place up to attempts times, backing off between attempts.
+// Errors wrapping errUnknownBlockName are returned without retrying.
+func retryPlacement(ctx context.Context, attempts int, place func() error) error {
+	backoff := placeRetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = place(); err == nil || errors.Is(err, errUnknownBlockName) || attempt >= attempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// placeConfirmed sends a placement at pos with place and waits for the server's
+// UpdateBlock there. An update leaving air means the server rejected it. A placement
+// still unconfirmed after placementAckTimeout failed if the server has confirmed
+// others in the batch; before any confirmation it is assumed placed, since some
+// servers don't echo placements, and once pacing has fallen back it is not waited on.
+func placeConfirmed(ctx context.Context, state *GameState, pacer *placementPacer, pos protocol.BlockPos, place func() error) error {
+	ack := pacer.await(pos, time.Now())
+	if err := place(); err != nil {
+		pacer.stopWaiting(pos)
+		return err
+	}
+	if ack == nil {
+		return nil
+	}
+	timer := time.NewTimer(placementAckTimeout)
+	defer timer.Stop()
+	select {
+	case runtimeID := <-ack:
+		if name := state.ResolveBlockName(runtimeID); isAirBlock(name) {
+			return fmt.Errorf("%w: the server left %s at %d %d %d", errPlacementRejected, name, pos[0], pos[1], pos[2])
+		}
+		return nil
+	case <-timer.C:
+		pacer.stopWaiting(pos)
+		if pacer.confirmedAny() {
+			return errPlacementUnconfirmed
+		}
+		return nil
+	case <-ctx.Done():
+		pacer.stopWaiting(pos)
+		return ctx.Err()
+	}
+}
+
+// placeAgainstAuto picks the block to place against from the block cache.
+const placeAgainstAuto = "auto"
+
+// placementSupport is the neighbor a placement clicks on. Dir names the side of the
+// target the neighbor is on, Offset leads from the target to it, and Face is the
+// neighbor's face pointing back at the target.
+type placementSupport struct {
+	Dir    string
+	Offset protocol.BlockPos
+	Face   int32
+}
+
+// placementSupports lists the neighbors in the order auto-selection tries them. "down"
+// comes first: clicking the top of the block below is what the client does for most
+// blocks, and the fallback when nothing is known about the neighbors.
+var placementSupports = []placementSupport{
+	{"down", protocol.BlockPos{0, -1, 0}, 1},
+	{"north", protocol.BlockPos{0, 0, -1}, 3},
+	{"south", protocol.BlockPos{0, 0, 1}, 2},
+	{"west", protocol.BlockPos{-1, 0, 0}, 5},
+	{"east", protocol.BlockPos{1, 0, 0}, 4},
+	{"up", protocol.BlockPos{0, 1, 0}, 0},
+}
+
+// placementSupportFor returns the support in direction dir.
+func placementSupportFor(dir string) (placementSupport, bool) {
+	for _, s := range placementSupports {
+		if s.Dir == dir {
+			return s, true
+		}
+	}
+	return placementSupport{}, false
+}
+
+// validatePlaceAgainst checks an against_face value: empty, "auto" or a direction.
+func validatePlaceAgainst(dir string) error {
+	if dir == "" || dir == placeAgainstAuto {
+		return nil
+	}
+	if _, ok := placementSupportFor(dir); !ok {
+		return fmt.Errorf("against_face must be auto, down, up, north, south, west or east, got %q", dir)
+	}
+	return nil
+}
+
+// isSupportBlock reports whether a block can be clicked to place another against it.
+func isSupportBlock(name string) bool {
+	return !isAirBlock(name) && !isLavaBlock(name) && !strings.Contains(name, "water")
+}
+
+// choosePlacementSupport returns the neighbor to click to place a block at target.
+// An explicit direction is used as given; otherwise the first neighbor the block cache
+// knows to be solid is chosen, falling back to the block below.
+func choosePlacementSupport(state *GameState, target protocol.BlockPos, dir string) placementSupport {
+	if s, ok := placementSupportFor(dir); ok {
+		return s
+	}
+	for _, s := range placementSupports {
+		if name, ok := state.BlockNameAt(addBlockPos(target, s.Offset)); ok && isSupportBlock(name) {
+			return s
+		}
+	}
+	return placementSupports[0]
+}
+
+// clickPosition returns the point in the middle of a block face, relative to the block.
+func clickPosition(face int32) mgl32.Vec3 {
+	click := mgl32.Vec3{0.5, 0.5, 0.5}
+	switch face {
+	case 0:
+		click[1] = 0
+	case 1:
+		click[1] = 1
+	case 2:
+		click[2] = 0
+	case 3:
+		click[2] = 1
+	case 4:
+		click[0] = 0
+	case 5:
+		click[0] = 1
+	}
+	return click
+}
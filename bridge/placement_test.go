@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestRetryPlacement(t *testing.T) {
+	transient := errors.New("rejected")
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"first try", 0, transient, 3, 1, false},
+		{"succeeds on retry", 2, transient, 3, 3, false},
+		{"exhausts attempts", 5, transient, 3, 3, true},
+		{"unknown block not retried", 5, fmt.Errorf("%w %q", errUnknownBlockName, "minecraft:nope"), 3, 1, true},
+	}
+	for _, tt := range tests {
+		calls := 0
+		err := retryPlacement(context.Background(), tt.attempts, func() error {
+			calls++
+			if calls <= tt.failures {
+				return tt.err
+			}
+			return nil
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("%s: expected %d calls, got %d", tt.name, tt.wantCalls, calls)
+		}
+	}
+}
+
+func TestPlaceConfirmed_Rejected(t *testing.T) {
+	gs := newChunkTestState(t)
+	pacer := newPlacementPacer(true, DefaultPlacementDelay)
+	gs.SetPlacementPacer(pacer)
+	pos := protocol.BlockPos{8, 66, 8}
+	air := blockStateHash(blockState{Name: "minecraft:air"})
+	stone := blockStateHash(blockState{Name: "minecraft:stone"})
+
+	// The server reverts the first placement to air and accepts the second.
+	var answers []uint32
+	place := func() error {
+		interceptServerPacket(&packet.UpdateBlock{Position: pos, NewBlockRuntimeID: answers[0]}, gs)
+		answers = answers[1:]
+		return nil
+	}
+	answers = []uint32{air, stone}
+	err := retryPlacement(context.Background(), 3, func() error {
+		return placeConfirmed(context.Background(), gs, pacer, pos, place)
+	})
+	if err != nil || len(answers) != 0 {
+		t.Errorf("expected the rejected placement to be retried and confirmed, got %v with %d answers left", err, len(answers))
+	}
+
+	answers = []uint32{air, air}
+	err = retryPlacement(context.Background(), 2, func() error {
+		return placeConfirmed(context.Background(), gs, pacer, pos, place)
+	})
+	if !errors.Is(err, errPlacementRejected) {
+		t.Errorf("expected the placement to fail as rejected, got %v", err)
+	}
+}
+
+func TestPlacementStrategy(t *testing.T) {
+	gs := NewGameState()
+	if got := placementStrategy(gs); got != placementTransaction {
+		t.Errorf("expected %s, got %s", placementTransaction, got)
+	}
+	gs.InitFromGameData(minecraft.GameData{ServerAuthoritativeInventory: true})
+	if got := placementStrategy(gs); got != placementItemStack {
+		t.Errorf("expected %s, got %s", placementItemStack, got)
+	}
+}
+
+func TestPreparePlacement(t *testing.T) {
+	gs := NewGameState()
+	gs.itemRegistry[1] = "minecraft:stone"
+	gs.itemRegistry[2] = "minecraft:dirt"
+
+	item, slot, err := preparePlacement(context.Background(), gs, placementTransaction, "minecraft:stone")
+	if err != nil || slot != 0 || item.Stack.NetworkID != 1 {
+		t.Errorf("transaction: expected stone in slot 0, got %+v slot %d (%v)", item, slot, err)
+	}
+
+	stack := protocol.ItemInstance{StackNetworkID: 42, Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 1}, Count: 64}}
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 2, stack)
+	gs.SetHeldSlot(2)
+	item, slot, err = preparePlacement(context.Background(), gs, placementItemStack, "minecraft:stone")
+	if err != nil || slot != 2 || item.StackNetworkID != 42 {
+		t.Errorf("item_stack: expected stack 42 in slot 2, got %+v slot %d (%v)", item, slot, err)
+	}
+
+	for i := 0; i < hotbarSize; i++ {
+		gs.UpdateInventorySlot(protocol.WindowIDInventory, i, stack)
+	}
+	if _, _, err := preparePlacement(context.Background(), gs, placementItemStack, "minecraft:dirt"); err == nil {
+		t.Error("expected error with a full hotbar")
+	}
+}
+
+func TestChoosePlacementSupport(t *testing.T) {
+	gs := NewGameState()
+	gs.LearnBlock(1, "minecraft:stone")
+	gs.LearnBlock(2, "minecraft:air")
+	gs.LearnBlock(3, "minecraft:water")
+	target := protocol.BlockPos{0, 64, 0}
+
+	// Nothing known about the neighbors: click the block below.
+	if s := choosePlacementSupport(gs, target, ""); s.Dir != "down" || s.Face != 1 {
+		t.Errorf("expected down/up face fallback, got %+v", s)
+	}
+
+	gs.Blocks().Set(protocol.BlockPos{0, 63, 0}, 2)  // air below
+	gs.Blocks().Set(protocol.BlockPos{0, 64, -1}, 3) // water to the north
+	gs.Blocks().Set(protocol.BlockPos{1, 64, 0}, 1)  // stone to the east
+	s := choosePlacementSupport(gs, target, placeAgainstAuto)
+	if s.Dir != "east" || s.Face != 4 {
+		t.Errorf("expected east neighbor clicked on its west face, got %+v", s)
+	}
+	if got := addBlockPos(target, s.Offset); got != (protocol.BlockPos{1, 64, 0}) {
+		t.Errorf("expected click on (1,64,0), got %v", got)
+	}
+	if c := clickPosition(s.Face); c != (mgl32.Vec3{0, 0.5, 0.5}) {
+		t.Errorf("expected click in the middle of the west face, got %v", c)
+	}
+
+	// An explicit direction is used even when the cache disagrees.
+	if s := choosePlacementSupport(gs, target, "north"); s.Dir != "north" || s.Face != 3 {
+		t.Errorf("expected north neighbor clicked on its south face, got %+v", s)
+	}
+}
+
+func TestValidatePlaceAgainst(t *testing.T) {
+	for _, dir := range []string{"", "auto", "down", "up", "north", "south", "west", "east"} {
+		if err := validatePlaceAgainst(dir); err != nil {
+			t.Errorf("%q: expected valid, got %v", dir, err)
+		}
+	}
+	if err := validatePlaceAgainst("sideways"); err == nil {
+		t.Error("expected error for an unknown direction")
+	}
+}
+
+func TestSkipPlacement(t *testing.T) {
+	gs := NewGameState()
+	gs.LearnBlock(1, "minecraft:stone")
+	gs.LearnBlock(2, "minecraft:air")
+	stone, air := protocol.BlockPos{0, 64, 0}, protocol.BlockPos{1, 64, 0}
+	unseen, unlearned := protocol.BlockPos{2, 64, 0}, protocol.BlockPos{3, 64, 0}
+	gs.Blocks().Set(stone, 1)
+	gs.Blocks().Set(air, 2)
+	gs.Blocks().Set(unlearned, 99)
+
+	tests := []struct {
+		pos     protocol.BlockPos
+		mode    string
+		unknown string
+		want    bool
+	}{
+		{stone, placeModeReplace, unknownBlocksSkip, false},
+		{stone, placeModeKeep, unknownBlocksPlace, true},
+		{air, placeModeKeep, unknownBlocksSkip, false},
+		{unseen, placeModeKeep, unknownBlocksPlace, false},
+		{unseen, placeModeKeep, unknownBlocksSkip, true},
+		{unlearned, placeModeKeep, unknownBlocksPlace, false},
+		{unlearned, placeModeKeep, unknownBlocksSkip, true},
+	}
+	for _, tt := range tests {
+		if got := skipPlacement(gs, tt.pos, tt.mode, tt.unknown); got != tt.want {
+			t.Errorf("%v %s/%s: expected %v, got %v", tt.pos, tt.mode, tt.unknown, tt.want, got)
+		}
+	}
+}
+
+func TestPlaceOptionsFromRequest(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	opts, err := placeOptionsFromRequest(req, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.fixedDelay || opts.delay != DefaultPlacementDelay || !opts.continueOnError || opts.mode != placeModeReplace {
+		t.Errorf("unexpected defaults %+v", opts)
+	}
+
+	req.Params.Arguments = map[string]any{"delay_ms": 0.0, "continue_on_error": false}
+	if opts, _ := placeOptionsFromRequest(req, true); !opts.fixedDelay || opts.delay != 0 || opts.continueOnError {
+		t.Errorf("expected fixed zero delay without continuing, got %+v", opts)
+	}
+
+	for _, args := range []map[string]any{
+		{"delay_ms": -1.0},
+		{"mode": "merge"},
+		{"unknown_blocks": "guess"},
+		{"reach": 0.0},
+		{"attempts": 0.0},
+	} {
+		req.Params.Arguments = args
+		if _, err := placeOptionsFromRequest(req, false); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// PlayerIdentity is what the server has revealed about a player's client. PlayerList
+// entries carry the XUID and skin, AddPlayer the entity runtime ID, and PlayerSkin
+// later skin changes; they are linked by the player's UUID, so whichever arrives
+// first is kept until the others fill in the rest.
+type PlayerIdentity struct {
+	XUID        string `json:"xuid,omitempty"`
+	RuntimeID   uint64 `json:"runtime_id,omitempty"`
+	Platform    string `json:"platform,omitempty"`
+	SkinID      string `json:"skin_id,omitempty"`
+	PersonaSkin bool   `json:"persona_skin,omitempty"` // a character creator skin rather than a skin pack
+}
+
+// deviceOSNames names the build platforms of protocol.DeviceOS.
+var deviceOSNames = map[protocol.DeviceOS]string{
+	protocol.DeviceAndroid:   "android",
+	protocol.DeviceIOS:       "ios",
+	protocol.DeviceOSX:       "macos",
+	protocol.DeviceFireOS:    "fireos",
+	protocol.DeviceGearVR:    "gearvr",
+	protocol.DeviceHololens:  "hololens",
+	protocol.DeviceWin10:     "windows",
+	protocol.DeviceWin32:     "win32",
+	protocol.DeviceDedicated: "dedicated",
+	protocol.DeviceTVOS:      "tvos",
+	protocol.DeviceOrbis:     "playstation",
+	protocol.DeviceNX:        "switch",
+	protocol.DeviceXBOX:      "xbox",
+	protocol.DeviceWP:        "windows_phone",
+	protocol.DeviceLinux:     "linux",
+}
+
+// deviceOSName returns the name of a build platform, or "" if none was sent.
+func deviceOSName(platform int32) string {
+	if platform <= 0 {
+		return ""
+	}
+	if name, ok := deviceOSNames[protocol.DeviceOS(platform)]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", platform)
+}
+
+// SetPlayerListIdentity records the identity carried by a PlayerList entry.
+func (gs *GameState) SetPlayerListIdentity(entry protocol.PlayerListEntry) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	id := gs.playerIdentities[entry.UUID]
+	id.XUID = entry.XUID
+	if p := deviceOSName(entry.BuildPlatform); p != "" {
+		id.Platform = p
+	}
+	if entry.Skin.SkinID != "" {
+		id.SkinID, id.PersonaSkin = entry.Skin.SkinID, entry.Skin.PersonaSkin
+	}
+	gs.playerIdentities[entry.UUID] = id
+}
+
+// SetPlayerEntityIdentity links a player's UUID to the runtime ID of its entity.
+func (gs *GameState) SetPlayerEntityIdentity(playerID uuid.UUID, runtimeID uint64, buildPlatform int32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	id := gs.playerIdentities[playerID]
+	id.RuntimeID = runtimeID
+	if p := deviceOSName(buildPlatform); p != "" {
+		id.Platform = p
+	}
+	gs.playerIdentities[playerID] = id
+}
+
+// SetPlayerSkin records a skin change from a PlayerSkin packet. name is used when
+// the skin has no ID.
+func (gs *GameState) SetPlayerSkin(playerID uuid.UUID, skin protocol.Skin, name string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	id := gs.playerIdentities[playerID]
+	id.SkinID, id.PersonaSkin = skin.SkinID, skin.PersonaSkin
+	if id.SkinID == "" {
+		id.SkinID = name
+	}
+	gs.playerIdentities[playerID] = id
+}
+
+// playerIdentityByXUIDLocked returns the identity of the player with the given XUID.
+// Callers must hold gs.mu.
+func (gs *GameState) playerIdentityByXUIDLocked(xuid string) (PlayerIdentity, bool) {
+	for playerID, x := range gs.playerXUIDs {
+		if x == xuid {
+			id, ok := gs.playerIdentities[playerID]
+			return id, ok
+		}
+	}
+	return PlayerIdentity{}, false
+}
+
+// playerIdentityByRuntimeIDLocked returns the identity of the player whose entity
+// has the given runtime ID. Callers must hold gs.mu.
+func (gs *GameState) playerIdentityByRuntimeIDLocked(runtimeID uint64) (PlayerIdentity, bool) {
+	if runtimeID == 0 {
+		return PlayerIdentity{}, false
+	}
+	for _, id := range gs.playerIdentities {
+		if id.RuntimeID == runtimeID {
+			return id, true
+		}
+	}
+	return PlayerIdentity{}, false
+}
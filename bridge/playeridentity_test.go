@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestDeviceOSName(t *testing.T) {
+	tests := []struct {
+		platform int32
+		expected string
+	}{
+		{0, ""},
+		{-1, ""},
+		{int32(protocol.DeviceAndroid), "android"},
+		{int32(protocol.DeviceXBOX), "xbox"},
+		{99, "unknown(99)"},
+	}
+	for _, tt := range tests {
+		if got := deviceOSName(tt.platform); got != tt.expected {
+			t.Errorf("deviceOSName(%d): expected %q, got %q", tt.platform, tt.expected, got)
+		}
+	}
+}
+
+func TestPlayerIdentity_ListThenEntity(t *testing.T) {
+	gs := NewGameState()
+	id := uuid.New()
+	interceptServerPacket(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{{
+		UUID: id, XUID: "123", Username: "Steve", BuildPlatform: int32(protocol.DeviceNX),
+		Skin: protocol.Skin{SkinID: "skin-a", PersonaSkin: true},
+	}}}, gs)
+
+	players := gs.Players()
+	if len(players) != 1 || players[0].Platform != "switch" || players[0].SkinID != "skin-a" || !players[0].PersonaSkin {
+		t.Fatalf("unexpected players %+v", players)
+	}
+	if players[0].RuntimeID != 0 {
+		t.Errorf("expected no runtime ID before AddPlayer, got %d", players[0].RuntimeID)
+	}
+
+	interceptServerPacket(&packet.AddPlayer{UUID: id, Username: "Steve", EntityRuntimeID: 7, Position: mgl32.Vec3{1, 2, 3}}, gs)
+	if p := gs.Players()[0]; p.RuntimeID != 7 {
+		t.Errorf("expected runtime ID 7, got %d", p.RuntimeID)
+	}
+	entities := gs.Entities()
+	if len(entities) != 1 || entities[0].Player == nil {
+		t.Fatalf("expected a player entity, got %+v", entities)
+	}
+	if e := entities[0].Player; e.XUID != "123" || e.Platform != "switch" {
+		t.Errorf("unexpected entity identity %+v", e)
+	}
+
+	interceptServerPacket(&packet.PlayerSkin{UUID: id, Skin: protocol.Skin{SkinID: "skin-b"}}, gs)
+	if p := gs.Players()[0]; p.SkinID != "skin-b" || p.PersonaSkin {
+		t.Errorf("expected skin change to skin-b, got %+v", p)
+	}
+
+	interceptServerPacket(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: []protocol.PlayerListEntry{{UUID: id}}}, gs)
+	if e := gs.Entities(); e[0].Player != nil {
+		t.Errorf("expected identity to be dropped with the player, got %+v", e[0].Player)
+	}
+}
+
+func TestPlayerIdentity_EntityThenList(t *testing.T) {
+	gs := NewGameState()
+	id := uuid.New()
+	interceptServerPacket(&packet.AddPlayer{UUID: id, Username: "Alex", EntityRuntimeID: 9, BuildPlatform: int32(protocol.DeviceIOS)}, gs)
+	e := gs.Entities()[0]
+	if e.Player == nil || e.Player.Platform != "ios" || e.Player.XUID != "" {
+		t.Fatalf("expected entity identity without XUID, got %+v", e.Player)
+	}
+
+	interceptServerPacket(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{{
+		UUID: id, XUID: "456", Username: "Alex",
+	}}}, gs)
+	if e := gs.Entities()[0]; e.Player.XUID != "456" || e.Player.Platform != "ios" {
+		t.Errorf("expected linked identity, got %+v", e.Player)
+	}
+	if p := gs.Players()[0]; p.RuntimeID != 9 || p.Platform != "ios" {
+		t.Errorf("expected player linked to entity 9, got %+v", p)
+	}
+
+	// Entities without a player identity are left alone
+	gs.AddEntity(10, "minecraft:cow", mgl32.Vec3{})
+	for _, e := range gs.Entities() {
+		if e.RuntimeID == 10 && e.Player != nil {
+			t.Errorf("expected no identity for a cow, got %+v", e.Player)
+		}
+	}
+}
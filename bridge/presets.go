@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BuildPreset is a named set of placement arguments saved with save_build_preset.
+// Unset fields leave the tool's own default in place.
+type BuildPreset struct {
+	Name            string   `json:"name"`
+	Reach           *float64 `json:"reach,omitempty"`
+	DelayMs         *int     `json:"delay_ms,omitempty"`
+	MoveDelayMs     *int     `json:"move_delay_ms,omitempty"`
+	Attempts        *int     `json:"attempts,omitempty"`
+	ContinueOnError *bool    `json:"continue_on_error,omitempty"`
+	Mode            string   `json:"mode,omitempty"`
+	UnknownBlocks   string   `json:"unknown_blocks,omitempty"`
+	OnOutOfReach    string   `json:"on_out_of_reach,omitempty"`
+	Rotation        *int     `json:"rotation,omitempty"` // build_from_file only
+}
+
+// parseBuildPreset reads the settings of a preset from JSON using the placement
+// tools' argument names, rejecting unknown settings and invalid values.
+func parseBuildPreset(name, settings string) (BuildPreset, error) {
+	if strings.TrimSpace(name) == "" {
+		return BuildPreset{}, fmt.Errorf("preset name must not be empty")
+	}
+	var p BuildPreset
+	dec := json.NewDecoder(strings.NewReader(settings))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return BuildPreset{}, fmt.Errorf("invalid settings JSON: %w", err)
+	}
+	p.Name = name
+	return p, p.validate()
+}
+
+// validate checks the preset's values the way the placement tools check their
+// arguments.
+func (p BuildPreset) validate() error {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = p.arguments()
+	if _, err := placeOptionsFromRequest(req, false); err != nil {
+		return err
+	}
+	if p.OnOutOfReach != "" && p.OnOutOfReach != outOfReachTeleport && p.OnOutOfReach != outOfReachError {
+		return fmt.Errorf("invalid on_out_of_reach %q (want %s or %s)", p.OnOutOfReach, outOfReachTeleport, outOfReachError)
+	}
+	if p.MoveDelayMs != nil && *p.MoveDelayMs < 0 {
+		return fmt.Errorf("move_delay_ms must not be negative")
+	}
+	if p.Rotation != nil {
+		switch *p.Rotation {
+		case 0, 90, 180, 270:
+		default:
+			return fmt.Errorf("invalid rotation %d (want 0, 90, 180 or 270)", *p.Rotation)
+		}
+	}
+	return nil
+}
+
+// arguments returns the preset's settings as tool arguments.
+func (p BuildPreset) arguments() map[string]any {
+	args := map[string]any{}
+	if p.Reach != nil {
+		args["reach"] = *p.Reach
+	}
+	if p.DelayMs != nil {
+		args["delay_ms"] = float64(*p.DelayMs)
+	}
+	if p.MoveDelayMs != nil {
+		args["move_delay_ms"] = float64(*p.MoveDelayMs)
+	}
+	if p.Attempts != nil {
+		args["attempts"] = float64(*p.Attempts)
+	}
+	if p.ContinueOnError != nil {
+		args["continue_on_error"] = *p.ContinueOnError
+	}
+	if p.Mode != "" {
+		args["mode"] = p.Mode
+	}
+	if p.UnknownBlocks != "" {
+		args["unknown_blocks"] = p.UnknownBlocks
+	}
+	if p.OnOutOfReach != "" {
+		args["on_out_of_reach"] = p.OnOutOfReach
+	}
+	if p.Rotation != nil {
+		args["rotation"] = float64(*p.Rotation)
+	}
+	return args
+}
+
+// withBuildPreset fills in the settings of the preset named by the request's preset
+// argument for arguments the request does not set itself.
+func withBuildPreset(state *GameState, req mcp.CallToolRequest) (mcp.CallToolRequest, error) {
+	name := req.GetString("preset", "")
+	if name == "" {
+		return req, nil
+	}
+	p, ok := state.BuildPreset(name)
+	if !ok {
+		var names []string
+		for _, p := range state.BuildPresets() {
+			names = append(names, p.Name)
+		}
+		if len(names) == 0 {
+			return req, fmt.Errorf("unknown build preset %q (no presets saved)", name)
+		}
+		return req, fmt.Errorf("unknown build preset %q (known: %s)", name, strings.Join(names, ", "))
+	}
+	args := maps.Clone(req.GetArguments())
+	if args == nil {
+		args = map[string]any{}
+	}
+	for k, v := range p.arguments() {
+		if _, set := args[k]; !set {
+			args[k] = v
+		}
+	}
+	req.Params.Arguments = args
+	return req, nil
+}
+
+// loadBuildPresets reads presets saved by saveBuildPresets. A missing file yields no
+// presets.
+func loadBuildPresets(path string) (map[string]BuildPreset, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]BuildPreset{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []BuildPreset
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	presets := make(map[string]BuildPreset, len(list))
+	for i, p := range list {
+		if strings.TrimSpace(p.Name) == "" {
+			return nil, fmt.Errorf("%s: preset %d has no name", path, i)
+		}
+		if _, ok := presets[p.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate preset %q", path, p.Name)
+		}
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("%s: preset %q: %w", path, p.Name, err)
+		}
+		presets[p.Name] = p
+	}
+	return presets, nil
+}
+
+// saveBuildPresets writes presets to path as a JSON list, replacing the file
+// atomically like saveWaypoints.
+func saveBuildPresets(path string, presets []BuildPreset) error {
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadBuildPresets loads build presets from path and saves future changes back to it.
+func (gs *GameState) LoadBuildPresets(path string) error {
+	presets, err := loadBuildPresets(path)
+	if err != nil {
+		return err
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.buildPresets = presets
+	gs.buildPresetsFile = path
+	return nil
+}
+
+// SetBuildPreset adds or replaces a preset, saving all presets if a file is configured.
+// If the save fails, the previous preset is restored.
+func (gs *GameState) SetBuildPreset(p BuildPreset) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	old, existed := gs.buildPresets[p.Name]
+	gs.buildPresets[p.Name] = p
+	if gs.buildPresetsFile == "" {
+		return nil
+	}
+	if err := saveBuildPresets(gs.buildPresetsFile, gs.buildPresetListLocked()); err != nil {
+		// Keep memory in step with the file.
+		if existed {
+			gs.buildPresets[p.Name] = old
+		} else {
+			delete(gs.buildPresets, p.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// BuildPreset returns the named preset.
+func (gs *GameState) BuildPreset(name string) (BuildPreset, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	p, ok := gs.buildPresets[name]
+	return p, ok
+}
+
+// BuildPresets returns all presets sorted by name.
+func (gs *GameState) BuildPresets() []BuildPreset {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.buildPresetListLocked()
+}
+
+// buildPresetListLocked returns the presets sorted by name. Callers must hold gs.mu.
+func (gs *GameState) buildPresetListLocked() []BuildPreset {
+	list := make([]BuildPreset, 0, len(gs.buildPresets))
+	for _, p := range gs.buildPresets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseBuildPreset(t *testing.T) {
+	tests := []struct {
+		settings string
+		wantErr  string
+	}{
+		{`{"reach":4.5,"delay_ms":150,"mode":"keep","rotation":90}`, ""},
+		{`{}`, ""},
+		{`{"mode":"merge"}`, "invalid mode"},
+		{`{"reach":0}`, "reach must be positive"},
+		{`{"delay_ms":-1}`, "delay_ms must not be negative"},
+		{`{"attempts":0}`, "attempts must be at least 1"},
+		{`{"on_out_of_reach":"walk"}`, "invalid on_out_of_reach"},
+		{`{"rotation":45}`, "invalid rotation"},
+		{`{"speed":2}`, "unknown field"},
+		{`not json`, "invalid settings JSON"},
+	}
+	for _, tt := range tests {
+		_, err := parseBuildPreset("fast", tt.settings)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("parseBuildPreset(%s): expected no error, got %v", tt.settings, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseBuildPreset(%s): expected error containing %q, got %v", tt.settings, tt.wantErr, err)
+		}
+	}
+	if _, err := parseBuildPreset(" ", `{}`); err == nil {
+		t.Error("expected an empty preset name to be rejected")
+	}
+}
+
+func TestWithBuildPreset(t *testing.T) {
+	gs := NewGameState()
+	p, err := parseBuildPreset("careful", `{"reach":4,"delay_ms":300,"mode":"keep","rotation":180}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetBuildPreset(p); err != nil {
+		t.Fatal(err)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"preset": "careful", "delay_ms": float64(50)}
+	merged, err := withBuildPreset(gs, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := merged.GetInt("delay_ms", 0); got != 50 {
+		t.Errorf("expected the call's delay_ms 50 to win, got %d", got)
+	}
+	if got := merged.GetFloat("reach", 0); got != 4 {
+		t.Errorf("expected reach 4 from the preset, got %v", got)
+	}
+	if got := merged.GetString("mode", ""); got != placeModeKeep {
+		t.Errorf("expected mode keep from the preset, got %q", got)
+	}
+	if got := merged.GetInt("rotation", 0); got != 180 {
+		t.Errorf("expected rotation 180 from the preset, got %d", got)
+	}
+	if _, ok := req.GetArguments()["reach"]; ok {
+		t.Error("expected the original arguments to be left unchanged")
+	}
+
+	req.Params.Arguments = map[string]any{"preset": "quick"}
+	if _, err := withBuildPreset(gs, req); err == nil || !strings.Contains(err.Error(), "known: careful") {
+		t.Errorf("expected unknown preset error listing careful, got %v", err)
+	}
+}
+
+func TestBuildPresetsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presets.json")
+
+	gs := NewGameState()
+	if err := gs.LoadBuildPresets(path); err != nil {
+		t.Fatalf("loading a missing file should succeed, got %v", err)
+	}
+	for _, name := range []string{"slow", "fast"} {
+		p, err := parseBuildPreset(name, `{"delay_ms":200,"continue_on_error":false}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gs.SetBuildPreset(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := NewGameState()
+	if err := reloaded.LoadBuildPresets(path); err != nil {
+		t.Fatal(err)
+	}
+	presets := reloaded.BuildPresets()
+	if len(presets) != 2 || presets[0].Name != "fast" || presets[1].Name != "slow" {
+		t.Fatalf("expected presets fast and slow after reload, got %+v", presets)
+	}
+	p := presets[0]
+	if p.DelayMs == nil || *p.DelayMs != 200 || p.ContinueOnError == nil || *p.ContinueOnError {
+		t.Errorf("expected delay_ms 200 and continue_on_error false, got %+v", p)
+	}
+}
+
+func TestLoadBuildPresets_Invalid(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr string
+	}{
+		{`[{"name":"","delay_ms":100}]`, "has no name"},
+		{`[{"name":"  "}]`, "has no name"},
+		{`[{"name":"fast"},{"name":"fast","reach":3}]`, `duplicate preset "fast"`},
+		{`[{"name":"fast","mode":"merge"}]`, "invalid mode"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "presets.json")
+		if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadBuildPresets(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.data, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSetBuildPreset_SaveFails(t *testing.T) {
+	gs := NewGameState()
+	// The directory does not exist, so loading succeeds with no presets but saving fails.
+	if err := gs.LoadBuildPresets(filepath.Join(t.TempDir(), "missing", "presets.json")); err != nil {
+		t.Fatal(err)
+	}
+	gs.mu.Lock()
+	gs.buildPresets["slow"] = BuildPreset{Name: "slow", Mode: placeModeKeep}
+	gs.mu.Unlock()
+
+	for _, name := range []string{"slow", "fast"} {
+		p, err := parseBuildPreset(name, `{"delay_ms":50}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gs.SetBuildPreset(p); err == nil {
+			t.Errorf("%s: expected the save to fail", name)
+		}
+	}
+	if _, ok := gs.BuildPreset("fast"); ok {
+		t.Error("expected the unsaved new preset to be dropped")
+	}
+	if p, ok := gs.BuildPreset("slow"); !ok || p.Mode != placeModeKeep || p.DelayMs != nil {
+		t.Errorf("expected the previous slow preset to be restored, got %+v", p)
+	}
+}
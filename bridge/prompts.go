@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// promptArg describes one argument of a workflow prompt.
+type promptArg struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// workflowPrompt is a parameterised multi-step workflow exposed as an MCP prompt.
+// Template is a text/template rendered with the prompt arguments as a map.
+type workflowPrompt struct {
+	Name        string
+	Description string
+	Args        []promptArg
+	Template    string
+}
+
+// workflowPrompts lists the prompts registered with the MCP server. Adding a
+// workflow only requires a new entry here.
+var workflowPrompts = []workflowPrompt{
+	{
+		Name:        "build_structure_from_file",
+		Description: "Upload a .chunks structure file to the Realm and verify it was built",
+		Args: []promptArg{
+			{Name: "file", Description: "Path to the .chunks file", Required: true},
+			{Name: "delay_ms", Description: "Delay between chunk sends in milliseconds"},
+		},
+		Template: `Build the structure in {{.file}} on the Realm.
+
+1. Call get_status and make sure the realm is connected.
+2. Call get_position so you know where the structure will appear relative to the player.
+3. Call upload_structure with file "{{.file}}"{{if .delay_ms}} and delay_ms {{.delay_ms}}{{end}}.
+4. Call get_chat_history to check for errors reported by the behavior pack.
+5. Summarise how many chunks were uploaded and any problems.`,
+	},
+	{
+		Name:        "survey_surroundings",
+		Description: "Gather position, world and player information to describe the player's surroundings",
+		Template: `Survey the player's surroundings on the Realm.
+
+1. Call get_status to confirm the connection.
+2. Call get_position for coordinates, facing and dimension.
+3. Call get_world_info for the time of day, game mode, health and spawn point.
+4. Call get_players to see who else is online.
+5. Call get_chat_history with count 10 for recent activity.
+6. Describe where the player is and anything notable nearby.`,
+	},
+	{
+		Name:        "find_and_mine_ore",
+		Description: "Dig down to a target depth looking for ore, stopping safely at lava or bedrock",
+		Args: []promptArg{
+			{Name: "ore", Description: "Ore to look for, e.g. minecraft:diamond_ore", Required: true},
+			{Name: "target_y", Description: "Y level to dig down to", Required: true},
+		},
+		Template: `Find and mine {{.ore}} by digging down to y={{.target_y}}.
+
+1. Call get_position and work out how many blocks lie between the player's feet and y={{.target_y}}.
+2. Call dig_column with direction "down" and that depth.
+3. Read stopped_by in the result: if it is "lava" or "bedrock", stop and report the position instead of continuing.
+4. Once at depth, tunnel with dig_column in a horizontal direction in short runs, checking get_inventory for {{.ore}} between runs.
+5. Report how many blocks were mined and whether {{.ore}} was found.`,
+	},
+}
+
+// registerPrompts registers every workflow prompt with the MCP server.
+func registerPrompts(s *server.MCPServer) {
+	for _, wp := range workflowPrompts {
+		tmpl := template.Must(template.New(wp.Name).Option("missingkey=zero").Parse(wp.Template))
+
+		opts := []mcp.PromptOption{mcp.WithPromptDescription(wp.Description)}
+		for _, arg := range wp.Args {
+			argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.Description)}
+			if arg.Required {
+				argOpts = append(argOpts, mcp.RequiredArgument())
+			}
+			opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+		}
+
+		s.AddPrompt(mcp.NewPrompt(wp.Name, opts...), func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			text, err := renderPrompt(wp, tmpl, req.Params.Arguments)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewGetPromptResult(wp.Description, []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			}), nil
+		})
+	}
+}
+
+// renderPrompt checks required arguments and renders the prompt template.
+func renderPrompt(wp workflowPrompt, tmpl *template.Template, args map[string]string) (string, error) {
+	data := make(map[string]string, len(wp.Args))
+	for _, arg := range wp.Args {
+		v := strings.TrimSpace(args[arg.Name])
+		if v == "" && arg.Required {
+			return "", fmt.Errorf("prompt %s: missing required argument %q", wp.Name, arg.Name)
+		}
+		data[arg.Name] = v
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", wp.Name, err)
+	}
+	return sb.String(), nil
+}
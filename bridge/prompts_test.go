@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestWorkflowPrompts_Render(t *testing.T) {
+	for _, wp := range workflowPrompts {
+		tmpl, err := template.New(wp.Name).Option("missingkey=zero").Parse(wp.Template)
+		if err != nil {
+			t.Fatalf("%s: template parse error: %v", wp.Name, err)
+		}
+		args := map[string]string{}
+		for _, arg := range wp.Args {
+			args[arg.Name] = "VALUE_" + arg.Name
+		}
+		text, err := renderPrompt(wp, tmpl, args)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", wp.Name, err)
+			continue
+		}
+		for _, arg := range wp.Args {
+			if !strings.Contains(text, "VALUE_"+arg.Name) {
+				t.Errorf("%s: rendered text missing argument %q", wp.Name, arg.Name)
+			}
+		}
+	}
+}
+
+func TestRenderPrompt_MissingRequired(t *testing.T) {
+	wp := workflowPrompts[0]
+	tmpl := template.Must(template.New(wp.Name).Parse(wp.Template))
+	if _, err := renderPrompt(wp, tmpl, map[string]string{}); err == nil {
+		t.Error("expected error for missing required argument")
+	}
+}
+
+func TestRenderPrompt_OptionalOmitted(t *testing.T) {
+	wp := workflowPrompts[0] // build_structure_from_file
+	tmpl := template.Must(template.New(wp.Name).Parse(wp.Template))
+	text, err := renderPrompt(wp, tmpl, map[string]string{"file": "house.chunks"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(text, "delay_ms") {
+		t.Errorf("expected optional delay_ms to be omitted, got: %s", text)
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"time"
 
 	"github.com/sandertv/gophertunnel/minecraft"
@@ -14,7 +17,7 @@ import (
 // startProxy creates a persistent listener and accepts client connections in a loop.
 // Each client connection triggers a realm dial and relay session. The listener stays
 // alive across sessions so the port isn't released and rebound.
-func startProxy(ctx context.Context, listenAddr, inviteCode string, tokenSource oauth2.TokenSource, state *GameState) {
+func startProxy(ctx context.Context, listenAddr string, target realmTarget, tokenSource oauth2.TokenSource, xbl *xblTokenCache, state *GameState) {
 	cfg := minecraft.ListenConfig{
 		AuthenticationDisabled: true,
 		StatusProvider:         minecraft.NewStatusProvider("Burnodd Realm Proxy", "Gophertunnel"),
@@ -32,6 +35,10 @@ func startProxy(ctx context.Context, listenAddr, inviteCode string, tokenSource
 	defer listener.Close()
 
 	slog.Info("proxy listening", "address", listenAddr)
+	state.SetProxyInfo(listenAddr, target)
+	state.SetRealmCheck(func(ctx context.Context, target realmTarget) error {
+		return checkRealm(ctx, tokenSource, target)
+	})
 
 	// Close listener when context is cancelled
 	go func() {
@@ -58,139 +65,366 @@ func startProxy(ctx context.Context, listenAddr, inviteCode string, tokenSource
 		clientConn := c.(*minecraft.Conn)
 		slog.Info("client connected", "remote", clientConn.RemoteAddr())
 
-		if err := handleSession(ctx, clientConn, inviteCode, tokenSource, state); err != nil {
+		if err := handleSession(ctx, clientConn, state.RealmTarget(), tokenSource, xbl, state); err != nil {
 			slog.Error("session error", "error", err)
+			state.RecordSessionError(err)
 		}
 
 		state.ClearConnections()
+		state.ClearBlocks()
 		state.SetStatus(StatusDisconnected)
 		slog.Info("session ended, waiting for new client")
 	}
 }
 
-// handleSession manages one client→realm relay session.
-func handleSession(ctx context.Context, clientConn *minecraft.Conn, inviteCode string, tokenSource oauth2.TokenSource, state *GameState) error {
-	state.SetStatus(StatusConnectingToRealm)
-
-	// Resolve realm address
-	realmAddr, err := resolveRealmAddress(ctx, tokenSource, inviteCode)
-	if err != nil {
+// handleSession manages one client→realm relay session. When the Realm connection
+// drops while the client is still connected, the proxy reconnects to the Realm
+// behind the client's back (see reconnectRealm). A followed Transfer moves the
+// session to the target the same way (see followTransfer).
+func handleSession(ctx context.Context, clientConn *minecraft.Conn, target realmTarget, tokenSource oauth2.TokenSource, xbl *xblTokenCache, state *GameState) error {
+	if err := checkClientProtocol(clientConn, state); err != nil {
 		clientConn.Close()
 		return err
 	}
 
-	// Dial the realm
-	dialer := minecraft.Dialer{
-		TokenSource: tokenSource,
+	state.SetStatus(StatusConnectingToRealm)
+
+	// dial reaches the server the session is on: the Realm, or the target of the
+	// last followed transfer.
+	dial := func(ctx context.Context) (*minecraft.Conn, error) {
+		return connectRealm(ctx, target, tokenSource, xbl, state)
 	}
-	serverConn, err := dialer.DialContext(ctx, "raknet", realmAddr)
+	serverConn, err := dial(ctx)
 	if err != nil {
 		clientConn.Close()
 		return err
 	}
 
-	// Perform handshake: spawn the client and the server connection
+	// Perform handshake: spawn the client and the server connection. A Realm that
+	// does not spawn the player in time is dialed again; the client, already started
+	// with the first connection's game data, is then moved to the new spawn.
 	gd := serverConn.GameData()
-	id := serverConn.IdentityData()
+	first := serverConn
+	spawnCtx, cancelSpawn := context.WithCancel(ctx)
+	defer cancelSpawn()
 
-	errs := make(chan error, 2)
+	type spawnResult struct {
+		conn *minecraft.Conn
+		err  error
+	}
+	started := make(chan error, 1)
+	spawned := make(chan spawnResult, 1)
 	go func() {
-		errs <- clientConn.StartGame(gd)
+		started <- clientConn.StartGame(gd)
 	}()
 	go func() {
-		errs <- serverConn.DoSpawn()
+		conn, err := spawnWithRetry(spawnCtx, serverConn, dial, spawnAttempts, spawnAttemptTimeout)
+		spawned <- spawnResult{conn, err}
 	}()
-	for i := 0; i < 2; i++ {
-		if err := <-errs; err != nil {
+	spawnPending := true
+	for pending := 2; pending > 0; pending-- {
+		var err error
+		select {
+		case err = <-started:
+		case r := <-spawned:
+			spawnPending, serverConn, err = false, r.conn, r.err
+		}
+		if err != nil {
+			cancelSpawn()
+			clientConn.Close()
+			if spawnPending {
+				serverConn = (<-spawned).conn
+			}
 			serverConn.Close()
+			return err
+		}
+	}
+	if serverConn != first {
+		if err := resyncClient(serverConn, clientConn, gd.EntityRuntimeID, gd.Dimension, state); err != nil {
 			clientConn.Close()
 			return err
 		}
 	}
 
+	sessionCtx, sessionCancel := context.WithCancel(ctx)
+	defer sessionCancel()
+	state.SetSessionCancel(sessionCancel)
+
+	startRealmSession(serverConn, clientConn, state)
+	state.RecordEvent(EventJoined, "joined the Realm as "+serverConn.IdentityData().DisplayName)
+
+	// The client is read by one goroutine for the whole session so that its packets
+	// keep flowing to whichever Realm connection is current.
+	clientPackets := readClientPackets(sessionCtx, clientConn)
+	for {
+		end := relaySession(sessionCtx, serverConn, clientConn, clientPackets, state)
+		serverConn.Close()
+		if sessionCtx.Err() != nil {
+			break
+		}
+		if end == relayTransferred {
+			t := *state.LastTransfer()
+			serverConn, err = followTransfer(sessionCtx, t, tokenSource, clientConn, clientPackets, gd.EntityRuntimeID, state)
+			if err != nil {
+				slog.Warn("transfer follow failed", "error", err)
+				break
+			}
+			dial = func(ctx context.Context) (*minecraft.Conn, error) {
+				return dialServer(ctx, t.Target(), "transfer", tokenSource, state)
+			}
+			continue
+		}
+		if end != relayServerLost {
+			break
+		}
+		serverConn, err = reconnectRealm(sessionCtx, dial, clientConn, clientPackets, gd.EntityRuntimeID, state)
+		if err != nil {
+			slog.Warn("realm reconnect failed", "error", err)
+			break
+		}
+	}
+
+	sessionCancel()
+	clientConn.Close()
+
+	return nil
+}
+
+// connectRealm resolves and dials the Realm without spawning.
+func connectRealm(ctx context.Context, target realmTarget, tokenSource oauth2.TokenSource, xbl *xblTokenCache, state *GameState) (*minecraft.Conn, error) {
+	realmAddr, realmProtocol, err := resolveRealmAddress(ctx, tokenSource, xbl, target, state.RealmWait())
+	if err != nil {
+		return nil, err
+	}
+	return dialServer(ctx, realmAddr, realmProtocol, tokenSource, state)
+}
+
+// dialServer dials a server at address without spawning and records it as the
+// session's endpoint.
+func dialServer(ctx context.Context, address, protocol string, tokenSource oauth2.TokenSource, state *GameState) (*minecraft.Conn, error) {
+	// The client finished its own handshake with the listener before the Realm was
+	// dialed, so the Realm's packs cannot be offered to it; the proxy negotiates them
+	// on the client's behalf.
+	packs := &resourcePackRecorder{mode: state.ResourcePackMode()}
+	dialer := minecraft.Dialer{
+		TokenSource:          tokenSource,
+		DownloadResourcePack: packs.download,
+	}
+	serverConn, err := dialer.DialContext(ctx, "raknet", address)
+	if err != nil {
+		return nil, err
+	}
+	state.SetResourcePacks(packs.result(serverConn.ResourcePacks()))
+	state.SetRealmEndpoint(address, protocol)
+	return serverConn, nil
+}
+
+// startRealmSession records a spawned Realm connection in the state and marks the
+// proxy connected.
+func startRealmSession(serverConn, clientConn *minecraft.Conn, state *GameState) {
+	gd := serverConn.GameData()
+	id := serverConn.IdentityData()
 	slog.Info("connected to realm",
 		"world", gd.WorldName,
 		"player", id.DisplayName,
 		"xuid", id.XUID,
+		"base_game_version", gd.BaseGameVersion,
 	)
+	state.SetRealmBaseGameVersion(gd.BaseGameVersion)
 
 	// Initialize state
 	state.SetConnections(serverConn, clientConn)
 	state.SetIdentity(id.DisplayName, id.XUID, gd.EntityRuntimeID)
 	state.InitFromGameData(gd)
 	state.SetStatus(StatusConnected)
+}
 
-	// Start PlayerAuthInput tick loop to keep the realm connection alive
-	sessionCtx, sessionCancel := context.WithCancel(ctx)
-	defer sessionCancel()
-
-	go playerAuthInputLoop(sessionCtx, serverConn, gd)
-
-	// Relay packets bidirectionally with interception
-	done := make(chan struct{}, 2)
+// Ways a relay of one Realm connection can end.
+const (
+	relayClientLost = iota
+	relayServerLost
+	relayCancelled
+	relayTransferred // the server sent a Transfer the proxy follows
+	relayServerEnded // the server kicked the player or transferred them away
+)
 
-	// client → server
+// readClientPackets reads packets from the client until it disconnects or ctx is
+// done. The channel is closed when reading stops.
+func readClientPackets(ctx context.Context, clientConn *minecraft.Conn) <-chan packet.Packet {
+	ch := make(chan packet.Packet, 64)
 	go func() {
-		defer func() { done <- struct{}{} }()
+		defer close(ch)
 		for {
 			pk, err := clientConn.ReadPacket()
 			if err != nil {
 				return
 			}
-			interceptClientPacket(pk, state)
-			if err := serverConn.WritePacket(pk); err != nil {
+			select {
+			case ch <- pk:
+			case <-ctx.Done():
 				return
 			}
 		}
 	}()
+	return ch
+}
+
+// relaySession relays packets between the client and one Realm connection, with
+// interception, until either side goes away or ctx is done.
+func relaySession(ctx context.Context, serverConn, clientConn *minecraft.Conn, clientPackets <-chan packet.Packet, state *GameState) int {
+	// Start PlayerAuthInput tick loop to keep the realm connection alive
+	connCtx, connCancel := context.WithCancel(ctx)
+	defer connCancel()
+	go playerAuthInputLoop(connCtx, serverConn, serverConn.GameData(), state)
+
+	toServer := newPacketRelay(packetDirClient, state)
+	toClient := newPacketRelay(packetDirServer, state)
 
 	// server → client
+	serverDone := make(chan int, 1)
 	go func() {
-		defer func() { done <- struct{}{} }()
 		for {
 			pk, err := serverConn.ReadPacket()
 			if err != nil {
-				return
+				msg, ok := serverDisconnectMessage(err)
+				if !ok {
+					serverDone <- relayServerLost
+					return
+				}
+				pk = &packet.Disconnect{Message: msg}
 			}
 			interceptServerPacket(pk, state)
-			if err := clientConn.WritePacket(pk); err != nil {
+			if ownServerResponse(pk, state) {
+				continue
+			}
+			end, ends := serverRelayEnd(pk, state)
+			// A followed Transfer is not passed on: the client stays and the session
+			// moves to the target.
+			if end != relayTransferred {
+				if err := toClient.forward(pk, clientConn); err != nil {
+					serverDone <- relayClientLost
+					return
+				}
+			}
+			if ends {
+				serverDone <- end
 				return
 			}
 		}
 	}()
 
-	// Wait for either relay to finish (disconnect)
-	select {
-	case <-done:
-	case <-ctx.Done():
+	// client → server
+	for {
+		select {
+		case pk, ok := <-clientPackets:
+			if !ok {
+				return relayClientLost
+			}
+			interceptClientPacket(pk, state)
+			if err := toServer.forward(pk, serverConn); err != nil {
+				return relayServerLost
+			}
+		case end := <-serverDone:
+			return end
+		case <-ctx.Done():
+			return relayCancelled
+		}
 	}
+}
 
-	sessionCancel()
-	serverConn.Close()
-	clientConn.Close()
+// serverRelayEnd returns how a relay ends when the server sends pk, or false if it
+// goes on. A kick or a Transfer ends the session on purpose, so the proxy does not
+// reconnect after one.
+func serverRelayEnd(pk packet.Packet, state *GameState) (int, bool) {
+	switch pk.(type) {
+	case *packet.Transfer:
+		if state.FollowTransfers() {
+			return relayTransferred, true
+		}
+		return relayServerEnded, true
+	case *packet.Disconnect:
+		return relayServerEnded, true
+	}
+	return 0, false
+}
+
+// serverDisconnectMessage returns the message of the Disconnect packet that closed
+// a server connection, given the error ReadPacket returned. gophertunnel handles the
+// packet itself and closes the connection with a net.ErrClosed operation named
+// after the message.
+func serverDisconnectMessage(err error) (string, bool) {
+	var d minecraft.DisconnectError
+	if errors.As(err, &d) {
+		return string(d), true
+	}
+	read, ok := err.(*net.OpError)
+	if !ok {
+		return "", false
+	}
+	cause, ok := read.Err.(*net.OpError)
+	if !ok || cause.Err != net.ErrClosed {
+		return "", false
+	}
+	return cause.Op, true
+}
 
+// checkClientProtocol records the client's protocol version and warns when it differs
+// from the protocol the proxy speaks to the Realm, since packets are relayed without
+// translation and mismatches corrupt them in subtle ways. With strict protocol checking
+// enabled, mismatched clients are refused.
+func checkClientProtocol(clientConn *minecraft.Conn, state *GameState) error {
+	state.SetClientVersion(clientConn.Proto().ID(), clientConn.ClientData().GameVersion)
+	v := state.ProtocolVersions()
+	if !v.Mismatch {
+		return nil
+	}
+	slog.Warn("client protocol differs from proxy protocol",
+		"client_protocol", v.ClientProtocol,
+		"client_version", v.ClientVersion,
+		"proxy_protocol", v.ProxyProtocol,
+		"proxy_version", v.ProxyVersion,
+	)
+	if state.StrictProtocol() {
+		return fmt.Errorf("refusing client on protocol %d (%s): proxy speaks %d (%s)",
+			v.ClientProtocol, v.ClientVersion, v.ProxyProtocol, v.ProxyVersion)
+	}
 	return nil
 }
 
 // playerAuthInputLoop sends PlayerAuthInput packets every tick (50ms) to keep
 // the Realm treating us as an active player. Without this, Realms silently
-// drops chat/command packets.
-func playerAuthInputLoop(ctx context.Context, conn *minecraft.Conn, gd minecraft.GameData) {
-	ticker := time.NewTicker(50 * time.Millisecond)
-	defer ticker.Stop()
+// drops chat/command packets. When anti-idle is enabled and the agent has been
+// idle, the head is turned slightly for one tick to signal activity. With idle
+// throttling, packets slow to a keep-alive cadence while the agent is idle.
+func playerAuthInputLoop(ctx context.Context, conn *minecraft.Conn, gd minecraft.GameData, state *GameState) {
+	wait := playerInputTick
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
 
 	var tick uint64
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			tick++
+		case <-timer.C:
+			// Keep the tick counter in step with time, as if every tick were sent.
+			tick += uint64(wait / playerInputTick)
+			next := state.InputInterval(time.Now())
+			if next != wait {
+				slog.Info("player input rate changed", "idle", next > playerInputTick, "interval", next)
+			}
+			wait = next
+			timer.Reset(wait)
+			headYaw := gd.Yaw
+			if state.AntiIdleDue(time.Now()) {
+				headYaw += antiIdleYawJitter
+				slog.Debug("anti-idle: signalling activity", "tick", tick)
+			}
 			conn.WritePacket(&packet.PlayerAuthInput{
 				Position:         gd.PlayerPosition,
 				Pitch:            gd.Pitch,
 				Yaw:              gd.Yaw,
-				HeadYaw:          gd.Yaw,
+				HeadYaw:          headYaw,
 				InputData:        protocol.NewBitset(packet.PlayerAuthInputBitsetSize),
 				Tick:             tick,
 				InputMode:        packet.InputModeMouse,
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestServerRelayEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		pk       packet.Packet
+		follow   bool
+		expected int
+		ends     bool
+	}{
+		{"kick", &packet.Disconnect{Message: "Kicked"}, false, relayServerEnded, true},
+		{"transfer", &packet.Transfer{Address: "play.example.net", Port: 19132}, false, relayServerEnded, true},
+		{"followed transfer", &packet.Transfer{Address: "play.example.net", Port: 19132}, true, relayTransferred, true},
+		{"other", &packet.Text{Message: "hi"}, false, 0, false},
+	}
+	for _, tt := range tests {
+		gs := NewGameState()
+		gs.SetFollowTransfers(tt.follow)
+		end, ends := serverRelayEnd(tt.pk, gs)
+		if end != tt.expected || ends != tt.ends {
+			t.Errorf("%s: expected (%d, %v), got (%d, %v)", tt.name, tt.expected, tt.ends, end, ends)
+		}
+	}
+}
+
+func TestServerDisconnectMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+		ok       bool
+	}{
+		// What ReadPacket returns after the server sent a Disconnect.
+		{"kick", &net.OpError{Op: "read packet", Net: "minecraft", Err: &net.OpError{Op: "You were kicked", Net: "minecraft", Err: net.ErrClosed}}, "You were kicked", true},
+		{"closed by the proxy", &net.OpError{Op: "read packet", Net: "minecraft", Err: net.ErrClosed}, "", false},
+		{"connection lost", &net.OpError{Op: "read packet", Net: "minecraft", Err: &net.OpError{Op: "read", Net: "raknet", Err: errors.New("timeout")}}, "", false},
+		{"other", errors.New("boom"), "", false},
+	}
+	for _, tt := range tests {
+		msg, ok := serverDisconnectMessage(tt.err)
+		if msg != tt.expected || ok != tt.ok {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tt.name, tt.expected, tt.ok, msg, ok)
+		}
+	}
+}
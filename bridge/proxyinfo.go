@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// ProxyInfo describes this proxy instance: where it listens and which Realm it relays
+// to. The invite code is masked.
+type ProxyInfo struct {
+	ListenAddress     string    `json:"listen_address"`
+	RealmName         string    `json:"realm_name,omitempty"`
+	InviteCode        string    `json:"invite_code,omitempty"`
+	RealmAddress      string    `json:"realm_address,omitempty"`  // empty until a session is established
+	RealmProtocol     string    `json:"realm_protocol,omitempty"` // from the Realm join response
+	StartedAt         time.Time `json:"started_at"`
+	UptimeSecs        float64   `json:"uptime_seconds"`
+	SessionUptimeSecs float64   `json:"session_uptime_seconds,omitempty"`
+
+	FollowTransfers bool          `json:"follow_transfers"`
+	LastTransfer    *TransferInfo `json:"last_transfer,omitempty"`
+}
+
+// maskInviteCode hides all but the first and last two characters of an invite code.
+// Codes of four characters or fewer are hidden entirely.
+func maskInviteCode(code string) string {
+	if len(code) <= 4 {
+		return strings.Repeat("*", len(code))
+	}
+	return code[:2] + strings.Repeat("*", len(code)-4) + code[len(code)-2:]
+}
+
+// SetProxyInfo records the listen address and Realm target when the proxy starts.
+func (gs *GameState) SetProxyInfo(listenAddr string, target realmTarget) {
+	gs.mu.Lock()
+	gs.proxyInfo = ProxyInfo{
+		ListenAddress: listenAddr,
+		StartedAt:     time.Now(),
+	}
+	gs.mu.Unlock()
+	gs.SetRealmTarget(target)
+}
+
+// SetRealmEndpoint records the Realm address and protocol of an established session.
+func (gs *GameState) SetRealmEndpoint(address, protocol string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.proxyInfo.RealmAddress = address
+	gs.proxyInfo.RealmProtocol = protocol
+	gs.sessionStartedAt = time.Now()
+}
+
+// ProxyInfo returns the proxy's listen address, Realm endpoint and uptime.
+func (gs *GameState) ProxyInfo() ProxyInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	info := gs.proxyInfo
+	if !info.StartedAt.IsZero() {
+		info.UptimeSecs = time.Since(info.StartedAt).Seconds()
+	}
+	if gs.status == StatusConnected && !gs.sessionStartedAt.IsZero() {
+		info.SessionUptimeSecs = time.Since(gs.sessionStartedAt).Seconds()
+	}
+	info.FollowTransfers = gs.followTransfers
+	if gs.lastTransfer != nil {
+		t := *gs.lastTransfer
+		info.LastTransfer = &t
+	}
+	return info
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestMaskInviteCode(t *testing.T) {
+	tests := []struct {
+		code, want string
+	}{
+		{"", ""},
+		{"abcd", "****"},
+		{"abcde", "ab*de"},
+		{"Xy12AbCdEf9", "Xy*******f9"},
+	}
+	for _, tt := range tests {
+		if got := maskInviteCode(tt.code); got != tt.want {
+			t.Errorf("maskInviteCode(%q): expected %q, got %q", tt.code, tt.want, got)
+		}
+	}
+}
+
+func TestProxyInfo(t *testing.T) {
+	gs := NewGameState()
+	gs.SetProxyInfo(":19132", realmTarget{InviteCode: "Xy12AbCdEf9"})
+	gs.SetRealmEndpoint("1.2.3.4:19132", "RAKNET")
+
+	info := gs.ProxyInfo()
+	if info.ListenAddress != ":19132" || info.InviteCode != "Xy*******f9" {
+		t.Errorf("expected listen address and masked invite, got %+v", info)
+	}
+	if info.RealmAddress != "1.2.3.4:19132" || info.RealmProtocol != "RAKNET" {
+		t.Errorf("expected realm endpoint, got %+v", info)
+	}
+	if info.SessionUptimeSecs != 0 {
+		t.Errorf("expected no session uptime while disconnected, got %v", info.SessionUptimeSecs)
+	}
+	gs.SetStatus(StatusConnected)
+	if info := gs.ProxyInfo(); info.StartedAt.IsZero() || info.SessionUptimeSecs <= 0 {
+		t.Errorf("expected uptimes once connected, got %+v", info)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// maxRawPacketTypes bounds the number of packet types whose last instance is kept.
+const maxRawPacketTypes = 256
+
+// RawPacket is the most recently intercepted instance of a packet type.
+type RawPacket struct {
+	Type      string        `json:"type"`
+	Direction string        `json:"direction"`
+	Time      time.Time     `json:"time"`
+	Packet    packet.Packet `json:"packet"`
+}
+
+// RecordRawPacket keeps pk as the last seen instance of its type. Packets are only
+// captured while verbose packet logging is enabled, to avoid the overhead otherwise.
+func (gs *GameState) RecordRawPacket(pk packet.Packet, dir string) {
+	if !gs.verbosePacketLog.Load() {
+		return
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	name := packetTypeName(pk)
+	if _, ok := gs.rawPackets[name]; !ok && len(gs.rawPackets) >= maxRawPacketTypes {
+		return
+	}
+	gs.rawPackets[name] = RawPacket{Type: name, Direction: dir, Time: time.Now(), Packet: pk}
+}
+
+// RawPacket returns the last captured instance of the named packet type.
+func (gs *GameState) RawPacket(name string) (RawPacket, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	rp, ok := gs.rawPackets[name]
+	return rp, ok
+}
+
+// RawPacketTypes returns the sorted names of packet types with a captured instance.
+func (gs *GameState) RawPacketTypes() []string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	names := make([]string, 0, len(gs.rawPackets))
+	for name := range gs.rawPackets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
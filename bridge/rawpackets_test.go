@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestRecordRawPacket_VerboseOnly(t *testing.T) {
+	gs := NewGameState()
+	gs.RecordRawPacket(&packet.SetTime{Time: 1000}, packetDirServer)
+	if _, ok := gs.RawPacket("SetTime"); ok {
+		t.Error("expected no capture when verbose off")
+	}
+
+	gs.SetVerbosePacketLog(true)
+	gs.RecordRawPacket(&packet.SetTime{Time: 1000}, packetDirServer)
+	gs.RecordRawPacket(&packet.SetTime{Time: 2000}, packetDirServer)
+	rp, ok := gs.RawPacket("SetTime")
+	if !ok {
+		t.Fatal("expected SetTime to be captured")
+	}
+	if pk := rp.Packet.(*packet.SetTime); pk.Time != 2000 {
+		t.Errorf("expected latest packet with time 2000, got %d", pk.Time)
+	}
+	if rp.Direction != packetDirServer {
+		t.Errorf("expected direction %s, got %s", packetDirServer, rp.Direction)
+	}
+}
+
+func TestRecordRawPacket_Bounded(t *testing.T) {
+	gs := NewGameState()
+	gs.SetVerbosePacketLog(true)
+	for i := 0; i < maxRawPacketTypes; i++ {
+		gs.rawPackets[fmt.Sprintf("Type%d", i)] = RawPacket{}
+	}
+	gs.RecordRawPacket(&packet.SetTime{}, packetDirServer)
+	if _, ok := gs.RawPacket("SetTime"); ok {
+		t.Error("expected new type to be dropped when the map is full")
+	}
+	if got := len(gs.RawPacketTypes()); got != maxRawPacketTypes {
+		t.Errorf("expected %d types, got %d", maxRawPacketTypes, got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Placement reach defaults. Vanilla survival reach is about 4.5 blocks from the eyes;
+// creative players reach further, so the default leaves some margin over survival.
+const (
+	DefaultReachDistance = 5.0
+	DefaultMoveDelay     = 250 * time.Millisecond
+)
+
+// Out-of-reach policies for block actions.
+const (
+	outOfReachTeleport = "teleport"
+	outOfReachError    = "error"
+)
+
+// reachDistance returns the distance from the player's eyes to the centre of a block.
+// Tracked positions are at eye level.
+func reachDistance(state *GameState, x, y, z int32) float64 {
+	px, py, pz, _, _, _ := state.Position()
+	dx := float64(x) + 0.5 - float64(px)
+	dy := float64(y) + 0.5 - float64(py)
+	dz := float64(z) + 0.5 - float64(pz)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// ensureInReach makes sure the block at x, y, z is within reach of the player. If it is
+// not, the player is either teleported to stand two blocks above it and given moveDelay
+// for the server to confirm the move, or an error is returned, depending on policy.
+// It reports whether the player was moved.
+func ensureInReach(ctx context.Context, state *GameState, x, y, z int32, reach float64, policy string, moveDelay time.Duration) (bool, error) {
+	if d := reachDistance(state, x, y, z); d <= reach {
+		return false, nil
+	} else if policy != outOfReachTeleport {
+		return false, fmt.Errorf("block at %d,%d,%d is %.1f blocks away, beyond reach %.1f", x, y, z, d, reach)
+	}
+	if err := sendCommand(state, fmt.Sprintf("tp @s %.1f %d %.1f", float32(x)+0.5, y+2, float32(z)+0.5)); err != nil {
+		return false, fmt.Errorf("teleport: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+		return true, ctx.Err()
+	case <-time.After(moveDelay):
+	}
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReachDistance(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(0.5, 65.5, 0.5, 0, 0)
+
+	tests := []struct {
+		x, y, z int32
+		want    float64
+	}{
+		{0, 65, 0, 0},
+		{3, 65, 0, 3},
+		{0, 65, 4, 4},
+		{3, 69, 0, 5},
+	}
+	for _, tt := range tests {
+		if got := reachDistance(gs, tt.x, tt.y, tt.z); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("reachDistance(%d,%d,%d) = %v, expected %v", tt.x, tt.y, tt.z, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureInReach(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(0.5, 65.5, 0.5, 0, 0)
+	ctx := context.Background()
+
+	moved, err := ensureInReach(ctx, gs, 2, 65, 2, DefaultReachDistance, outOfReachError, 0)
+	if moved || err != nil {
+		t.Errorf("expected in-reach block to need no move, got moved=%v err=%v", moved, err)
+	}
+
+	_, err = ensureInReach(ctx, gs, 20, 65, 0, DefaultReachDistance, outOfReachError, 0)
+	if err == nil || !strings.Contains(err.Error(), "beyond reach") {
+		t.Errorf("expected beyond reach error, got %v", err)
+	}
+
+	// Teleporting needs a server connection.
+	_, err = ensureInReach(ctx, gs, 20, 65, 0, DefaultReachDistance, outOfReachTeleport, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "teleport") {
+		t.Errorf("expected teleport error without a connection, got %v", err)
+	}
+}
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sandertv/gophertunnel/minecraft/auth"
@@ -15,55 +18,239 @@ import (
 	"golang.org/x/oauth2"
 )
 
-// resolveRealmAddress looks up a Realm by invite code and returns its RakNet address.
-func resolveRealmAddress(ctx context.Context, tokenSource oauth2.TokenSource, inviteCode string) (string, error) {
-	client := realms.NewClient(tokenSource, nil)
+const realmsRelyingParty = "https://pocket.realms.minecraft.net/"
 
-	slog.Info("looking up realm...")
-	realm, err := client.Realm(ctx, inviteCode)
+// XBL tokens issued for the Realms relying party are valid for roughly 16 hours, but
+// gophertunnel's auth.XBLToken does not expose the NotAfter timestamp from the XSTS
+// response. We therefore assume a conservative lifetime from the moment the token was
+// fetched and refresh a little early so long transfers never start with a stale token.
+const (
+	xblTokenLifetime     = 12 * time.Hour
+	xblTokenSafetyMargin = 5 * time.Minute
+)
+
+var errRealmsUnauthorized = errors.New("realms API unauthorized")
+
+// errRealmStarting is returned while the join endpoint answers 503 because the
+// Realm is waking up.
+var errRealmStarting = errors.New("realm is starting")
+
+// Polling of the Realms join endpoint.
+const (
+	realmJoinAttempts      = 10
+	realmJoinRetryInterval = 3 * time.Second
+)
+
+// requestXBLToken is the XBL token fetcher, replaced in tests.
+var requestXBLToken = auth.RequestXBLToken
+
+// xblTokenCache caches an XBL token for a relying party and transparently re-requests
+// it once it has expired.
+// main creates one for the Realms API that every session shares.
+type xblTokenCache struct {
+	mu           sync.Mutex
+	tokenSource  oauth2.TokenSource
+	relyingParty string
+	now          func() time.Time
+
+	token    *auth.XBLToken
+	notAfter time.Time
+}
+
+func newXBLTokenCache(tokenSource oauth2.TokenSource, relyingParty string) *xblTokenCache {
+	return &xblTokenCache{
+		tokenSource:  tokenSource,
+		relyingParty: relyingParty,
+		now:          time.Now,
+	}
+}
+
+// Token returns the cached XBL token, requesting a new one if none is cached or the
+// cached one is within the safety margin of expiring.
+func (c *xblTokenCache) Token(ctx context.Context) (*auth.XBLToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != nil && c.now().Before(c.notAfter.Add(-xblTokenSafetyMargin)) {
+		return c.token, nil
+	}
+
+	t, err := c.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+	fetched := c.now()
+	xbl, err := requestXBLToken(ctx, t, c.relyingParty)
 	if err != nil {
-		return "", fmt.Errorf("realm lookup error: %w", err)
+		return nil, err
 	}
+	c.token = xbl
+	c.notAfter = fetched.Add(xblTokenLifetime)
+	slog.Debug("requested XBL token", "relying_party", c.relyingParty, "not_after", c.notAfter)
+	return c.token, nil
+}
 
-	slog.Info("found realm", "name", realm.Name, "id", realm.ID)
+// Invalidate drops the cached token so the next call to Token requests a new one.
+func (c *xblTokenCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = nil
+}
 
-	// Retry loop: handles 503 (realm starting up) and NETHERNET UUID addresses.
-	for attempt := range 10 {
-		address, protocol, err := realmJoin(ctx, tokenSource, realm.ID)
-		if err != nil {
-			slog.Warn("realm join failed, retrying...", "error", err, "attempt", attempt+1)
-			select {
-			case <-ctx.Done():
-				return "", ctx.Err()
-			case <-time.After(3 * time.Second):
+// realmTarget identifies the Realm to connect to, by name and/or invite code.
+// When both are set the name is tried first and the invite code is the fallback.
+type realmTarget struct {
+	Name       string
+	InviteCode string
+}
+
+// selectRealmByName returns the Realm whose name matches name case-insensitively.
+// It is an error if no Realm or more than one Realm matches.
+func selectRealmByName(list []realms.Realm, name string) (realms.Realm, error) {
+	var matches []realms.Realm
+	for _, r := range list {
+		if strings.EqualFold(r.Name, name) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		names := make([]string, len(list))
+		for i, r := range list {
+			names[i] = r.Name
+		}
+		return realms.Realm{}, fmt.Errorf("no realm named %q (available: %s)", name, strings.Join(names, ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, r := range matches {
+			ids[i] = fmt.Sprint(r.ID)
+		}
+		return realms.Realm{}, fmt.Errorf("realm name %q is ambiguous: matches realms %s", name, strings.Join(ids, ", "))
+	}
+}
+
+// lookupRealm finds the target Realm, by name among the Realms the account owns or
+// has joined, or by invite code.
+func lookupRealm(ctx context.Context, client *realms.Client, target realmTarget) (realms.Realm, error) {
+	if target.Name != "" {
+		list, err := client.Realms(ctx)
+		if err == nil {
+			var realm realms.Realm
+			realm, err = selectRealmByName(list, target.Name)
+			if err == nil {
+				return realm, nil
 			}
-			continue
 		}
+		if target.InviteCode == "" {
+			return realms.Realm{}, fmt.Errorf("realm lookup error: %w", err)
+		}
+		slog.Warn("realm lookup by name failed, falling back to invite code", "name", target.Name, "error", err)
+	}
 
-		slog.Info("realm join response", "address", address, "protocol", protocol, "attempt", attempt+1)
+	realm, err := client.Realm(ctx, target.InviteCode)
+	if err != nil {
+		return realms.Realm{}, fmt.Errorf("realm lookup error: %w", err)
+	}
+	return realm, nil
+}
 
-		if _, _, err := net.SplitHostPort(address); err == nil {
-			return address, nil
-		}
+// checkRealm looks up the target Realm without joining it.
+func checkRealm(ctx context.Context, tokenSource oauth2.TokenSource, target realmTarget) error {
+	_, err := lookupRealm(ctx, realms.NewClient(tokenSource, nil), target)
+	return err
+}
+
+// resolveRealmAddress looks up the target Realm and returns its RakNet address and the
+// network protocol the join response named, joining with the XBL token from xbl. A
+// Realm that is starting is waited for for up to wait.
+func resolveRealmAddress(ctx context.Context, tokenSource oauth2.TokenSource, xbl *xblTokenCache, target realmTarget, wait time.Duration) (address, protocol string, err error) {
+	client := realms.NewClient(tokenSource, nil)
+
+	slog.Info("looking up realm...")
+	realm, err := lookupRealm(ctx, client, target)
+	if err != nil {
+		return "", "", err
+	}
+
+	slog.Info("found realm", "name", realm.Name, "id", realm.ID)
 
-		slog.Warn("address not in host:port format (likely NETHERNET), retrying...", "address", address)
+	join := func(ctx context.Context) (string, string, error) {
+		return realmJoin(ctx, xbl, realm.ID)
+	}
+	return pollRealmJoin(ctx, join, wait, realmJoinRetryInterval)
+}
+
+// pollRealmJoin calls join until it returns a host:port address, retrying failures
+// and NETHERNET UUID addresses up to realmJoinAttempts times. With a wait above
+// zero, a Realm that is still starting is polled until wait has passed instead,
+// without using up attempts.
+func pollRealmJoin(ctx context.Context, join func(context.Context) (string, string, error), wait, interval time.Duration) (address, protocol string, err error) {
+	deadline := time.Now().Add(wait)
+	attempts := 0
+	for {
+		address, protocol, err = join(ctx)
+		switch {
+		case errors.Is(err, errRealmStarting) && wait > 0:
+			if !time.Now().Before(deadline) {
+				return "", "", fmt.Errorf("realm did not start within %s: %w", wait, err)
+			}
+			slog.Info("realm is starting, waiting...", "remaining", time.Until(deadline).Round(time.Second))
+		case err != nil:
+			attempts++
+			if attempts >= realmJoinAttempts {
+				return "", "", err
+			}
+			slog.Warn("realm join failed, retrying...", "error", err, "attempt", attempts)
+		default:
+			slog.Info("realm join response", "address", address, "protocol", protocol)
+			if _, _, err := net.SplitHostPort(address); err == nil {
+				return address, protocol, nil
+			}
+			attempts++
+			if attempts >= realmJoinAttempts {
+				return "", "", fmt.Errorf("realm address never resolved to host:port — realm may only support NETHERNET (WebRTC)")
+			}
+			slog.Warn("address not in host:port format (likely NETHERNET), retrying...", "address", address)
+		}
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(3 * time.Second):
+			return "", "", ctx.Err()
+		case <-time.After(interval):
 		}
 	}
+}
 
-	return "", fmt.Errorf("realm address never resolved to host:port — realm may only support NETHERNET (WebRTC)")
+// SetRealmWait sets how long to keep polling a Realm that is still starting.
+// 0 gives up after the usual number of join attempts.
+func (gs *GameState) SetRealmWait(d time.Duration) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.realmWait = d
+}
+
+// RealmWait returns how long a starting Realm is waited for.
+func (gs *GameState) RealmWait() time.Duration {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.realmWait
 }
 
 // realmJoin calls the Realms API join endpoint directly and returns the address and protocol.
-func realmJoin(ctx context.Context, tokenSource oauth2.TokenSource, realmID int) (address, protocol string, err error) {
-	t, err := tokenSource.Token()
-	if err != nil {
-		return "", "", err
+// A 401 response invalidates the cached XBL token and the request is retried once with a fresh one.
+func realmJoin(ctx context.Context, xbl *xblTokenCache, realmID int) (address, protocol string, err error) {
+	address, protocol, err = realmJoinOnce(ctx, xbl, realmID)
+	if errors.Is(err, errRealmsUnauthorized) {
+		slog.Warn("realms API rejected XBL token, refreshing")
+		xbl.Invalidate()
+		return realmJoinOnce(ctx, xbl, realmID)
 	}
-	xbl, err := auth.RequestXBLToken(ctx, t, "https://pocket.realms.minecraft.net/")
+	return address, protocol, err
+}
+
+func realmJoinOnce(ctx context.Context, xbl *xblTokenCache, realmID int) (address, protocol string, err error) {
+	token, err := xbl.Token(ctx)
 	if err != nil {
 		return "", "", err
 	}
@@ -75,7 +262,7 @@ func realmJoin(ctx context.Context, tokenSource oauth2.TokenSource, realmID int)
 	}
 	req.Header.Set("User-Agent", "MCPE/UWP")
 	req.Header.Set("Client-Version", "1.10.1")
-	xbl.SetAuthHeader(req)
+	token.SetAuthHeader(req)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -90,6 +277,12 @@ func realmJoin(ctx context.Context, tokenSource oauth2.TokenSource, realmID int)
 
 	slog.Debug("realm join raw response", "status", resp.StatusCode, "body", string(body))
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", "", fmt.Errorf("%w: %s", errRealmsUnauthorized, string(body))
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return "", "", fmt.Errorf("%w: %s", errRealmStarting, string(body))
+	}
 	if resp.StatusCode >= 400 {
 		return "", "", fmt.Errorf("realms API error %d: %s", resp.StatusCode, string(body))
 	}
@@ -107,10 +300,10 @@ func realmJoin(ctx context.Context, tokenSource oauth2.TokenSource, realmID int)
 }
 
 // realmJoinWithBackoff retries the join call on 503 errors.
-func realmJoinWithBackoff(ctx context.Context, tokenSource oauth2.TokenSource, realmID int) (address, protocol string, err error) {
+func realmJoinWithBackoff(ctx context.Context, xbl *xblTokenCache, realmID int) (address, protocol string, err error) {
 	delays := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
 	for i := range 4 {
-		address, protocol, err = realmJoin(ctx, tokenSource, realmID)
+		address, protocol, err = realmJoin(ctx, xbl, realmID)
 		if err == nil {
 			return
 		}
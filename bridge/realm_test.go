@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/auth"
+	"github.com/sandertv/gophertunnel/minecraft/realms"
+	"golang.org/x/oauth2"
+)
+
+// stubXBL replaces requestXBLToken for the duration of a test and counts calls.
+func stubXBL(t *testing.T) *int {
+	t.Helper()
+	calls := 0
+	old := requestXBLToken
+	requestXBLToken = func(ctx context.Context, liveToken *oauth2.Token, relyingParty string) (*auth.XBLToken, error) {
+		calls++
+		tok := &auth.XBLToken{}
+		tok.AuthorizationToken.Token = liveToken.AccessToken
+		return tok, nil
+	}
+	t.Cleanup(func() { requestXBLToken = old })
+	return &calls
+}
+
+func TestXBLTokenCache_ReusesToken(t *testing.T) {
+	calls := stubXBL(t)
+	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "live", Expiry: time.Now().Add(time.Hour)})
+	c := newXBLTokenCache(src, realmsRelyingParty)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Token(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 XBL request, got %d", *calls)
+	}
+}
+
+func TestXBLTokenCache_RefreshesWhenExpired(t *testing.T) {
+	calls := stubXBL(t)
+	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "live", Expiry: time.Now().Add(time.Hour)})
+	c := newXBLTokenCache(src, realmsRelyingParty)
+
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return clock }
+
+	if _, err := c.Token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Still valid just outside the safety margin
+	clock = clock.Add(xblTokenLifetime - xblTokenSafetyMargin - time.Second)
+	if _, err := c.Token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *calls != 1 {
+		t.Fatalf("expected cached token before expiry, got %d requests", *calls)
+	}
+
+	// Inside the safety margin the token is re-requested
+	clock = clock.Add(2 * time.Second)
+	if _, err := c.Token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected token refresh after expiry, got %d requests", *calls)
+	}
+}
+
+func TestXBLTokenCache_Invalidate(t *testing.T) {
+	calls := stubXBL(t)
+	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "live", Expiry: time.Now().Add(time.Hour)})
+	c := newXBLTokenCache(src, realmsRelyingParty)
+
+	c.Token(context.Background())
+	c.Invalidate()
+	c.Token(context.Background())
+	if *calls != 2 {
+		t.Errorf("expected 2 XBL requests after invalidate, got %d", *calls)
+	}
+}
+
+func TestSelectRealmByName(t *testing.T) {
+	list := []realms.Realm{
+		{ID: 1, Name: "Burnodd"},
+		{ID: 2, Name: "Skyblock"},
+		{ID: 3, Name: "skyblock"},
+	}
+
+	r, err := selectRealmByName(list, "BURNODD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.ID != 1 {
+		t.Errorf("expected realm 1, got %d", r.ID)
+	}
+
+	if _, err := selectRealmByName(list, "Skyblock"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+	if _, err := selectRealmByName(list, "Missing"); err == nil {
+		t.Error("expected error for unknown realm name")
+	}
+}
+
+// scriptedJoin returns a join function answering with errs in turn, then address.
+func scriptedJoin(address string, errs ...error) (func(context.Context) (string, string, error), *int) {
+	calls := 0
+	return func(ctx context.Context) (string, string, error) {
+		calls++
+		if calls <= len(errs) {
+			return "", "", errs[calls-1]
+		}
+		return address, "DEFAULT", nil
+	}, &calls
+}
+
+func TestPollRealmJoin_WaitsForStartingRealm(t *testing.T) {
+	starting := make([]error, realmJoinAttempts+5)
+	for i := range starting {
+		starting[i] = errRealmStarting
+	}
+	join, calls := scriptedJoin("1.2.3.4:19132", starting...)
+	address, _, err := pollRealmJoin(context.Background(), join, time.Minute, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address != "1.2.3.4:19132" {
+		t.Errorf("expected address 1.2.3.4:19132, got %q", address)
+	}
+	if *calls != len(starting)+1 {
+		t.Errorf("expected %d calls, got %d", len(starting)+1, *calls)
+	}
+}
+
+func TestPollRealmJoin_WaitTimesOut(t *testing.T) {
+	join := func(ctx context.Context) (string, string, error) { return "", "", errRealmStarting }
+	_, _, err := pollRealmJoin(context.Background(), join, 20*time.Millisecond, time.Millisecond)
+	if !errors.Is(err, errRealmStarting) {
+		t.Fatalf("expected errRealmStarting, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "did not start within") {
+		t.Errorf("expected timeout message, got %q", err)
+	}
+}
+
+func TestPollRealmJoin_WithoutWait(t *testing.T) {
+	starting := make([]error, realmJoinAttempts)
+	for i := range starting {
+		starting[i] = errRealmStarting
+	}
+	join, calls := scriptedJoin("1.2.3.4:19132", starting...)
+	if _, _, err := pollRealmJoin(context.Background(), join, 0, time.Millisecond); !errors.Is(err, errRealmStarting) {
+		t.Errorf("expected errRealmStarting after %d attempts, got %v", realmJoinAttempts, err)
+	}
+	if *calls != realmJoinAttempts {
+		t.Errorf("expected %d calls, got %d", realmJoinAttempts, *calls)
+	}
+
+	// Other errors use up attempts even while waiting
+	other := errors.New("boom")
+	join, _ = scriptedJoin("1.2.3.4:19132", other, errRealmStarting, other)
+	if address, _, err := pollRealmJoin(context.Background(), join, time.Minute, time.Millisecond); err != nil || address != "1.2.3.4:19132" {
+		t.Errorf("expected recovery after errors, got %q, %v", address, err)
+	}
+
+	join, _ = scriptedJoin("a1b2c3d4-uuid")
+	if _, _, err := pollRealmJoin(context.Background(), join, time.Minute, time.Millisecond); err == nil || !strings.Contains(err.Error(), "NETHERNET") {
+		t.Errorf("expected NETHERNET error, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// switch_realm settings.
+const (
+	defaultSwitchRealmWait = 60 * time.Second
+	maxSwitchRealmWait     = 5 * time.Minute
+	switchRealmPoll        = 250 * time.Millisecond
+	switchRealmMessage     = "Switching Realm, please reconnect"
+)
+
+// SetRealmTarget sets the Realm the next session connects to.
+func (gs *GameState) SetRealmTarget(target realmTarget) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.realmTarget = target
+	gs.proxyInfo.RealmName = target.Name
+	gs.proxyInfo.InviteCode = maskInviteCode(target.InviteCode)
+	gs.proxyInfo.RealmAddress = ""
+	gs.proxyInfo.RealmProtocol = ""
+}
+
+// RealmTarget returns the Realm sessions connect to.
+func (gs *GameState) RealmTarget() realmTarget {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.realmTarget
+}
+
+// SetRealmCheck stores the function that checks a Realm can be found before
+// switch_realm ends the current session.
+func (gs *GameState) SetRealmCheck(check func(context.Context, realmTarget) error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.realmCheck = check
+}
+
+// SetSessionCancel stores the function that ends the current session.
+func (gs *GameState) SetSessionCancel(cancel context.CancelFunc) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.sessionCancel = cancel
+}
+
+// EndSession disconnects the client with message and ends the current session, leaving
+// the listener up for the client to reconnect. It reports whether a session was running.
+func (gs *GameState) EndSession(message string) bool {
+	gs.mu.Lock()
+	cancel, conn := gs.sessionCancel, gs.clientConn
+	gs.sessionCancel = nil
+	gs.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	if conn != nil {
+		if err := conn.WritePacket(&packet.Disconnect{Message: message}); err == nil {
+			_ = conn.Flush()
+		}
+	}
+	cancel()
+	return true
+}
+
+// RecordSessionError records why a session failed.
+func (gs *GameState) RecordSessionError(err error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.sessionErr = err
+	gs.sessionErrAt = time.Now()
+}
+
+// sessionSince reports the outcome of the first session started after since: connected
+// is true once one is established, and err is set if one failed first.
+func (gs *GameState) sessionSince(since time.Time) (connected bool, err error) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.status == StatusConnected && gs.sessionStartedAt.After(since) {
+		return true, nil
+	}
+	if gs.sessionErr != nil && gs.sessionErrAt.After(since) {
+		return false, gs.sessionErr
+	}
+	return false, nil
+}
+
+// SwitchRealmResult is the outcome of switch_realm.
+type SwitchRealmResult struct {
+	Connected bool   `json:"connected"`
+	WorldName string `json:"world_name,omitempty"`
+	Message   string `json:"message"`
+}
+
+// switchRealm checks that a new Realm can be found, points the proxy at it, ends the
+// current session so the client reconnects, and waits up to timeout for the new
+// session. If the new Realm cannot be joined, the previous target is restored so the
+// client can reconnect to it.
+func switchRealm(ctx context.Context, state *GameState, target realmTarget, timeout time.Duration) (SwitchRealmResult, error) {
+	state.mu.RLock()
+	check := state.realmCheck
+	state.mu.RUnlock()
+	if check != nil {
+		if err := check(ctx, target); err != nil {
+			return SwitchRealmResult{}, fmt.Errorf("staying on the current Realm: %w", err)
+		}
+	}
+
+	previous := state.RealmTarget()
+	start := time.Now()
+	state.SetRealmTarget(target)
+	if !state.EndSession(switchRealmMessage) {
+		return SwitchRealmResult{Message: "no session was running; the next client connection will join the new Realm"}, nil
+	}
+
+	ticker := time.NewTicker(switchRealmPoll)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		connected, err := state.sessionSince(start)
+		if err != nil {
+			state.SetRealmTarget(previous)
+			return SwitchRealmResult{}, fmt.Errorf("joining the new Realm failed, reverted to the previous Realm: %w", err)
+		}
+		if connected {
+			worldName, _, _, _, _ := state.WorldInfo()
+			return SwitchRealmResult{Connected: true, WorldName: worldName, Message: "connected to the new Realm"}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return SwitchRealmResult{}, ctx.Err()
+		case <-deadline:
+			return SwitchRealmResult{Message: fmt.Sprintf("the client has not reconnected within %s; it will join the new Realm when it does", timeout)}, nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSwitchRealm_NoSession(t *testing.T) {
+	gs := NewGameState()
+	gs.SetProxyInfo(":19132", realmTarget{InviteCode: "oldinvite"})
+
+	result, err := switchRealm(context.Background(), gs, realmTarget{InviteCode: "newinvite"}, time.Second)
+	if err != nil || result.Connected {
+		t.Fatalf("expected pending switch, got %+v (%v)", result, err)
+	}
+	if got := gs.RealmTarget().InviteCode; got != "newinvite" {
+		t.Errorf("expected new target, got %q", got)
+	}
+	if got := gs.ProxyInfo().InviteCode; got != "ne*****te" {
+		t.Errorf("expected masked new invite in proxy info, got %q", got)
+	}
+}
+
+func TestSwitchRealm_Connects(t *testing.T) {
+	gs := NewGameState()
+	gs.SetRealmTarget(realmTarget{InviteCode: "old"})
+	ended := false
+	gs.SetSessionCancel(func() { ended = true })
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		gs.SetRealmEndpoint("1.2.3.4:19132", "RAKNET")
+		gs.SetStatus(StatusConnected)
+	}()
+	result, err := switchRealm(context.Background(), gs, realmTarget{InviteCode: "new"}, time.Second)
+	if err != nil || !result.Connected {
+		t.Fatalf("expected connected, got %+v (%v)", result, err)
+	}
+	if !ended {
+		t.Error("expected the old session to be ended")
+	}
+}
+
+func TestSwitchRealm_RevertsOnFailure(t *testing.T) {
+	gs := NewGameState()
+	gs.SetRealmTarget(realmTarget{InviteCode: "old"})
+	gs.SetSessionCancel(func() {})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		gs.RecordSessionError(errors.New("realm lookup error: not found"))
+	}()
+	if _, err := switchRealm(context.Background(), gs, realmTarget{InviteCode: "bad"}, time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := gs.RealmTarget().InviteCode; got != "old" {
+		t.Errorf("expected target reverted to old, got %q", got)
+	}
+}
+
+func TestSwitchRealm_CheckFails(t *testing.T) {
+	gs := NewGameState()
+	gs.SetRealmTarget(realmTarget{InviteCode: "old"})
+	ended := false
+	gs.SetSessionCancel(func() { ended = true })
+	gs.SetRealmCheck(func(ctx context.Context, target realmTarget) error {
+		return errors.New("realm lookup error: not found")
+	})
+
+	if _, err := switchRealm(context.Background(), gs, realmTarget{InviteCode: "bad"}, time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+	if ended {
+		t.Error("expected the session to be left running")
+	}
+	if got := gs.RealmTarget().InviteCode; got != "old" {
+		t.Errorf("expected target to stay old, got %q", got)
+	}
+}
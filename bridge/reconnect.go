@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Realm reconnection settings.
+const (
+	DefaultReconnectAttempts   = 3
+	reconnectBackoff           = 5 * time.Second
+	reconnectKeepAliveInterval = time.Second
+	reconnectTip               = "Realm connection lost, reconnecting..."
+	reconnectRejoinMessage     = "The Realm connection was restored, but the world has to be reloaded. Please rejoin."
+)
+
+// SetReconnectAttempts sets how many times the proxy tries to reconnect to the Realm
+// when the connection drops while the client is still connected. 0 disables it.
+func (gs *GameState) SetReconnectAttempts(n int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.reconnectAttempts = n
+}
+
+// ReconnectAttempts returns the number of Realm reconnection attempts.
+func (gs *GameState) ReconnectAttempts() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.reconnectAttempts
+}
+
+// keepClientAlive sends the client tip every reconnectKeepAliveInterval and
+// discards its packets while there is no Realm connection to relay them to. If the
+// client goes away, clientGone is called.
+func keepClientAlive(ctx context.Context, clientConn *minecraft.Conn, clientPackets <-chan packet.Packet, tip string, clientGone func()) {
+	ticker := time.NewTicker(reconnectKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-clientPackets:
+			if !ok {
+				clientGone()
+				return
+			}
+		case <-ticker.C:
+			if err := clientConn.WritePacket(&packet.Text{TextType: packet.TextTypeTip, Message: tip}); err != nil {
+				clientGone()
+				return
+			}
+		}
+	}
+}
+
+// resyncPackets returns the packets that move the client, which still holds the
+// world of the old connection, to where the new connection spawned the player.
+func resyncPackets(gd minecraft.GameData, dimension int32) []packet.Packet {
+	var pks []packet.Packet
+	if gd.Dimension != dimension {
+		pks = append(pks, &packet.ChangeDimension{Dimension: gd.Dimension, Position: gd.PlayerPosition})
+	}
+	return append(pks,
+		&packet.MovePlayer{
+			EntityRuntimeID: gd.EntityRuntimeID,
+			Position:        gd.PlayerPosition,
+			Pitch:           gd.Pitch,
+			Yaw:             gd.Yaw,
+			HeadYaw:         gd.Yaw,
+			Mode:            packet.MoveModeTeleport,
+		},
+		&packet.SetTime{Time: int32(gd.Time)},
+	)
+}
+
+// resyncClient moves a client started with another connection's game data to where
+// conn spawned the player. If the player's runtime ID changed, the client cannot
+// follow and is asked to rejoin. On failure, conn is closed.
+func resyncClient(conn, clientConn *minecraft.Conn, clientRuntimeID uint64, dimension int32, state *GameState) error {
+	gd := conn.GameData()
+	if gd.EntityRuntimeID != clientRuntimeID {
+		conn.Close()
+		_ = clientConn.WritePacket(&packet.Disconnect{Message: reconnectRejoinMessage})
+		state.RecordEvent(EventReconnectFailed, "reconnected, but the player's runtime ID changed; the client must rejoin")
+		return fmt.Errorf("player runtime ID changed from %d to %d", clientRuntimeID, gd.EntityRuntimeID)
+	}
+	for _, pk := range resyncPackets(gd, dimension) {
+		if err := clientConn.WritePacket(pk); err != nil {
+			conn.Close()
+			return fmt.Errorf("resynchronising client: %w", err)
+		}
+	}
+	return nil
+}
+
+// reconnectRealm dials and spawns a new connection through dial for a session whose
+// Realm connection dropped, while keeping the client connected. The client's world
+// cannot be replaced without a second StartGame, which the protocol does not allow,
+// so the new connection is only used if the Realm gives the player the runtime ID
+// the client already knows; otherwise the client is asked to rejoin.
+func reconnectRealm(ctx context.Context, dial func(context.Context) (*minecraft.Conn, error), clientConn *minecraft.Conn, clientPackets <-chan packet.Packet, clientRuntimeID uint64, state *GameState) (*minecraft.Conn, error) {
+	attempts := state.ReconnectAttempts()
+	if attempts <= 0 {
+		return nil, fmt.Errorf("realm connection lost")
+	}
+	_, _, _, _, _, dimension := state.Position()
+	state.SetConnections(nil, clientConn)
+	state.SetStatus(StatusReconnecting)
+	state.RecordEvent(EventReconnecting, "Realm connection lost, reconnecting")
+	slog.Info("realm connection lost, reconnecting", "attempts", attempts)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go keepClientAlive(ctx, clientConn, clientPackets, reconnectTip, cancel)
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		conn, err := dial(ctx)
+		if err == nil {
+			conn, err = spawnWithRetry(ctx, conn, nil, 1, spawnAttemptTimeout)
+		}
+		if err == nil {
+			cancel()
+			return resumeRealmSession(conn, clientConn, clientRuntimeID, dimension, state)
+		}
+		lastErr = err
+		slog.Warn("realm reconnect attempt failed", "attempt", attempt, "error", err)
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectBackoff):
+		}
+	}
+	if ctx.Err() != nil {
+		lastErr = fmt.Errorf("client left while reconnecting: %w", ctx.Err())
+	}
+	state.RecordEvent(EventReconnectFailed, fmt.Sprintf("could not reconnect to the Realm: %v", lastErr))
+	return nil, lastErr
+}
+
+// resumeRealmSession switches the session to a freshly spawned Realm connection.
+func resumeRealmSession(conn, clientConn *minecraft.Conn, clientRuntimeID uint64, dimension int32, state *GameState) (*minecraft.Conn, error) {
+	if err := resyncClient(conn, clientConn, clientRuntimeID, dimension, state); err != nil {
+		return nil, err
+	}
+	startRealmSession(conn, clientConn, state)
+	state.RecordEvent(EventReconnected, "reconnected to the Realm")
+	return conn, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestResyncPackets(t *testing.T) {
+	gd := minecraft.GameData{
+		EntityRuntimeID: 7,
+		Dimension:       1,
+		PlayerPosition:  mgl32.Vec3{10, 70, -5},
+		Yaw:             90,
+		Time:            6000,
+	}
+
+	pks := resyncPackets(gd, 1)
+	if len(pks) != 2 {
+		t.Fatalf("expected 2 packets in the same dimension, got %d", len(pks))
+	}
+	move, ok := pks[0].(*packet.MovePlayer)
+	if !ok || move.EntityRuntimeID != 7 || move.Position != gd.PlayerPosition || move.Mode != packet.MoveModeTeleport {
+		t.Errorf("expected teleport of entity 7 to %v, got %+v", gd.PlayerPosition, pks[0])
+	}
+	if st, ok := pks[1].(*packet.SetTime); !ok || st.Time != 6000 {
+		t.Errorf("expected SetTime 6000, got %+v", pks[1])
+	}
+
+	pks = resyncPackets(gd, 0)
+	if len(pks) != 3 {
+		t.Fatalf("expected 3 packets across dimensions, got %d", len(pks))
+	}
+	if cd, ok := pks[0].(*packet.ChangeDimension); !ok || cd.Dimension != 1 {
+		t.Errorf("expected ChangeDimension to 1 first, got %+v", pks[0])
+	}
+}
+
+func TestReconnectAttemptsDefault(t *testing.T) {
+	gs := NewGameState()
+	if got := gs.ReconnectAttempts(); got != DefaultReconnectAttempts {
+		t.Errorf("expected %d, got %d", DefaultReconnectAttempts, got)
+	}
+	gs.SetReconnectAttempts(0)
+	if got := gs.ReconnectAttempts(); got != 0 {
+		t.Errorf("expected 0, got %d", got)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// How the relay treats packets that fail to re-serialize. Packets are only
+// validated in the drop and forward modes; by default they pass through unchecked.
+const (
+	InvalidPacketsOff     = "off"
+	InvalidPacketsDrop    = "drop"
+	InvalidPacketsForward = "forward"
+)
+
+// RelayStats counts packets relayed in one session.
+type RelayStats struct {
+	ClientToServer uint64 `json:"client_to_server"`
+	ServerToClient uint64 `json:"server_to_client"`
+	Invalid        uint64 `json:"invalid"`
+	Dropped        uint64 `json:"dropped"`
+}
+
+// validatePacket checks that pk survives a marshal/unmarshal round trip using the
+// packet definitions in pool. Packets decoded with AllowInvalidPackets, or from a
+// client on a different protocol version, may hold data that panics or produces
+// garbage on re-encoding, which would crash the relay or the receiving client.
+func validatePacket(pk packet.Packet, pool packet.Pool, shieldID int32) (err error) {
+	if _, ok := pk.(*packet.Unknown); ok {
+		// Raw payloads are forwarded verbatim.
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	buf := new(bytes.Buffer)
+	pk.Marshal(protocol.NewWriter(buf, shieldID))
+
+	newPk, ok := pool[pk.ID()]
+	if !ok {
+		return fmt.Errorf("packet ID %d not in pool", pk.ID())
+	}
+	newPk().Marshal(protocol.NewReader(buf, shieldID, false))
+	if buf.Len() != 0 {
+		return fmt.Errorf("%d unread bytes after decoding", buf.Len())
+	}
+	return nil
+}
+
+// packetRelay forwards packets in one direction, validating each one first unless
+// the invalid packet mode is off.
+type packetRelay struct {
+	dir      string // packetDirClient or packetDirServer
+	mode     string // invalid packet mode
+	pool     packet.Pool
+	shieldID int32
+	state    *GameState
+}
+
+// newPacketRelay creates a relay for packets travelling in dir.
+func newPacketRelay(dir string, state *GameState) *packetRelay {
+	pool := packet.NewServerPool()
+	if dir == packetDirClient {
+		pool = packet.NewClientPool()
+	}
+	shieldID, _ := state.ResolveItemNetworkID("minecraft:shield")
+	return &packetRelay{dir: dir, mode: state.InvalidPacketMode(), pool: pool, shieldID: shieldID, state: state}
+}
+
+// forward writes pk to dst unless it fails validation and the invalid packet mode is
+// drop. Validation failures are logged and counted either way.
+func (r *packetRelay) forward(pk packet.Packet, dst *minecraft.Conn) error {
+	if r.mode == InvalidPacketsOff {
+		r.state.RecordRelayedPacket(r.dir)
+		return dst.WritePacket(pk)
+	}
+	if err := validatePacket(pk, r.pool, r.shieldID); err != nil {
+		drop := r.mode == InvalidPacketsDrop
+		r.state.RecordInvalidPacket(drop)
+		slog.Warn("invalid packet", "dir", r.dir, "pkt", packetTypeName(pk), "error", err, "dropped", drop)
+		if drop {
+			return nil
+		}
+	}
+	r.state.RecordRelayedPacket(r.dir)
+	return dst.WritePacket(pk)
+}
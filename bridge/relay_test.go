@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestValidatePacket(t *testing.T) {
+	clientPool := packet.NewClientPool()
+	serverPool := packet.NewServerPool()
+
+	tests := []struct {
+		name    string
+		pk      packet.Packet
+		pool    packet.Pool
+		wantErr bool
+	}{
+		{"valid server packet", &packet.SetTime{Time: 1000}, serverPool, false},
+		{"valid client packet", &packet.Text{TextType: packet.TextTypeChat, Message: "hi"}, clientPool, false},
+		{"unknown packet passes through", &packet.Unknown{PacketID: 0xfff, Payload: []byte{1, 2, 3}}, serverPool, false},
+		{"marshal panics", &packet.InventoryTransaction{}, clientPool, true},
+		{"not in pool", &packet.SetTime{Time: 1}, clientPool, true},
+	}
+	for _, tt := range tests {
+		err := validatePacket(tt.pk, tt.pool, 0)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestRelayStats(t *testing.T) {
+	gs := NewGameState()
+	gs.RecordRelayedPacket(packetDirClient)
+	gs.RecordRelayedPacket(packetDirServer)
+	gs.RecordRelayedPacket(packetDirServer)
+	gs.RecordInvalidPacket(true)
+	gs.RecordInvalidPacket(false)
+
+	stats := gs.RelayStats()
+	if stats.ClientToServer != 1 || stats.ServerToClient != 2 || stats.Invalid != 2 || stats.Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// A new session resets the counters.
+	gs.SetConnections(nil, nil)
+	if stats := gs.RelayStats(); stats != (RelayStats{}) {
+		t.Errorf("expected stats to reset, got %+v", stats)
+	}
+}
+
+func TestNewPacketRelay_Mode(t *testing.T) {
+	gs := NewGameState()
+	if r := newPacketRelay(packetDirServer, gs); r.mode != InvalidPacketsOff {
+		t.Errorf("expected packets to pass through unchecked by default, got mode %q", r.mode)
+	}
+	gs.SetInvalidPacketMode(InvalidPacketsDrop)
+	if r := newPacketRelay(packetDirServer, gs); r.mode != InvalidPacketsDrop {
+		t.Errorf("expected mode %q, got %q", InvalidPacketsDrop, r.mode)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// How the proxy answers the Realm's resource pack negotiation.
+const (
+	// ResourcePacksDownload downloads every pack the Realm offers before spawning.
+	ResourcePacksDownload = "download"
+	// ResourcePacksSkip tells the Realm the packs are already present, so nothing is
+	// downloaded. Use it when large packs stall the handshake.
+	ResourcePacksSkip = "skip"
+)
+
+// ResourcePackInfo describes a resource pack the Realm offered.
+type ResourcePackInfo struct {
+	UUID       string `json:"uuid"`
+	Version    string `json:"version"`
+	Name       string `json:"name,omitempty"`
+	Downloaded bool   `json:"downloaded"`
+}
+
+// resourcePackRecorder answers the dialer's DownloadResourcePack callback according
+// to mode and remembers the packs offered.
+type resourcePackRecorder struct {
+	mode  string
+	packs []ResourcePackInfo
+}
+
+// download is the minecraft.Dialer DownloadResourcePack callback.
+func (r *resourcePackRecorder) download(id uuid.UUID, version string, current, total int) bool {
+	download := r.mode != ResourcePacksSkip
+	slog.Info("realm offered resource pack", "uuid", id, "version", version, "pack", current+1, "of", total, "download", download)
+	r.packs = append(r.packs, ResourcePackInfo{UUID: id.String(), Version: version, Downloaded: download})
+	return download
+}
+
+// result returns the offered packs, named after the downloaded packs where available.
+func (r *resourcePackRecorder) result(downloaded []*resource.Pack) []ResourcePackInfo {
+	names := make(map[string]string, len(downloaded))
+	for _, p := range downloaded {
+		names[p.UUID().String()] = p.Name()
+	}
+	packs := make([]ResourcePackInfo, len(r.packs))
+	for i, p := range r.packs {
+		p.Name = names[p.UUID]
+		packs[i] = p
+	}
+	return packs
+}
+
+// SetResourcePackMode sets how resource pack negotiation with the Realm is answered.
+func (gs *GameState) SetResourcePackMode(mode string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.resourcePackMode = mode
+}
+
+// ResourcePackMode returns how resource pack negotiation with the Realm is answered.
+func (gs *GameState) ResourcePackMode() string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.resourcePackMode
+}
+
+// SetResourcePacks records the resource packs the Realm offered this session.
+func (gs *GameState) SetResourcePacks(packs []ResourcePackInfo) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.resourcePacks = packs
+}
+
+// ResourcePacks returns the resource packs the Realm offered this session.
+func (gs *GameState) ResourcePacks() []ResourcePackInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	result := make([]ResourcePackInfo, len(gs.resourcePacks))
+	copy(result, gs.resourcePacks)
+	return result
+}
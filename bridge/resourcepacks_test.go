@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestResourcePackRecorder(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		mode string
+		want bool
+	}{
+		{ResourcePacksDownload, true},
+		{ResourcePacksSkip, false},
+	}
+	for _, tt := range tests {
+		r := &resourcePackRecorder{mode: tt.mode}
+		if got := r.download(id, "1.0.0", 0, 1); got != tt.want {
+			t.Errorf("%s: expected download %v, got %v", tt.mode, tt.want, got)
+		}
+		packs := r.result(nil)
+		if len(packs) != 1 || packs[0].UUID != id.String() || packs[0].Downloaded != tt.want {
+			t.Errorf("%s: unexpected packs %+v", tt.mode, packs)
+		}
+	}
+}
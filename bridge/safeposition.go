@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Search radius settings for find_safe_position.
+const (
+	defaultSafeSearchRadius = 4
+	maxSafeSearchRadius     = 16
+)
+
+// isLiquidBlock reports whether a block is water or lava.
+func isLiquidBlock(name string) bool {
+	return isLavaBlock(name) || strings.Contains(name, "water")
+}
+
+// isStandableBlock reports whether the player can safely stand on top of a block.
+func isStandableBlock(name string) bool {
+	return !isAirBlock(name) && !isLiquidBlock(name) && !isHazardBlock(name)
+}
+
+// SafePosition is the result of find_safe_position. Position is the block the player's
+// feet would occupy; TeleportTo is the centre of that block for the teleport tool.
+type SafePosition struct {
+	Found      bool               `json:"found"`
+	Position   *protocol.BlockPos `json:"position,omitempty"`
+	TeleportTo *[3]float64        `json:"teleport_to,omitempty"`
+	Distance   float64            `json:"distance,omitempty"` // blocks from the requested position
+	Radius     int                `json:"radius"`
+}
+
+// isSafeStandingPosition reports whether feet has known air at feet and head height
+// above a known standable block. Positions missing from the block cache are unsafe.
+func isSafeStandingPosition(blocks *blockNameView, feet protocol.BlockPos) bool {
+	for _, pos := range []protocol.BlockPos{feet, addBlockPos(feet, protocol.BlockPos{0, 1, 0})} {
+		if name, ok := blocks.at(pos); !ok || !isAirBlock(name) {
+			return false
+		}
+	}
+	name, ok := blocks.at(addBlockPos(feet, protocol.BlockPos{0, -1, 0}))
+	return ok && isStandableBlock(name)
+}
+
+// findSafePosition searches the block cache within radius blocks of target for the
+// safe standing position closest to it.
+func findSafePosition(state *GameState, target protocol.BlockPos, radius int) SafePosition {
+	result := SafePosition{Radius: radius}
+	blocks := state.BlockNames()
+	best := -1
+	r := int32(radius)
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			for dz := -r; dz <= r; dz++ {
+				d := int(dx*dx + dy*dy + dz*dz)
+				if best >= 0 && d >= best {
+					continue
+				}
+				feet := addBlockPos(target, protocol.BlockPos{dx, dy, dz})
+				if isSafeStandingPosition(blocks, feet) {
+					best = d
+					result.Position = &feet
+				}
+			}
+		}
+	}
+	if result.Position == nil {
+		return result
+	}
+	p := *result.Position
+	result.Found = true
+	result.TeleportTo = &[3]float64{float64(p[0]) + 0.5, float64(p[1]), float64(p[2]) + 0.5}
+	result.Distance = math.Sqrt(float64(best))
+	return result
+}
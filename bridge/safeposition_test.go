@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func TestFindSafePosition(t *testing.T) {
+	gs := newChunkTestState(t)
+	stone := blockState{Name: "minecraft:stone"}
+	lava := blockState{Name: "minecraft:lava", Properties: map[string]any{"liquid_depth": int32(0)}}
+
+	// Air over lava at the target, magma next to it, and stone further away.
+	sendTestChunk(gs, map[protocol.BlockPos]blockState{
+		{8, 65, 8}:  lava,
+		{9, 65, 8}:  {Name: "minecraft:magma"},
+		{11, 65, 8}: stone,
+		{8, 65, 13}: stone,
+	})
+
+	got := findSafePosition(gs, protocol.BlockPos{8, 66, 8}, 4)
+	if !got.Found || *got.Position != (protocol.BlockPos{11, 66, 8}) {
+		t.Fatalf("expected safe position (11,66,8), got %+v", got)
+	}
+	if *got.TeleportTo != [3]float64{11.5, 66, 8.5} || got.Distance != 3 {
+		t.Errorf("expected teleport to (11.5,66,8.5) at distance 3, got %v at %v", *got.TeleportTo, got.Distance)
+	}
+
+	if got := findSafePosition(gs, protocol.BlockPos{8, 66, 8}, 2); got.Found {
+		t.Errorf("expected nothing within radius 2, got %+v", got)
+	}
+}
+
+func TestIsStandableBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"minecraft:stone", true},
+		{"minecraft:grass_block", true},
+		{"minecraft:air", false},
+		{"minecraft:water", false},
+		{"minecraft:flowing_lava", false},
+		{"minecraft:magma", false},
+		{"minecraft:cactus", false},
+		{"minecraft:campfire", false},
+	}
+	for _, tt := range tests {
+		if got := isStandableBlock(tt.name); got != tt.want {
+			t.Errorf("isStandableBlock(%q): expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
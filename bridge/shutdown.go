@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Shutdown settings.
+const (
+	shutdownGracePeriod  = 10 * time.Second // time the active operation gets to finish
+	shutdownCancelWait   = 2 * time.Second  // time a cancelled operation gets to stop
+	shutdownPollInterval = 50 * time.Millisecond
+	shutdownMessage      = "Proxy shutting down"
+)
+
+// BeginShutdown marks the proxy as shutting down so that no new tool calls are accepted.
+func (gs *GameState) BeginShutdown() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.shuttingDown = true
+}
+
+// ShuttingDown reports whether shutdown has begun.
+func (gs *GameState) ShuttingDown() bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.shuttingDown
+}
+
+// shutdownMiddleware refuses tool calls once shutdown has begun.
+func shutdownMiddleware(state *GameState) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if state.ShuttingDown() {
+				return mcp.NewToolResultError("proxy is shutting down"), nil
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// waitForOperation waits up to timeout for the active operation to end. It reports
+// whether no operation is running any more.
+func waitForOperation(state *GameState, timeout time.Duration) bool {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		if _, ok := state.Operation(); !ok {
+			return true
+		}
+		select {
+		case <-deadline:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// gracefulShutdown stops accepting tool calls, gives the active operation up to grace
+// to finish before cancelling it, and tells the connected client the proxy is going
+// away. The caller then cancels the proxy context, which closes both connections.
+func gracefulShutdown(state *GameState, grace time.Duration) {
+	state.BeginShutdown()
+
+	if progress, ok := state.Operation(); ok {
+		slog.Info("shutdown: waiting for operation", "name", progress.Name, "current", progress.Current, "total", progress.Total)
+		if !waitForOperation(state, grace) {
+			progress, _ := state.CancelOperation()
+			slog.Warn("shutdown: cancelled operation", "name", progress.Name, "current", progress.Current, "total", progress.Total)
+			if !waitForOperation(state, shutdownCancelWait) {
+				slog.Warn("shutdown: operation did not stop", "name", progress.Name)
+			}
+		}
+	}
+
+	if conn := state.ClientConn(); conn != nil {
+		if err := conn.WritePacket(&packet.Disconnect{Message: shutdownMessage}); err == nil {
+			_ = conn.Flush()
+		}
+	}
+}
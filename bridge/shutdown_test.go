@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestShutdownMiddleware(t *testing.T) {
+	gs := NewGameState()
+	called := 0
+	handler := shutdownMiddleware(gs)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	if result, _ := handler(context.Background(), mcp.CallToolRequest{}); result.IsError {
+		t.Error("expected tool call to run before shutdown")
+	}
+	gs.BeginShutdown()
+	if result, _ := handler(context.Background(), mcp.CallToolRequest{}); !result.IsError {
+		t.Error("expected tool call to be refused after shutdown")
+	}
+	if called != 1 {
+		t.Errorf("expected handler to run once, got %d", called)
+	}
+}
+
+func TestGracefulShutdown_LetsOperationFinish(t *testing.T) {
+	gs := NewGameState()
+	id, ctx := gs.StartOperation(context.Background(), "place_blocks", 10)
+	interrupted := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			interrupted <- true
+		case <-time.After(100 * time.Millisecond):
+			interrupted <- false
+		}
+		gs.EndOperation(id)
+	}()
+
+	gracefulShutdown(gs, time.Second)
+
+	if <-interrupted {
+		t.Error("expected operation to finish without being cancelled")
+	}
+	if !gs.ShuttingDown() {
+		t.Error("expected shutdown to have begun")
+	}
+}
+
+func TestGracefulShutdown_CancelsSlowOperation(t *testing.T) {
+	gs := NewGameState()
+	id, ctx := gs.StartOperation(context.Background(), "upload_structure", 1000)
+	cancelled := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(cancelled)
+		gs.EndOperation(id)
+	}()
+
+	gracefulShutdown(gs, 50*time.Millisecond)
+
+	select {
+	case <-cancelled:
+	default:
+		t.Error("expected slow operation to be cancelled after the grace period")
+	}
+	if _, ok := gs.Operation(); ok {
+		t.Error("expected no operation running after shutdown")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Bounds on the variable-size parts of a snapshot.
+const (
+	maxSnapshotEntities = 100
+	maxSnapshotChat     = 20
+)
+
+// SnapshotPosition is the player's position and rotation in a snapshot.
+type SnapshotPosition struct {
+	X         float32 `json:"x"`
+	Y         float32 `json:"y"`
+	Z         float32 `json:"z"`
+	Pitch     float32 `json:"pitch"`
+	Yaw       float32 `json:"yaw"`
+	Dimension string  `json:"dimension"`
+}
+
+// SnapshotWorld is the cached world information in a snapshot.
+type SnapshotWorld struct {
+	Name     string            `json:"name"`
+	Time     int64             `json:"time"`
+	GameMode string            `json:"game_mode"`
+	Health   float32           `json:"health"`
+	SpawnPos protocol.BlockPos `json:"spawn_pos"`
+}
+
+// Snapshot is an internally consistent view of the whole game state.
+type Snapshot struct {
+	Status      string           `json:"status"`
+	PlayerName  string           `json:"player_name"`
+	XUID        string           `json:"xuid"`
+	EntityID    uint64           `json:"entity_id"`
+	Position    SnapshotPosition `json:"position"`
+	World       SnapshotWorld    `json:"world"`
+	Inventory   []InventorySlot  `json:"inventory"`
+	Players     []PlayerInfo     `json:"players"`
+	Entities    []EntityInfo     `json:"entities"`
+	EntityCount int              `json:"entity_count"` // total tracked, before truncation
+	Chat        []ChatMessage    `json:"chat"`
+}
+
+// Snapshot returns the game state assembled under a single read lock, so that no
+// field reflects an update the others have not seen. Entities are limited to the
+// maxSnapshotEntities nearest the player and chat to the last maxSnapshotChat messages.
+func (gs *GameState) Snapshot() Snapshot {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	entities := gs.entitiesLocked()
+	count := len(entities)
+	if count > maxSnapshotEntities {
+		player := mgl32.Vec3{gs.posX, gs.posY, gs.posZ}
+		entities = nearestEntities(entities, player, maxSnapshotEntities)
+	}
+
+	return Snapshot{
+		Status:     gs.status,
+		PlayerName: gs.displayName,
+		XUID:       gs.xuid,
+		EntityID:   gs.entityID,
+		Position: SnapshotPosition{
+			X: gs.posX, Y: gs.posY, Z: gs.posZ,
+			Pitch: gs.pitch, Yaw: gs.yaw,
+			Dimension: dimensionName(gs.dimension),
+		},
+		World: SnapshotWorld{
+			Name:     gs.worldName,
+			Time:     gs.worldTime,
+			GameMode: gameModeName(gs.gameMode),
+			Health:   gs.health,
+			SpawnPos: gs.spawnPos,
+		},
+		Inventory:   gs.inventoryLocked(),
+		Players:     gs.playersLocked(),
+		Entities:    entities,
+		EntityCount: count,
+		Chat:        gs.chatHistoryLocked(maxSnapshotChat),
+	}
+}
+
+// nearestEntities returns the n entities closest to pos, ordered by runtime ID.
+func nearestEntities(entities []EntityInfo, pos mgl32.Vec3, n int) []EntityInfo {
+	sorted := make([]EntityInfo, len(entities))
+	copy(sorted, entities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position.Sub(pos).LenSqr() < sorted[j].Position.Sub(pos).LenSqr()
+	})
+	sorted = sorted[:n]
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RuntimeID < sorted[j].RuntimeID })
+	return sorted
+}
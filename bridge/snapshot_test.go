@@ -0,0 +1,68 @@
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSnapshot_Bounds(t *testing.T) {
+	gs := NewGameState()
+	gs.UpdatePosition(0, 64, 0, 0, 0)
+	for i := 0; i < maxSnapshotEntities+10; i++ {
+		gs.AddEntity(uint64(i), "minecraft:cow", mgl32.Vec3{float32(i), 64, 0})
+	}
+	for i := 0; i < maxSnapshotChat+5; i++ {
+		gs.AppendChat(ChatMessage{Message: "hi"})
+	}
+
+	snap := gs.Snapshot()
+	if len(snap.Entities) != maxSnapshotEntities {
+		t.Errorf("expected %d entities, got %d", maxSnapshotEntities, len(snap.Entities))
+	}
+	if snap.EntityCount != maxSnapshotEntities+10 {
+		t.Errorf("expected entity count %d, got %d", maxSnapshotEntities+10, snap.EntityCount)
+	}
+	if last := snap.Entities[len(snap.Entities)-1].RuntimeID; last != maxSnapshotEntities-1 {
+		t.Errorf("expected nearest entities to be kept, last runtime ID %d", last)
+	}
+	if len(snap.Chat) != maxSnapshotChat {
+		t.Errorf("expected %d chat messages, got %d", maxSnapshotChat, len(snap.Chat))
+	}
+}
+
+func TestSnapshot_Concurrent(t *testing.T) {
+	gs := NewGameState()
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			// Position and entity are always updated together, so a consistent
+			// snapshot sees the entity at the player's x coordinate.
+			gs.mu.Lock()
+			gs.posX = float32(i)
+			gs.entities[1] = EntityInfo{RuntimeID: 1, Position: mgl32.Vec3{float32(i), 0, 0}}
+			gs.mu.Unlock()
+			gs.AppendChat(ChatMessage{Message: "tick"})
+			gs.AddPlayer("xuid", "Steve")
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		snap := gs.Snapshot()
+		if len(snap.Entities) == 1 && snap.Entities[0].Position.X() != snap.Position.X {
+			t.Fatalf("torn snapshot: player x %v, entity x %v", snap.Position.X, snap.Entities[0].Position.X())
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
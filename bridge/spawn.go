@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Spawn retry settings. Realms still warming up after a cold start can take longer
+// than one attempt to finish spawning the player.
+const (
+	spawnAttempts       = 3
+	spawnAttemptTimeout = 30 * time.Second
+)
+
+// spawner is the part of *minecraft.Conn used to spawn into the server.
+type spawner interface {
+	DoSpawnContext(ctx context.Context) error
+	Close() error
+}
+
+// spawnWithRetry waits for the server to spawn the player on conn, giving up after
+// attempts tries of timeout each. A connection whose spawn timed out cannot be used
+// again, so each retry closes it and dials a new one with redial. It returns the
+// connection that spawned; on failure, the last connection is closed.
+func spawnWithRetry[C spawner](ctx context.Context, conn C, redial func(context.Context) (C, error), attempts int, timeout time.Duration) (C, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = conn.DoSpawnContext(attemptCtx)
+		cancel()
+		if err == nil {
+			return conn, nil
+		}
+		conn.Close()
+		if ctx.Err() != nil {
+			return conn, err
+		}
+		if attempt >= attempts {
+			return conn, fmt.Errorf("spawn failed after %d attempts: %w", attempts, err)
+		}
+		slog.Warn("spawn not complete, reconnecting", "attempt", attempt, "max", attempts, "error", err)
+		next, dialErr := redial(ctx)
+		if dialErr != nil {
+			return conn, fmt.Errorf("reconnecting after spawn attempt %d: %w", attempt, dialErr)
+		}
+		conn = next
+	}
+}
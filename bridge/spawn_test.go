@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeSpawner is a connection that spawns unless fail is set.
+type fakeSpawner struct {
+	fail   bool
+	calls  int
+	closed bool
+}
+
+func (f *fakeSpawner) DoSpawnContext(ctx context.Context) error {
+	f.calls++
+	if f.fail {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (f *fakeSpawner) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestSpawnWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int // connections that fail to spawn
+		wantDials int
+		wantErr   bool
+	}{
+		{"first attempt", 0, 0, false},
+		{"after reconnecting", 2, 2, false},
+		{"gives up", 5, 2, true},
+	}
+	for _, tt := range tests {
+		conns := []*fakeSpawner{{fail: tt.failures > 0}}
+		redial := func(context.Context) (*fakeSpawner, error) {
+			c := &fakeSpawner{fail: len(conns) < tt.failures}
+			conns = append(conns, c)
+			return c, nil
+		}
+		got, err := spawnWithRetry(context.Background(), conns[0], redial, 3, time.Millisecond)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if dials := len(conns) - 1; dials != tt.wantDials {
+			t.Errorf("%s: expected %d new connections, got %d", tt.name, tt.wantDials, dials)
+		}
+		for i, c := range conns {
+			if c.calls != 1 {
+				t.Errorf("%s: expected connection %d to be spawned on once, got %d", tt.name, i, c.calls)
+			}
+			if last := i == len(conns)-1; c.closed != (!last || tt.wantErr) {
+				t.Errorf("%s: connection %d closed=%v", tt.name, i, c.closed)
+			}
+		}
+		if !tt.wantErr && got != conns[len(conns)-1] {
+			t.Errorf("%s: expected the last connection to be returned", tt.name)
+		}
+	}
+}
+
+func TestSpawnWithRetry_DialFails(t *testing.T) {
+	first := &fakeSpawner{fail: true}
+	dialErr := errors.New("realm offline")
+	_, err := spawnWithRetry(context.Background(), first, func(context.Context) (*fakeSpawner, error) {
+		return nil, dialErr
+	}, 3, time.Millisecond)
+	if !errors.Is(err, dialErr) || !first.closed {
+		t.Errorf("expected the dial error after closing the first connection, got %v (closed=%v)", err, first.closed)
+	}
+}
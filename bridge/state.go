@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
 const (
@@ -15,10 +23,12 @@ const (
 	StatusWaitingForClient = "waiting_for_client"
 	StatusConnectingToRealm = "connecting_to_realm"
 	StatusConnected        = "connected"
+	StatusReconnecting     = "reconnecting_to_realm"
 	StatusDisconnected     = "disconnected"
 )
 
-const maxChatHistory = 100
+// DefaultChatHistory is the default number of chat messages kept.
+const DefaultChatHistory = 100
 
 // ChatMessage represents a single chat message with metadata.
 type ChatMessage struct {
@@ -28,10 +38,48 @@ type ChatMessage struct {
 	Type      string    `json:"type"` // "incoming" or "outgoing"
 }
 
+// ProtocolVersions describes the protocol and game versions seen during the handshake.
+type ProtocolVersions struct {
+	ClientProtocol       int32  `json:"client_protocol"`
+	ClientVersion        string `json:"client_version"`
+	ProxyProtocol        int32  `json:"proxy_protocol"`
+	ProxyVersion         string `json:"proxy_version"`
+	RealmBaseGameVersion string `json:"realm_base_game_version"`
+	Mismatch             bool   `json:"mismatch"`
+}
+
+// SessionInfo holds StartGame-derived metadata describing the capabilities of a session.
+type SessionInfo struct {
+	BaseGameVersion              string   `json:"base_game_version"`
+	ServerAuthoritativeInventory bool     `json:"server_authoritative_inventory"`
+	EntityRuntimeID              uint64   `json:"entity_runtime_id"`
+	EntityUniqueID               int64    `json:"entity_unique_id"`
+	InitialGameMode              string   `json:"initial_game_mode"`
+	WorldGameMode                string   `json:"world_game_mode"`
+	Difficulty                   int32    `json:"difficulty"`
+	Hardcore                     bool     `json:"hardcore"`
+	ChunkRadius                  int32    `json:"chunk_radius"`
+	Experiments                  []string `json:"experiments"`
+}
+
+// WorldGeneration holds the world generation settings from StartGame. gophertunnel's
+// GameData does not expose the generator type, so it is not reported.
+type WorldGeneration struct {
+	Seed                 int64             `json:"seed"`
+	SeedKnown            bool              `json:"seed_known"` // servers may hide the seed by sending 0
+	ClientSideGeneration bool              `json:"client_side_generation"`
+	SpawnPos             protocol.BlockPos `json:"spawn_pos"`
+	SpawnRadius          *uint32           `json:"spawn_radius,omitempty"` // from the spawnradius game rule
+}
+
 // PlayerInfo represents an online player.
 type PlayerInfo struct {
-	Username string `json:"username"`
-	XUID     string `json:"xuid"`
+	Username    string `json:"username"`
+	XUID        string `json:"xuid"`
+	RuntimeID   uint64 `json:"runtime_id,omitempty"` // once the player's entity has been seen
+	Platform    string `json:"platform,omitempty"`
+	SkinID      string `json:"skin_id,omitempty"`
+	PersonaSkin bool   `json:"persona_skin,omitempty"`
 }
 
 // EntityInfo represents a tracked nearby entity.
@@ -39,6 +87,9 @@ type EntityInfo struct {
 	RuntimeID uint64    `json:"runtime_id"`
 	Type      string    `json:"type"` // entity identifier or player name
 	Position  mgl32.Vec3 `json:"position"`
+	Velocity  mgl32.Vec3 `json:"velocity"` // blocks per tick, from SetActorMotion
+	Health    *float32   `json:"health,omitempty"` // nil until the server reports it
+	Player    *PlayerIdentity `json:"player,omitempty"` // set for player entities
 }
 
 // InventorySlot represents a single inventory slot.
@@ -59,29 +110,49 @@ type GameState struct {
 	clientConn *minecraft.Conn
 
 	// Player identity (set on connect)
-	displayName string
-	xuid        string
-	entityID    uint64 // our entity runtime ID
+	displayName  string
+	nameOverride string // -display-name, replaces displayName in outgoing chat
+	xuid         string
+	entityID     uint64 // our entity runtime ID
 
 	// Position and rotation
 	posX, posY, posZ float32
 	pitch, yaw       float32
 	dimension        int32
+	serverMoveSeq    uint64 // MovePlayer packets received for our entity
 
 	// Inventory: map of window ID -> slots
 	inventory map[byte][]protocol.ItemInstance
+	heldSlot  int // selected hotbar slot
 
 	// Chat history (ring buffer)
 	chatHistory []ChatMessage
+	chatSeq     uint64 // total messages ever appended
+	chatCap     int    // messages kept
 
 	// Online players
-	players map[string]PlayerInfo // keyed by XUID
+	players     map[string]PlayerInfo // keyed by XUID
+	playerXUIDs map[uuid.UUID]string  // XUID by PlayerList UUID
+
+	// Player identities from PlayerList, AddPlayer and PlayerSkin, by UUID
+	playerIdentities map[uuid.UUID]PlayerIdentity
 
 	// World info
 	worldName string
 	worldTime int64
 	gameMode  int32
 	spawnPos  protocol.BlockPos
+	worldSeed int64
+
+	clientSideGeneration bool
+
+	// Session metadata from StartGame
+	session SessionInfo
+
+	// Game rules from StartGame (name -> bool, uint32 or float32 value), and when each
+	// last arrived in GameRulesChanged
+	gameRules        map[string]any
+	gameRulesChanged map[string]time.Time
 
 	// Player attributes
 	health     float32
@@ -93,11 +164,152 @@ type GameState struct {
 	// Item registry from StartGame (for resolving network IDs to names)
 	itemRegistry map[int32]string // network ID -> item name
 
-	// Verbose packet logging toggle
-	verbosePacketLog bool
+	// Verbose packet logging toggle and filter (empty filter = all building packets).
+	// The toggle is atomic so that per-packet checks skip gs.mu while it is off.
+	verbosePacketLog atomic.Bool
+	packetLogTypes   map[string]bool
+	packetLogDir     string
+
+	// Anti-idle action, performed when no tool has been called for antiIdleInterval
+	antiIdle         bool
+	antiIdleInterval time.Duration
+	lastActivity     time.Time
+
+	// PlayerAuthInput throttling once no tool has been called for idleThrottleAfter
+	// (0 = never); lastToolCall is not reset by anti-idle actions
+	idleThrottleAfter time.Duration
+	lastToolCall      time.Time
+
+	// Progress of the active long-running operation (nil when none), and the cancel
+	// funcs of every running operation by ID
+	operation        *OperationProgress
+	operationCancels map[uint64]context.CancelFunc
+	nextOperationID  uint64
+
+	// Player abilities from UpdateAbilities
+	abilities      protocol.AbilityData
+	abilitiesKnown bool
+
+	// Flight and other movement abilities, also kept current by AdventureSettings
+	// and the client starting or stopping flight
+	movementAbilities Abilities
+
+	// This proxy instance and the start of the current Realm session
+	proxyInfo        ProxyInfo
+	sessionStartedAt time.Time
+
+	// Realm to connect sessions to, the check switch_realm runs on a new target, the
+	// function ending the current session, and the last session failure
+	realmTarget   realmTarget
+	realmCheck    func(context.Context, realmTarget) error
+	sessionCancel context.CancelFunc
+	sessionErr    error
+	sessionErrAt  time.Time
+
+	// Transfer packets: whether the proxy follows them and the last one seen
+	followTransfers bool
+	lastTransfer    *TransferInfo
+
+	// Attempts to reconnect to the Realm when it drops while the client stays
+	reconnectAttempts int
+
+	// How long to keep polling a Realm that is still starting
+	realmWait time.Duration
+
+	// Recent PacketViolationWarnings, oldest first
+	violations []Violation
+
+	// Timeline of notable session events, oldest first
+	events   []Event
+	eventSeq uint64
+
+	// Set once shutdown has begun; new tool calls are refused
+	shuttingDown bool
+
+	// Container window opened by ContainerOpen (nil when none)
+	container *OpenContainer
+
+	// Form from ModalFormRequest awaiting an answer (nil when none)
+	pendingForm *PendingForm
+
+	// Commands from AvailableCommands, sorted by name
+	availableCommands []CommandInfo
 
-	// Block registry: block runtime ID -> name, learned from observation
+	// Full command tree from AvailableCommands and UpdateSoftEnum, for export
+	commandTree CommandTree
+
+	// Last intercepted packet per type, captured while verbose logging is on
+	rawPackets map[string]RawPacket
+
+	// Block registry: block network ID -> name, built at StartGame from the block
+	// state table and extended by observation
 	blockRegistry map[uint32]string
+	blockStates   []blockState
+	airRuntimeID  uint32
+	airKnown      bool
+
+	// Spatial caches of blocks seen from the server, one per dimension
+	blockCaches    map[int32]*BlockCache
+	blockCacheSize int
+
+	// Block entity NBT from BlockActorData, per dimension
+	blockEntities map[int32]map[protocol.BlockPos]map[string]any
+
+	// Chunk parsing (decode LevelChunk/SubChunk into the block cache)
+	parseChunks bool
+	chunkRadius int
+
+	// Sub-chunks requested by load_area awaiting a SubChunk response, and when each
+	// SubChunkRequest the bridge sent itself went out
+	subChunkLoad        *subChunkLoad
+	ownSubChunkRequests map[subChunkRequestKey]time.Time
+
+	// Biome storages decoded from LevelChunk, per dimension and chunk column
+	biomes map[int32]map[protocol.ChunkPos][]*palettedStorage
+
+	// Versions negotiated in the current session, and whether to refuse mismatched clients
+	versions       ProtocolVersions
+	strictProtocol bool
+
+	// Relay validation mode and per-session packet counters
+	invalidPacketMode string
+	relayStats        RelayStats
+
+	// Resource pack negotiation with the Realm
+	resourcePackMode string
+	resourcePacks    []ResourcePackInfo
+
+	// Player spawn point (bed / respawn anchor) from SetSpawnPosition
+	playerSpawn    protocol.BlockPos
+	playerSpawnDim int32
+	hasPlayerSpawn bool
+
+	// Named waypoints, persisted to waypointsFile when set
+	waypoints     map[string]Waypoint
+	waypointsFile string
+
+	// Named build presets, persisted to buildPresetsFile when set
+	buildPresets     map[string]BuildPreset
+	buildPresetsFile string
+
+	// Creative items (name -> creative item network ID) and crafting recipes, from
+	// CreativeContent and CraftingData
+	creativeItems map[string]uint32
+	recipes       map[uint32]RecipeInfo
+
+	// Pending ItemStackRequest packets awaiting ItemStackResponse, by request ID
+	stackWaiters       map[int32]chan protocol.ItemStackResponse
+	nextStackRequestID int32
+
+	// Pending CommandRequest packets awaiting CommandOutput, by command origin UUID
+	commandWaiters map[uuid.UUID]chan *packet.CommandOutput
+
+	// Pacer of the running place_blocks, told about UpdateBlock confirmations
+	placementPacer *placementPacer
+
+	// One-shot wait_for watchers, by watcher ID
+	watchers      map[uint64]*watcher
+	nextWatcherID uint64
 }
 
 // NewGameState creates a new GameState with initial status.
@@ -106,10 +318,37 @@ func NewGameState() *GameState {
 		status:        StatusStarting,
 		inventory:     make(map[byte][]protocol.ItemInstance),
 		players:       make(map[string]PlayerInfo),
+		chatCap:       DefaultChatHistory,
+		playerXUIDs:   make(map[uuid.UUID]string),
+		playerIdentities: make(map[uuid.UUID]PlayerIdentity),
 		attributes:    make(map[string]float32),
+		gameRules:     make(map[string]any),
+		gameRulesChanged: make(map[string]time.Time),
 		entities:      make(map[uint64]EntityInfo),
 		itemRegistry:  make(map[int32]string),
 		blockRegistry: make(map[uint32]string),
+		blockCaches:    make(map[int32]*BlockCache),
+		blockEntities:  make(map[int32]map[protocol.BlockPos]map[string]any),
+		biomes:         make(map[int32]map[protocol.ChunkPos][]*palettedStorage),
+		rawPackets:     make(map[string]RawPacket),
+		blockCacheSize: DefaultBlockCacheSize,
+		chunkRadius:    DefaultChunkRadius,
+		commandWaiters: make(map[uuid.UUID]chan *packet.CommandOutput),
+		watchers:       make(map[uint64]*watcher),
+
+		invalidPacketMode: InvalidPacketsOff,
+		resourcePackMode:  ResourcePacksDownload,
+		antiIdleInterval:  DefaultAntiIdleInterval,
+		lastActivity:      time.Now(),
+		lastToolCall:      time.Now(),
+		reconnectAttempts: DefaultReconnectAttempts,
+
+		waypoints:          make(map[string]Waypoint),
+		buildPresets:       make(map[string]BuildPreset),
+		creativeItems:      make(map[string]uint32),
+		recipes:            make(map[uint32]RecipeInfo),
+		stackWaiters:       make(map[int32]chan protocol.ItemStackResponse),
+		nextStackRequestID: firstBridgeStackRequestID,
 	}
 }
 
@@ -133,6 +372,7 @@ func (gs *GameState) SetConnections(server, client *minecraft.Conn) {
 	defer gs.mu.Unlock()
 	gs.serverConn = server
 	gs.clientConn = client
+	gs.relayStats = RelayStats{}
 }
 
 // ClearConnections removes stored connections.
@@ -141,6 +381,7 @@ func (gs *GameState) ClearConnections() {
 	defer gs.mu.Unlock()
 	gs.serverConn = nil
 	gs.clientConn = nil
+	gs.sessionCancel = nil
 }
 
 // ServerConn returns the server connection (nil if not connected).
@@ -150,6 +391,36 @@ func (gs *GameState) ServerConn() *minecraft.Conn {
 	return gs.serverConn
 }
 
+// ClientConn returns the client connection (nil if not connected).
+func (gs *GameState) ClientConn() *minecraft.Conn {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.clientConn
+}
+
+// maxDisplayNameLength is the longest player name Bedrock accepts.
+const maxDisplayNameLength = 16
+
+// validateDisplayName checks a display name override against Bedrock's name rules.
+func validateDisplayName(name string) error {
+	n := utf8.RuneCountInString(name)
+	if n == 0 || n > maxDisplayNameLength {
+		return fmt.Errorf("display name must be 1-%d characters, got %d", maxDisplayNameLength, n)
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("display name must not start or end with spaces")
+	}
+	return nil
+}
+
+// SetDisplayNameOverride sets a display name to use instead of the account's name in
+// outgoing chat. An empty name uses the account's name.
+func (gs *GameState) SetDisplayNameOverride(name string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.nameOverride = name
+}
+
 // SetIdentity stores the player's identity info.
 func (gs *GameState) SetIdentity(displayName, xuid string, entityID uint64) {
 	gs.mu.Lock()
@@ -166,6 +437,17 @@ func (gs *GameState) Identity() (string, string) {
 	return gs.displayName, gs.xuid
 }
 
+// ChatIdentity returns the source name and XUID to send chat as: the display name
+// override if one is set, otherwise the player's display name.
+func (gs *GameState) ChatIdentity() (string, string) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.nameOverride != "" {
+		return gs.nameOverride, gs.xuid
+	}
+	return gs.displayName, gs.xuid
+}
+
 // EntityID returns our entity runtime ID.
 func (gs *GameState) EntityID() uint64 {
 	gs.mu.RLock()
@@ -182,6 +464,7 @@ func (gs *GameState) UpdatePosition(x, y, z, pitch, yaw float32) {
 	gs.posZ = z
 	gs.pitch = pitch
 	gs.yaw = yaw
+	gs.blockCacheLocked(gs.dimension).SetCenter(protocol.BlockPos{int32(x), int32(y), int32(z)})
 }
 
 // Position returns the current player position and rotation.
@@ -191,10 +474,22 @@ func (gs *GameState) Position() (x, y, z, pitch, yaw float32, dimension int32) {
 	return gs.posX, gs.posY, gs.posZ, gs.pitch, gs.yaw, gs.dimension
 }
 
-// SetDimension updates the current dimension.
+// SetDimension updates the current dimension. Entity runtime IDs are only meaningful
+// in the dimension they were announced in, so tracked entities are dropped when the
+// dimension changes; the server re-announces the entities around the player on arrival.
+// Block caches are kept per dimension and become active again on return.
 func (gs *GameState) SetDimension(dim int32) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
+	gs.setDimensionLocked(dim)
+}
+
+// setDimensionLocked switches the current dimension, dropping tracked entities if it
+// changes. Callers must hold gs.mu.
+func (gs *GameState) setDimensionLocked(dim int32) {
+	if dim != gs.dimension {
+		gs.entities = make(map[uint64]EntityInfo)
+	}
 	gs.dimension = dim
 }
 
@@ -203,6 +498,9 @@ func (gs *GameState) SetInventory(windowID byte, items []protocol.ItemInstance)
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 	gs.inventory[windowID] = items
+	if gs.container != nil && gs.container.WindowID == windowID {
+		gs.container.Ready = true
+	}
 }
 
 // UpdateInventorySlot updates a single inventory slot.
@@ -222,7 +520,11 @@ func (gs *GameState) UpdateInventorySlot(windowID byte, slot int, item protocol.
 func (gs *GameState) Inventory() []InventorySlot {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
+	return gs.inventoryLocked()
+}
 
+// inventoryLocked is Inventory without locking. Callers must hold gs.mu.
+func (gs *GameState) inventoryLocked() []InventorySlot {
 	var result []InventorySlot
 	for _, items := range gs.inventory {
 		for i, item := range items {
@@ -240,6 +542,117 @@ func (gs *GameState) Inventory() []InventorySlot {
 	return result
 }
 
+// InventoryItem returns the raw item tracked in a window slot. ok is false if the slot
+// is empty or untracked.
+func (gs *GameState) InventoryItem(windowID byte, slot int) (item protocol.ItemInstance, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	items := gs.inventory[windowID]
+	if slot < 0 || slot >= len(items) || items[slot].Stack.Count == 0 {
+		return protocol.ItemInstance{}, false
+	}
+	return items[slot], true
+}
+
+// setSlotLocked stores an item in a window slot, growing the window as needed.
+// gs.mu must be held for writing.
+func (gs *GameState) setSlotLocked(windowID byte, slot int, item protocol.ItemInstance) {
+	for len(gs.inventory[windowID]) <= slot {
+		gs.inventory[windowID] = append(gs.inventory[windowID], protocol.ItemInstance{})
+	}
+	gs.inventory[windowID][slot] = item
+}
+
+// ApplyItemMove mirrors an accepted move (or swap) between two window slots in the
+// tracked inventory, since the server does not resend slots changed by the bridge's
+// own item stack requests.
+func (gs *GameState) ApplyItemMove(srcWindow byte, srcSlot int, dstWindow byte, dstSlot int, count int, swap bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	var src, dst protocol.ItemInstance
+	if items := gs.inventory[srcWindow]; srcSlot < len(items) {
+		src = items[srcSlot]
+	}
+	if items := gs.inventory[dstWindow]; dstSlot < len(items) {
+		dst = items[dstSlot]
+	}
+	if swap {
+		gs.setSlotLocked(srcWindow, srcSlot, dst)
+		gs.setSlotLocked(dstWindow, dstSlot, src)
+		return
+	}
+	moved := src
+	moved.Stack.Count = uint16(count)
+	if dst.Stack.Count > 0 {
+		moved.Stack.Count += dst.Stack.Count
+		moved.StackNetworkID = dst.StackNetworkID
+	}
+	gs.setSlotLocked(dstWindow, dstSlot, moved)
+	src.Stack.Count -= uint16(count)
+	if src.Stack.Count == 0 {
+		src = protocol.ItemInstance{}
+	}
+	gs.setSlotLocked(srcWindow, srcSlot, src)
+}
+
+// ApplyItemStackResponse updates tracked slot counts and stack network IDs from an
+// accepted item stack response.
+func (gs *GameState) ApplyItemStackResponse(resp protocol.ItemStackResponse) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	for _, c := range resp.ContainerInfo {
+		w, ok := windowForContainer(c.Container.ContainerID)
+		if !ok {
+			continue
+		}
+		for _, si := range c.SlotInfo {
+			slot := int(si.Slot)
+			if slot >= len(gs.inventory[w.WindowID]) {
+				continue
+			}
+			if si.Count == 0 {
+				gs.inventory[w.WindowID][slot] = protocol.ItemInstance{}
+				continue
+			}
+			item := &gs.inventory[w.WindowID][slot]
+			item.Stack.Count = uint16(si.Count)
+			item.StackNetworkID = si.StackNetworkID
+		}
+	}
+}
+
+// FirstEmptySlot returns the first empty slot of a window in the given range.
+func (gs *GameState) FirstEmptySlot(windowID byte, first, last int) (int, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	items := gs.inventory[windowID]
+	for i := first; i <= last; i++ {
+		if i >= len(items) || items[i].Stack.Count == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// WindowSlots returns every slot of a window in the given range, including empty ones
+// (with an empty item name and zero count), so callers can see free slot indices.
+func (gs *GameState) WindowSlots(windowID byte, first, last int) []InventorySlot {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	items := gs.inventory[windowID]
+	result := make([]InventorySlot, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		slot := InventorySlot{Slot: i}
+		if i < len(items) && items[i].Stack.Count > 0 {
+			slot.Item = gs.resolveItemName(items[i].Stack.NetworkID)
+			slot.Count = int(items[i].Stack.Count)
+		}
+		result = append(result, slot)
+	}
+	return result
+}
+
 // resolveItemName converts a network ID to an item name using the registry.
 // Must be called with at least a read lock held.
 func (gs *GameState) resolveItemName(networkID int32) string {
@@ -254,15 +667,43 @@ func (gs *GameState) AppendChat(msg ChatMessage) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 	gs.chatHistory = append(gs.chatHistory, msg)
-	if len(gs.chatHistory) > maxChatHistory {
-		gs.chatHistory = gs.chatHistory[len(gs.chatHistory)-maxChatHistory:]
+	gs.chatSeq++
+	gs.trimChatLocked()
+}
+
+// trimChatLocked drops the oldest messages beyond the history size. Callers must
+// hold gs.mu.
+func (gs *GameState) trimChatLocked() {
+	if len(gs.chatHistory) > gs.chatCap {
+		gs.chatHistory = gs.chatHistory[len(gs.chatHistory)-gs.chatCap:]
 	}
 }
 
-// ChatHistory returns the last n chat messages (up to maxChatHistory).
+// SetChatHistorySize sets how many chat messages are kept, dropping the oldest if
+// more are held. Sizes below 1 are treated as 1.
+func (gs *GameState) SetChatHistorySize(n int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.chatCap = max(n, 1)
+	gs.trimChatLocked()
+}
+
+// ChatHistorySize returns how many chat messages are kept.
+func (gs *GameState) ChatHistorySize() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.chatCap
+}
+
+// ChatHistory returns the last n chat messages (up to the chat history size).
 func (gs *GameState) ChatHistory(n int) []ChatMessage {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
+	return gs.chatHistoryLocked(n)
+}
+
+// chatHistoryLocked is ChatHistory without locking. Callers must hold gs.mu.
+func (gs *GameState) chatHistoryLocked(n int) []ChatMessage {
 	if n <= 0 || n > len(gs.chatHistory) {
 		n = len(gs.chatHistory)
 	}
@@ -272,6 +713,30 @@ func (gs *GameState) ChatHistory(n int) []ChatMessage {
 	return result
 }
 
+// ChatSeq returns the number of chat messages appended so far. Pass it to ChatSince
+// to collect the messages that arrive afterwards.
+func (gs *GameState) ChatSeq() uint64 {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.chatSeq
+}
+
+// ChatSince returns the messages appended after seq that are still in the history buffer.
+func (gs *GameState) ChatSince(seq uint64) []ChatMessage {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	n := gs.chatSeq - seq
+	if seq > gs.chatSeq {
+		n = 0
+	}
+	if n > uint64(len(gs.chatHistory)) {
+		n = uint64(len(gs.chatHistory))
+	}
+	result := make([]ChatMessage, n)
+	copy(result, gs.chatHistory[len(gs.chatHistory)-int(n):])
+	return result
+}
+
 // AddPlayer adds a player to the online player list.
 func (gs *GameState) AddPlayer(xuid, username string) {
 	gs.mu.Lock()
@@ -286,12 +751,49 @@ func (gs *GameState) RemovePlayer(xuid string) {
 	delete(gs.players, xuid)
 }
 
+// AddPlayerEntry adds a player from a PlayerList entry, remembering its UUID since
+// removal entries carry only the UUID. It reports whether the player was not already
+// in the list.
+func (gs *GameState) AddPlayerEntry(id uuid.UUID, xuid, username string) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	_, known := gs.players[xuid]
+	gs.players[xuid] = PlayerInfo{Username: username, XUID: xuid}
+	if id != uuid.Nil {
+		gs.playerXUIDs[id] = xuid
+	}
+	return !known
+}
+
+// RemovePlayerEntry removes the player of a PlayerList removal entry, found by UUID
+// or, failing that, by XUID. It returns the removed player.
+func (gs *GameState) RemovePlayerEntry(id uuid.UUID, xuid string) (PlayerInfo, bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if x, ok := gs.playerXUIDs[id]; ok {
+		xuid = x
+		delete(gs.playerXUIDs, id)
+	}
+	delete(gs.playerIdentities, id)
+	p, ok := gs.players[xuid]
+	delete(gs.players, xuid)
+	return p, ok
+}
+
 // Players returns the list of online players.
 func (gs *GameState) Players() []PlayerInfo {
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
+	return gs.playersLocked()
+}
+
+// playersLocked is Players without locking. Callers must hold gs.mu.
+func (gs *GameState) playersLocked() []PlayerInfo {
 	result := make([]PlayerInfo, 0, len(gs.players))
 	for _, p := range gs.players {
+		if id, ok := gs.playerIdentityByXUIDLocked(p.XUID); ok {
+			p.RuntimeID, p.Platform, p.SkinID, p.PersonaSkin = id.RuntimeID, id.Platform, id.SkinID, id.PersonaSkin
+		}
 		result = append(result, p)
 	}
 	return result
@@ -308,7 +810,7 @@ func (gs *GameState) SetWorldTime(t int64) {
 func (gs *GameState) SetHealth(h float32) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.health = h
+	gs.setHealthLocked(h)
 	gs.attributes["health"] = h
 }
 
@@ -318,10 +820,36 @@ func (gs *GameState) SetAttribute(name string, value float32) {
 	defer gs.mu.Unlock()
 	gs.attributes[name] = value
 	if name == "minecraft:health" {
-		gs.health = value
+		gs.setHealthLocked(value)
 	}
 }
 
+// Hunger attribute names sent in UpdateAttributes.
+const (
+	attrHunger     = "minecraft:player.hunger"
+	attrSaturation = "minecraft:player.saturation"
+	attrExhaustion = "minecraft:player.exhaustion"
+)
+
+// HungerInfo is the player's food state. Food ranges from 0 to 20; sprinting needs
+// more than 6 and natural regeneration needs 18 or more.
+type HungerInfo struct {
+	Food       float32 `json:"food"`
+	Saturation float32 `json:"saturation"`
+	Exhaustion float32 `json:"exhaustion"`
+}
+
+// Hunger returns the player's food state. ok is false until the server has sent
+// the hunger attribute.
+func (gs *GameState) Hunger() (info HungerInfo, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	info.Food, ok = gs.attributes[attrHunger]
+	info.Saturation = gs.attributes[attrSaturation]
+	info.Exhaustion = gs.attributes[attrExhaustion]
+	return info, ok
+}
+
 // InitFromGameData populates world info from the StartGame GameData.
 func (gs *GameState) InitFromGameData(gd minecraft.GameData) {
 	gs.mu.Lock()
@@ -329,8 +857,10 @@ func (gs *GameState) InitFromGameData(gd minecraft.GameData) {
 	gs.worldName = gd.WorldName
 	gs.gameMode = gd.PlayerGameMode
 	gs.worldTime = gd.Time
-	gs.dimension = gd.Dimension
+	gs.setDimensionLocked(gd.Dimension)
 	gs.spawnPos = gd.WorldSpawn
+	gs.worldSeed = gd.WorldSeed
+	gs.clientSideGeneration = gd.ClientSideGeneration
 	gs.posX = gd.PlayerPosition.X()
 	gs.posY = gd.PlayerPosition.Y()
 	gs.posZ = gd.PlayerPosition.Z()
@@ -343,7 +873,53 @@ func (gs *GameState) InitFromGameData(gd minecraft.GameData) {
 	for _, item := range gd.Items {
 		gs.itemRegistry[int32(item.RuntimeID)] = item.Name
 	}
+	gs.initBlockRegistryLocked(gd.CustomBlocks, gd.UseBlockNetworkIDHashes)
+
+	gs.session = SessionInfo{
+		BaseGameVersion:              gd.BaseGameVersion,
+		ServerAuthoritativeInventory: gd.ServerAuthoritativeInventory,
+		EntityRuntimeID:              gd.EntityRuntimeID,
+		EntityUniqueID:               gd.EntityUniqueID,
+		InitialGameMode:              gameModeName(gd.PlayerGameMode),
+		WorldGameMode:                gameModeName(gd.WorldGameMode),
+		Difficulty:                   gd.Difficulty,
+		Hardcore:                     gd.Hardcore,
+		ChunkRadius:                  gd.ChunkRadius,
+		Experiments:                  []string{},
+	}
+	for _, e := range gd.Experiments {
+		if e.Enabled {
+			gs.session.Experiments = append(gs.session.Experiments, e.Name)
+		}
+	}
 
+	// Game rule values are kept as sent (bool, uint32 or float32).
+	for _, rule := range gd.GameRules {
+		gs.gameRules[rule.Name] = rule.Value
+	}
+}
+
+// SessionInfo returns the StartGame-derived metadata of the current session.
+func (gs *GameState) SessionInfo() SessionInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.session
+}
+
+// WorldGeneration returns the world generation settings from StartGame.
+func (gs *GameState) WorldGeneration() WorldGeneration {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	gen := WorldGeneration{
+		Seed:                 gs.worldSeed,
+		SeedKnown:            gs.worldSeed != 0,
+		ClientSideGeneration: gs.clientSideGeneration,
+		SpawnPos:             gs.spawnPos,
+	}
+	if r, ok := gs.gameRules["spawnradius"].(uint32); ok {
+		gen.SpawnRadius = &r
+	}
+	return gen
 }
 
 // WorldInfo returns the cached world information.
@@ -353,6 +929,36 @@ func (gs *GameState) WorldInfo() (worldName string, worldTime int64, gameMode in
 	return gs.worldName, gs.worldTime, gs.gameMode, gs.health, gs.spawnPos
 }
 
+// GameRules returns a copy of the cached game rules.
+func (gs *GameState) GameRules() map[string]any {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	result := make(map[string]any, len(gs.gameRules))
+	for name, value := range gs.gameRules {
+		result[name] = value
+	}
+	return result
+}
+
+// UpdateGameRules applies changed game rules to the cache.
+func (gs *GameState) UpdateGameRules(rules []protocol.GameRule) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	now := time.Now()
+	for _, rule := range rules {
+		gs.gameRules[rule.Name] = rule.Value
+		gs.gameRulesChanged[rule.Name] = now
+	}
+}
+
+// GameRuleChangedSince reports whether GameRulesChanged set the named rule to value
+// after since. A value cached from StartGame does not count.
+func (gs *GameState) GameRuleChangedSince(name string, value any, since time.Time) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.gameRulesChanged[name].After(since) && gs.gameRules[name] == value
+}
+
 // AddEntity adds or updates a tracked entity.
 func (gs *GameState) AddEntity(runtimeID uint64, entityType string, pos mgl32.Vec3) {
 	gs.mu.Lock()
@@ -381,18 +987,99 @@ func (gs *GameState) UpdateEntityPosition(runtimeID uint64, pos mgl32.Vec3) {
 	}
 }
 
-// SetVerbosePacketLog enables or disables verbose packet logging.
-func (gs *GameState) SetVerbosePacketLog(enabled bool) {
+// UpdateEntityVelocity updates an entity's velocity. Motion for untracked entities is ignored.
+func (gs *GameState) UpdateEntityVelocity(runtimeID uint64, vel mgl32.Vec3) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
-	gs.verbosePacketLog = enabled
+	if e, ok := gs.entities[runtimeID]; ok {
+		e.Velocity = vel
+		gs.entities[runtimeID] = e
+	}
+}
+
+// Entities returns a snapshot of all tracked entities, ordered by runtime ID.
+func (gs *GameState) Entities() []EntityInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.entitiesLocked()
+}
+
+// entitiesLocked is Entities without locking. Callers must hold gs.mu.
+func (gs *GameState) entitiesLocked() []EntityInfo {
+	result := make([]EntityInfo, 0, len(gs.entities))
+	for _, e := range gs.entities {
+		if id, ok := gs.playerIdentityByRuntimeIDLocked(e.RuntimeID); ok {
+			e.Player = &id
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RuntimeID < result[j].RuntimeID })
+	return result
+}
+
+// SetVerbosePacketLog enables or disables verbose packet logging.
+func (gs *GameState) SetVerbosePacketLog(enabled bool) {
+	gs.verbosePacketLog.Store(enabled)
 }
 
 // VerbosePacketLog returns whether verbose packet logging is enabled.
 func (gs *GameState) VerbosePacketLog() bool {
+	return gs.verbosePacketLog.Load()
+}
+
+// Packet log directions, as written in the "dir" field of packet logs.
+const (
+	packetDirClient = "C→S"
+	packetDirServer = "S→C"
+)
+
+// SetPacketLogFilter restricts verbose packet logging to the given packet type names
+// and direction (packetDirClient, packetDirServer, or "" for both). An empty type list
+// logs every building packet, which is the default.
+func (gs *GameState) SetPacketLogFilter(types []string, dir string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.packetLogTypes = make(map[string]bool, len(types))
+	for _, t := range types {
+		gs.packetLogTypes[t] = true
+	}
+	gs.packetLogDir = dir
+}
+
+// PacketLogFilter returns the configured packet type filter and direction.
+func (gs *GameState) PacketLogFilter() (types []string, dir string) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	for t := range gs.packetLogTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types, gs.packetLogDir
+}
+
+// PacketLogFilterNames reports whether the filter explicitly names a packet type and
+// allows its direction.
+func (gs *GameState) PacketLogFilterNames(dir, name string) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.packetLogDir != "" && gs.packetLogDir != dir {
+		return false
+	}
+	return gs.packetLogTypes[name]
+}
+
+// ShouldLogPacket reports whether a packet of the given type travelling in dir should
+// be logged, taking the verbose toggle and the packet log filter into account.
+func (gs *GameState) ShouldLogPacket(dir, name string) bool {
+	if !gs.verbosePacketLog.Load() {
+		return false
+	}
 	gs.mu.RLock()
 	defer gs.mu.RUnlock()
-	return gs.verbosePacketLog
+	if gs.packetLogDir != "" && gs.packetLogDir != dir {
+		return false
+	}
+	return len(gs.packetLogTypes) == 0 || gs.packetLogTypes[name]
 }
 
 // ResolveItemName converts a network ID to an item name using the registry (thread-safe).
@@ -431,3 +1118,414 @@ func (gs *GameState) ResolveBlockName(runtimeID uint32) string {
 	}
 	return fmt.Sprintf("rid:%d", runtimeID)
 }
+
+// blockCacheLocked returns the block cache for a dimension, creating it on first use.
+// gs.mu must be held for writing.
+func (gs *GameState) blockCacheLocked(dim int32) *BlockCache {
+	bc, ok := gs.blockCaches[dim]
+	if !ok {
+		bc = NewBlockCache(gs.blockCacheSize)
+		bc.SetSubChunkRadius(gs.chunkRadius)
+		gs.blockCaches[dim] = bc
+	}
+	return bc
+}
+
+// Blocks returns the spatial block cache of the current dimension.
+func (gs *GameState) Blocks() *BlockCache {
+	gs.mu.RLock()
+	dim := gs.dimension
+	bc, ok := gs.blockCaches[dim]
+	gs.mu.RUnlock()
+	if ok {
+		return bc
+	}
+	return gs.BlocksIn(dim)
+}
+
+// BlocksIn returns the spatial block cache of the given dimension. Only the first
+// use of a dimension takes the write lock.
+func (gs *GameState) BlocksIn(dim int32) *BlockCache {
+	gs.mu.RLock()
+	bc, ok := gs.blockCaches[dim]
+	gs.mu.RUnlock()
+	if ok {
+		return bc
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	return gs.blockCacheLocked(dim)
+}
+
+// SetBlockCacheSize sets the maximum number of explicit entries kept per dimension.
+func (gs *GameState) SetBlockCacheSize(maxEntries int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.blockCacheSize = maxEntries
+	for _, bc := range gs.blockCaches {
+		bc.SetMaxEntries(maxEntries)
+	}
+}
+
+// ClearBlocks empties the block caches and block entities of all dimensions.
+func (gs *GameState) ClearBlocks() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.blockCaches = make(map[int32]*BlockCache)
+	gs.blockEntities = make(map[int32]map[protocol.BlockPos]map[string]any)
+	gs.biomes = make(map[int32]map[protocol.ChunkPos][]*palettedStorage)
+}
+
+// BlockNameAt returns the name of the cached block at pos in the current dimension,
+// resolved through the learned block registry. ok is false if the position is not cached.
+func (gs *GameState) BlockNameAt(pos protocol.BlockPos) (name string, ok bool) {
+	e, ok := gs.Blocks().Get(pos)
+	if !ok {
+		return "", false
+	}
+	return gs.ResolveBlockName(e.RuntimeID), true
+}
+
+// blockNameView resolves positions in one block cache to names for a search over
+// many blocks, looking each runtime ID up in the registry only once.
+type blockNameView struct {
+	gs    *GameState
+	cache *BlockCache
+	names map[uint32]string
+}
+
+// BlockNames returns a view of the current dimension's block cache for a search.
+func (gs *GameState) BlockNames() *blockNameView {
+	return &blockNameView{gs: gs, cache: gs.Blocks(), names: make(map[uint32]string)}
+}
+
+// at returns the name of the cached block at pos. ok is false if it is not cached.
+func (v *blockNameView) at(pos protocol.BlockPos) (name string, ok bool) {
+	e, ok := v.cache.Get(pos)
+	if !ok {
+		return "", false
+	}
+	name, known := v.names[e.RuntimeID]
+	if !known {
+		name = v.gs.ResolveBlockName(e.RuntimeID)
+		v.names[e.RuntimeID] = name
+	}
+	return name, true
+}
+
+// SetChunkParsing enables or disables decoding chunk data into the block cache, for
+// chunk columns within radius chunks of the player.
+func (gs *GameState) SetChunkParsing(enabled bool, radius int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.parseChunks = enabled
+	gs.chunkRadius = radius
+	for _, bc := range gs.blockCaches {
+		bc.SetSubChunkRadius(radius)
+	}
+}
+
+// ChunkParsing returns whether chunk parsing is enabled and its radius.
+func (gs *GameState) ChunkParsing() (enabled bool, radius int32) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.parseChunks, int32(gs.chunkRadius)
+}
+
+// AddCommandWaiter registers interest in the CommandOutput for the command request with
+// the given origin UUID. The returned channel receives at most one packet.
+func (gs *GameState) AddCommandWaiter(id uuid.UUID) <-chan *packet.CommandOutput {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	ch := make(chan *packet.CommandOutput, 1)
+	gs.commandWaiters[id] = ch
+	return ch
+}
+
+// RemoveCommandWaiter drops the waiter for a command request, e.g. after a timeout.
+func (gs *GameState) RemoveCommandWaiter(id uuid.UUID) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	delete(gs.commandWaiters, id)
+}
+
+// DeliverCommandOutput hands a CommandOutput to the waiter registered for its origin
+// UUID. It reports whether a waiter was found.
+func (gs *GameState) DeliverCommandOutput(pk *packet.CommandOutput) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	ch, ok := gs.commandWaiters[pk.CommandOrigin.UUID]
+	if !ok {
+		return false
+	}
+	delete(gs.commandWaiters, pk.CommandOrigin.UUID)
+	ch <- pk
+	return true
+}
+
+// SetInvalidPacketMode sets whether relayed packets are validated, and whether
+// those failing validation are dropped (InvalidPacketsDrop) or forwarded anyway
+// (InvalidPacketsForward). With InvalidPacketsOff they are not validated.
+func (gs *GameState) SetInvalidPacketMode(mode string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.invalidPacketMode = mode
+}
+
+// InvalidPacketMode returns the configured invalid packet handling mode.
+func (gs *GameState) InvalidPacketMode() string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.invalidPacketMode
+}
+
+// RecordRelayedPacket counts a packet forwarded in the given direction.
+func (gs *GameState) RecordRelayedPacket(dir string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if dir == packetDirClient {
+		gs.relayStats.ClientToServer++
+	} else {
+		gs.relayStats.ServerToClient++
+	}
+}
+
+// RecordInvalidPacket counts a packet that failed validation, and whether it was dropped.
+func (gs *GameState) RecordInvalidPacket(dropped bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.relayStats.Invalid++
+	if dropped {
+		gs.relayStats.Dropped++
+	}
+}
+
+// RelayStats returns the packet counters of the current session.
+func (gs *GameState) RelayStats() RelayStats {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.relayStats
+}
+
+// SetClientVersion records the connecting client's protocol and game version, compared
+// against the protocol the proxy speaks to the Realm.
+func (gs *GameState) SetClientVersion(protocolID int32, version string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.versions = ProtocolVersions{
+		ClientProtocol: protocolID,
+		ClientVersion:  version,
+		ProxyProtocol:  protocol.CurrentProtocol,
+		ProxyVersion:   protocol.CurrentVersion,
+		Mismatch:       protocolID != protocol.CurrentProtocol,
+	}
+}
+
+// SetRealmBaseGameVersion records the base game version reported by the Realm.
+func (gs *GameState) SetRealmBaseGameVersion(version string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.versions.RealmBaseGameVersion = version
+}
+
+// ProtocolVersions returns the versions recorded for the current session.
+func (gs *GameState) ProtocolVersions() ProtocolVersions {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.versions
+}
+
+// SetStrictProtocol sets whether clients on a different protocol version are refused.
+func (gs *GameState) SetStrictProtocol(strict bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.strictProtocol = strict
+}
+
+// StrictProtocol reports whether clients on a different protocol version are refused.
+func (gs *GameState) StrictProtocol() bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.strictProtocol
+}
+
+// firstBridgeStackRequestID is the first request ID used for item stack requests sent by
+// the bridge. The client counts down from -1 in steps of 2, so the bridge counts down in
+// the same way from a distant starting point to avoid colliding with its IDs.
+const firstBridgeStackRequestID = -1_000_001
+
+// isBridgeStackRequestID reports whether an item stack request ID was allocated by
+// the bridge rather than the client.
+func isBridgeStackRequestID(id int32) bool {
+	return id <= firstBridgeStackRequestID
+}
+
+// AddItemStackWaiter allocates a request ID for an item stack request and registers
+// interest in its response. The returned channel receives at most one response.
+func (gs *GameState) AddItemStackWaiter() (int32, <-chan protocol.ItemStackResponse) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	id := gs.nextStackRequestID
+	gs.nextStackRequestID -= 2
+	ch := make(chan protocol.ItemStackResponse, 1)
+	gs.stackWaiters[id] = ch
+	return id, ch
+}
+
+// RemoveItemStackWaiter drops the waiter for an item stack request.
+func (gs *GameState) RemoveItemStackWaiter(id int32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	delete(gs.stackWaiters, id)
+}
+
+// DeliverItemStackResponse hands a response to the waiter registered for its request
+// ID. It reports whether a waiter was found.
+func (gs *GameState) DeliverItemStackResponse(resp protocol.ItemStackResponse) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	ch, ok := gs.stackWaiters[resp.RequestID]
+	if !ok {
+		return false
+	}
+	delete(gs.stackWaiters, resp.RequestID)
+	ch <- resp
+	return true
+}
+
+// SetCreativeItems replaces the creative inventory, indexing items by name.
+func (gs *GameState) SetCreativeItems(items []protocol.CreativeItem) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.creativeItems = make(map[string]uint32, len(items))
+	for _, item := range items {
+		name := gs.resolveItemName(item.Item.NetworkID)
+		if _, ok := gs.creativeItems[name]; !ok {
+			// Keep the first variant (e.g. the undamaged, unenchanted item).
+			gs.creativeItems[name] = item.CreativeItemNetworkID
+		}
+	}
+}
+
+// CreativeItemCount returns the number of distinct items in the creative inventory.
+// It is zero until CreativeContent has been received.
+func (gs *GameState) CreativeItemCount() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return len(gs.creativeItems)
+}
+
+// CreativeItemID returns the creative item network ID for an item name.
+func (gs *GameState) CreativeItemID(name string) (uint32, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	id, ok := gs.creativeItems[name]
+	return id, ok
+}
+
+// AddRecipes stores the crafting recipes from a CraftingData packet.
+func (gs *GameState) AddRecipes(recipes []protocol.Recipe, clear bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if clear {
+		gs.recipes = make(map[uint32]RecipeInfo)
+	}
+	for _, r := range recipes {
+		if info, ok := gs.recipeInfo(r); ok {
+			gs.recipes[info.NetworkID] = info
+		}
+	}
+}
+
+// FindRecipe looks up a recipe by network ID (as a decimal string) or recipe ID.
+func (gs *GameState) FindRecipe(key string) (RecipeInfo, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	for _, r := range gs.recipes {
+		if r.RecipeID == key || strconv.FormatUint(uint64(r.NetworkID), 10) == key {
+			return r, true
+		}
+	}
+	return RecipeInfo{}, false
+}
+
+// SetWorldSpawn updates the world spawn position.
+func (gs *GameState) SetWorldSpawn(pos protocol.BlockPos) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.spawnPos = pos
+}
+
+// SetPlayerSpawn records the player's own spawn point (bed or respawn anchor).
+func (gs *GameState) SetPlayerSpawn(pos protocol.BlockPos, dim int32) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.playerSpawn = pos
+	gs.playerSpawnDim = dim
+	gs.hasPlayerSpawn = true
+}
+
+// PlayerSpawn returns the player's spawn point. ok is false until the server sends one.
+func (gs *GameState) PlayerSpawn() (pos protocol.BlockPos, dim int32, ok bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.playerSpawn, gs.playerSpawnDim, gs.hasPlayerSpawn
+}
+
+// LoadWaypoints loads waypoints from path and saves future changes back to it.
+func (gs *GameState) LoadWaypoints(path string) error {
+	waypoints, err := loadWaypoints(path)
+	if err != nil {
+		return err
+	}
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.waypoints = waypoints
+	gs.waypointsFile = path
+	return nil
+}
+
+// SetWaypoint adds or replaces a waypoint, saving all waypoints if a file is configured.
+func (gs *GameState) SetWaypoint(w Waypoint) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.waypoints[w.Name] = w
+	if gs.waypointsFile == "" {
+		return nil
+	}
+	return saveWaypoints(gs.waypointsFile, gs.waypointListLocked())
+}
+
+// Waypoint returns the named waypoint.
+func (gs *GameState) Waypoint(name string) (Waypoint, bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	w, ok := gs.waypoints[name]
+	return w, ok
+}
+
+// Waypoints returns all waypoints sorted by name.
+func (gs *GameState) Waypoints() []Waypoint {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.waypointListLocked()
+}
+
+// WaypointNames returns the names of all waypoints in sorted order.
+func (gs *GameState) WaypointNames() []string {
+	var names []string
+	for _, w := range gs.Waypoints() {
+		names = append(names, w.Name)
+	}
+	return names
+}
+
+// waypointListLocked returns the waypoints sorted by name. gs.mu must be held.
+func (gs *GameState) waypointListLocked() []Waypoint {
+	list := make([]Waypoint, 0, len(gs.waypoints))
+	for _, w := range gs.waypoints {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
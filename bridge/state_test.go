@@ -59,6 +59,46 @@ func TestIdentity(t *testing.T) {
 	}
 }
 
+func TestIdentity_DisplayNameOverride(t *testing.T) {
+	gs := NewGameState()
+	gs.SetDisplayNameOverride("Builder")
+	gs.SetIdentity("Steve", "12345", 42)
+	name, xuid := gs.ChatIdentity()
+	if name != "Builder" || xuid != "12345" {
+		t.Errorf("expected overridden chat name Builder with xuid 12345, got %q/%q", name, xuid)
+	}
+	if name, _ := gs.Identity(); name != "Steve" {
+		t.Errorf("expected the account name Steve outside chat, got %q", name)
+	}
+	if got := gs.Snapshot().PlayerName; got != "Steve" {
+		t.Errorf("expected the account name Steve in the snapshot, got %q", got)
+	}
+
+	gs.SetDisplayNameOverride("")
+	if name, _ := gs.ChatIdentity(); name != "Steve" {
+		t.Errorf("expected the account name Steve without an override, got %q", name)
+	}
+}
+
+func TestValidateDisplayName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Steve", false},
+		{"Sixteen_Chars_Ok", false},
+		{"", true},
+		{"Seventeen_Chars_X", true},
+		{" Steve", true},
+		{"Stevé", false},
+	}
+	for _, tt := range tests {
+		if err := validateDisplayName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("validateDisplayName(%q) error = %v, expected error=%v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
 func TestPosition(t *testing.T) {
 	gs := NewGameState()
 	gs.UpdatePosition(1.0, 2.0, 3.0, 45.0, 90.0)
@@ -213,6 +253,40 @@ func TestChatHistory(t *testing.T) {
 	}
 }
 
+func TestChatHistory_CustomSize(t *testing.T) {
+	gs := NewGameState()
+	gs.SetChatHistorySize(250)
+	for i := 0; i < 300; i++ {
+		gs.AppendChat(ChatMessage{Message: fmt.Sprintf("msg%d", i)})
+	}
+	h := gs.ChatHistory(0)
+	if len(h) != 250 {
+		t.Fatalf("expected 250 messages, got %d", len(h))
+	}
+	if h[0].Message != "msg50" {
+		t.Errorf("expected oldest kept message msg50, got %q", h[0].Message)
+	}
+	if got := gs.ChatSince(0); len(got) != 250 {
+		t.Errorf("expected ChatSince to return 250 messages, got %d", len(got))
+	}
+
+	// Shrinking drops the oldest messages
+	gs.SetChatHistorySize(5)
+	h = gs.ChatHistory(0)
+	if len(h) != 5 || h[0].Message != "msg295" {
+		t.Errorf("expected last 5 messages from msg295, got %+v", h)
+	}
+	gs.AppendChat(ChatMessage{Message: "new"})
+	if h := gs.ChatHistory(10); len(h) != 5 || h[4].Message != "new" {
+		t.Errorf("expected 5 messages ending with new, got %+v", h)
+	}
+
+	gs.SetChatHistorySize(0)
+	if size := gs.ChatHistorySize(); size != 1 {
+		t.Errorf("expected size clamped to 1, got %d", size)
+	}
+}
+
 func TestPlayers(t *testing.T) {
 	gs := NewGameState()
 	gs.AddPlayer("x1", "Alice")
@@ -326,6 +400,87 @@ func TestInitFromGameData(t *testing.T) {
 	}
 }
 
+func TestInitFromGameData_SessionInfo(t *testing.T) {
+	gs := NewGameState()
+	gs.InitFromGameData(minecraft.GameData{
+		BaseGameVersion:              "1.21.0",
+		ServerAuthoritativeInventory: true,
+		EntityRuntimeID:              7,
+		PlayerGameMode:               1,
+		WorldGameMode:                0,
+		Experiments: []protocol.ExperimentData{
+			{Name: "gametest", Enabled: true},
+			{Name: "data_driven_biomes", Enabled: false},
+		},
+	})
+
+	info := gs.SessionInfo()
+	if info.BaseGameVersion != "1.21.0" || !info.ServerAuthoritativeInventory || info.EntityRuntimeID != 7 {
+		t.Errorf("unexpected session info: %+v", info)
+	}
+	if info.InitialGameMode != "creative" || info.WorldGameMode != "survival" {
+		t.Errorf("expected creative/survival game modes, got %q/%q", info.InitialGameMode, info.WorldGameMode)
+	}
+	if len(info.Experiments) != 1 || info.Experiments[0] != "gametest" {
+		t.Errorf("expected only enabled experiments, got %v", info.Experiments)
+	}
+}
+
+func TestInitFromGameData_WorldGeneration(t *testing.T) {
+	gs := NewGameState()
+	gs.InitFromGameData(minecraft.GameData{
+		WorldSeed:  -4172144997902289642,
+		WorldSpawn: protocol.BlockPos{8, 70, -16},
+		GameRules:  []protocol.GameRule{{Name: "spawnradius", Value: uint32(5)}},
+	})
+
+	gen := gs.WorldGeneration()
+	if gen.Seed != -4172144997902289642 || !gen.SeedKnown {
+		t.Errorf("expected known seed, got %+v", gen)
+	}
+	if gen.SpawnPos != (protocol.BlockPos{8, 70, -16}) {
+		t.Errorf("expected spawn 8,70,-16, got %v", gen.SpawnPos)
+	}
+	if gen.SpawnRadius == nil || *gen.SpawnRadius != 5 {
+		t.Errorf("expected spawn radius 5, got %v", gen.SpawnRadius)
+	}
+
+	hidden := NewGameState()
+	hidden.InitFromGameData(minecraft.GameData{})
+	if gen := hidden.WorldGeneration(); gen.SeedKnown || gen.SpawnRadius != nil {
+		t.Errorf("expected unknown seed and spawn radius, got %+v", gen)
+	}
+}
+
+func TestInitFromGameData_GameRules(t *testing.T) {
+	gs := NewGameState()
+	gd := minecraft.GameData{
+		GameRules: []protocol.GameRule{
+			{Name: "dodaylightcycle", Value: false},
+			{Name: "randomtickspeed", Value: uint32(3)},
+			{Name: "commandblockoutput", Value: true},
+		},
+	}
+	gs.InitFromGameData(gd)
+
+	rules := gs.GameRules()
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 game rules, got %d", len(rules))
+	}
+	if v, ok := rules["dodaylightcycle"].(bool); !ok || v {
+		t.Errorf("expected dodaylightcycle=false, got %v", rules["dodaylightcycle"])
+	}
+	if v, ok := rules["randomtickspeed"].(uint32); !ok || v != 3 {
+		t.Errorf("expected randomtickspeed=uint32(3), got %#v", rules["randomtickspeed"])
+	}
+
+	// Returned map is a copy
+	rules["dodaylightcycle"] = true
+	if gs.GameRules()["dodaylightcycle"] != false {
+		t.Error("modifying returned map should not affect state")
+	}
+}
+
 func TestWorldInfo(t *testing.T) {
 	gs := NewGameState()
 	gs.mu.Lock()
@@ -354,6 +509,62 @@ func TestWorldInfo(t *testing.T) {
 	}
 }
 
+func TestWindowSlots(t *testing.T) {
+	gs := NewGameState()
+	gs.mu.Lock()
+	gs.itemRegistry[5] = "minecraft:stone"
+	gs.mu.Unlock()
+
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 2, protocol.ItemInstance{
+		Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 5}, Count: 3},
+	})
+	gs.UpdateInventorySlot(protocol.WindowIDInventory, 10, protocol.ItemInstance{
+		Stack: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: 5}, Count: 1},
+	})
+
+	w := inventoryWindows["hotbar"]
+	slots := gs.WindowSlots(w.WindowID, w.FirstSlot, w.LastSlot)
+	if len(slots) != 9 {
+		t.Fatalf("expected 9 hotbar slots, got %d", len(slots))
+	}
+	if slots[2].Slot != 2 || slots[2].Item != "minecraft:stone" || slots[2].Count != 3 {
+		t.Errorf("expected stone x3 in slot 2, got %+v", slots[2])
+	}
+	if slots[0].Item != "" || slots[0].Count != 0 {
+		t.Errorf("expected slot 0 to be empty, got %+v", slots[0])
+	}
+
+	// Untracked windows report empty slots.
+	w = inventoryWindows["armor"]
+	if slots := gs.WindowSlots(w.WindowID, w.FirstSlot, w.LastSlot); len(slots) != 4 || slots[3].Count != 0 {
+		t.Errorf("expected 4 empty armor slots, got %+v", slots)
+	}
+}
+
+func TestChatSince(t *testing.T) {
+	gs := NewGameState()
+	gs.AppendChat(ChatMessage{Message: "before"})
+	seq := gs.ChatSeq()
+	if got := gs.ChatSince(seq); len(got) != 0 {
+		t.Errorf("expected no messages since seq, got %d", len(got))
+	}
+
+	gs.AppendChat(ChatMessage{Message: "one"})
+	gs.AppendChat(ChatMessage{Message: "two"})
+	got := gs.ChatSince(seq)
+	if len(got) != 2 || got[0].Message != "one" || got[1].Message != "two" {
+		t.Errorf("expected [one two], got %+v", got)
+	}
+
+	// Messages that fell out of the ring buffer are not returned.
+	for i := 0; i < DefaultChatHistory+10; i++ {
+		gs.AppendChat(ChatMessage{Message: "spam"})
+	}
+	if got := gs.ChatSince(seq); len(got) != DefaultChatHistory {
+		t.Errorf("expected %d messages, got %d", DefaultChatHistory, len(got))
+	}
+}
+
 func TestEntities(t *testing.T) {
 	gs := NewGameState()
 
@@ -387,6 +598,64 @@ func TestEntities(t *testing.T) {
 	gs.UpdateEntityPosition(999, mgl32.Vec3{0, 0, 0})
 }
 
+func TestDimensionRoundTrip(t *testing.T) {
+	gs := NewGameState()
+	overworldPos := protocol.BlockPos{10, 64, 10}
+	netherPos := protocol.BlockPos{1, 40, 1}
+
+	gs.AddEntity(100, "minecraft:zombie", mgl32.Vec3{10, 64, 10})
+	gs.Blocks().Set(overworldPos, 7)
+
+	// Enter the nether: overworld entities and blocks are no longer visible.
+	gs.SetDimension(1)
+	if n := len(gs.Entities()); n != 0 {
+		t.Errorf("expected no entities after entering the nether, got %d", n)
+	}
+	if _, ok := gs.Blocks().Get(overworldPos); ok {
+		t.Error("expected overworld block to be hidden in the nether")
+	}
+	gs.AddEntity(200, "minecraft:ghast", mgl32.Vec3{1, 40, 1})
+	gs.Blocks().Set(netherPos, 9)
+
+	// Return to the overworld: overworld blocks are back, nether data is not visible.
+	gs.SetDimension(0)
+	if e, ok := gs.Blocks().Get(overworldPos); !ok || e.RuntimeID != 7 {
+		t.Errorf("expected overworld block 7 after returning, got %+v (ok=%v)", e, ok)
+	}
+	if _, ok := gs.Blocks().Get(netherPos); ok {
+		t.Error("expected nether block to be hidden in the overworld")
+	}
+	if n := len(gs.Entities()); n != 0 {
+		t.Errorf("expected nether entities to be dropped, got %d", n)
+	}
+	if e, ok := gs.BlocksIn(1).Get(netherPos); !ok || e.RuntimeID != 9 {
+		t.Errorf("expected nether block 9 to be retained, got %+v (ok=%v)", e, ok)
+	}
+
+	// Re-entering the same dimension keeps entities.
+	gs.AddEntity(101, "minecraft:pig", mgl32.Vec3{0, 64, 0})
+	gs.SetDimension(0)
+	if n := len(gs.Entities()); n != 1 {
+		t.Errorf("expected 1 entity after same-dimension update, got %d", n)
+	}
+
+	// A session starting in another dimension drops them too.
+	gs.InitFromGameData(minecraft.GameData{Dimension: 2})
+	if n := len(gs.Entities()); n != 0 {
+		t.Errorf("expected entities to be dropped by a StartGame in the end, got %d", n)
+	}
+}
+
+func TestClearBlocks(t *testing.T) {
+	gs := NewGameState()
+	gs.Blocks().Set(protocol.BlockPos{0, 0, 0}, 1)
+	gs.BlocksIn(2).Set(protocol.BlockPos{0, 0, 0}, 2)
+	gs.ClearBlocks()
+	if gs.Blocks().Len() != 0 || gs.BlocksIn(2).Len() != 0 {
+		t.Error("expected all dimensions to be cleared")
+	}
+}
+
 func TestVerbosePacketLog(t *testing.T) {
 	gs := NewGameState()
 	if gs.VerbosePacketLog() {
@@ -493,3 +762,21 @@ func TestConcurrency(t *testing.T) {
 	// If we get here without deadlock or panic, concurrency is OK.
 	// The -race flag will catch data races.
 }
+
+func TestProtocolVersions(t *testing.T) {
+	gs := NewGameState()
+	gs.SetClientVersion(protocol.CurrentProtocol, protocol.CurrentVersion)
+	gs.SetRealmBaseGameVersion("1.21.0")
+	v := gs.ProtocolVersions()
+	if v.Mismatch {
+		t.Errorf("expected no mismatch for the current protocol, got %+v", v)
+	}
+	if v.RealmBaseGameVersion != "1.21.0" || v.ProxyProtocol != protocol.CurrentProtocol {
+		t.Errorf("unexpected versions: %+v", v)
+	}
+
+	gs.SetClientVersion(protocol.CurrentProtocol-1, "1.0.0")
+	if v := gs.ProtocolVersions(); !v.Mismatch || v.ClientProtocol != protocol.CurrentProtocol-1 {
+		t.Errorf("expected mismatch for an older protocol, got %+v", v)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Teleport confirmation settings.
+const (
+	teleportConfirmTimeout = 2 * time.Second
+	teleportPollInterval   = 50 * time.Millisecond
+	teleportTolerance      = 0.5 // blocks, per axis
+)
+
+// TeleportResult reports where a teleport actually left the player.
+type TeleportResult struct {
+	Requested [3]float64 `json:"requested"`
+	Landed    [3]float64 `json:"landed"` // feet position
+	Confirmed bool       `json:"confirmed"`
+	Mismatch  bool       `json:"mismatch"`
+}
+
+// RecordServerMove updates the position from a server MovePlayer for our entity and
+// counts the move, so callers can wait for the server to move the player.
+func (gs *GameState) RecordServerMove(x, y, z, pitch, yaw float32) {
+	gs.UpdatePosition(x, y, z, pitch, yaw)
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.serverMoveSeq++
+}
+
+// ServerMoveSeq returns the number of server moves recorded so far.
+func (gs *GameState) ServerMoveSeq() uint64 {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.serverMoveSeq
+}
+
+// teleportConfirmed builds the result of a teleport to x, y, z (feet position) from
+// the player's tracked position.
+func teleportConfirmed(state *GameState, x, y, z float64, confirmed bool) TeleportResult {
+	px, py, pz, _, _, _ := state.Position()
+	landed := [3]float64{float64(px), float64(py) - playerEyeHeight, float64(pz)}
+	result := TeleportResult{
+		Requested: [3]float64{x, y, z},
+		Landed:    landed,
+		Confirmed: confirmed,
+	}
+	if confirmed {
+		result.Mismatch = math.Abs(landed[0]-x) > teleportTolerance ||
+			math.Abs(landed[1]-y) > teleportTolerance ||
+			math.Abs(landed[2]-z) > teleportTolerance
+	}
+	return result
+}
+
+// teleportAndConfirm teleports the player with /tp and waits up to timeout for the
+// server's MovePlayer to report where the player landed.
+func teleportAndConfirm(ctx context.Context, state *GameState, x, y, z float64, timeout time.Duration) (TeleportResult, error) {
+	seq := state.ServerMoveSeq()
+	if err := sendCommand(state, fmt.Sprintf("tp @s %.2f %.2f %.2f", x, y, z)); err != nil {
+		return TeleportResult{}, err
+	}
+
+	ticker := time.NewTicker(teleportPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for state.ServerMoveSeq() == seq {
+		select {
+		case <-ctx.Done():
+			return TeleportResult{}, ctx.Err()
+		case <-deadline:
+			return teleportConfirmed(state, x, y, z, false), nil
+		case <-ticker.C:
+		}
+	}
+	return teleportConfirmed(state, x, y, z, true), nil
+}
+
+// maxRelativeTeleport caps each offset of a relative teleport, in blocks.
+const maxRelativeTeleport = 1000
+
+// dimensionHeight returns the lowest and one past the highest block Y of a dimension.
+func dimensionHeight(dimension int32) (minY, maxY float64) {
+	switch dimension {
+	case 1: // nether
+		return 0, 128
+	case 2: // end
+		return 0, 256
+	}
+	return float64(minSubChunkIndex(dimension)) * 16, 320
+}
+
+// relativeDestination returns the feet position dx, dy, dz away from the player.
+// Offsets are capped at maxRelativeTeleport and Y is kept within the dimension's
+// build height; clamped reports whether either limit applied.
+func relativeDestination(state *GameState, dx, dy, dz float64) (dest [3]float64, clamped bool) {
+	px, py, pz, _, _, dimension := state.Position()
+	offsets := [3]float64{dx, dy, dz}
+	for i, d := range offsets {
+		if c := math.Max(-maxRelativeTeleport, math.Min(maxRelativeTeleport, d)); c != d {
+			offsets[i], clamped = c, true
+		}
+	}
+	dest = [3]float64{
+		float64(px) + offsets[0],
+		float64(py) - playerEyeHeight + offsets[1],
+		float64(pz) + offsets[2],
+	}
+	minY, maxY := dimensionHeight(dimension)
+	if y := math.Max(minY, math.Min(maxY-1, dest[1])); y != dest[1] {
+		dest[1], clamped = y, true
+	}
+	return dest, clamped
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_MovePlayer_CountsServerMoves(t *testing.T) {
+	gs := NewGameState()
+	gs.SetIdentity("Steve", "123", 42)
+
+	interceptServerPacket(&packet.MovePlayer{EntityRuntimeID: 99, Position: mgl32.Vec3{1, 2, 3}}, gs)
+	if gs.ServerMoveSeq() != 0 {
+		t.Error("expected moves of other entities not to count")
+	}
+	interceptServerPacket(&packet.MovePlayer{EntityRuntimeID: 42, Position: mgl32.Vec3{1, 2, 3}}, gs)
+	if gs.ServerMoveSeq() != 1 {
+		t.Errorf("expected 1 server move, got %d", gs.ServerMoveSeq())
+	}
+}
+
+func TestTeleportConfirmed(t *testing.T) {
+	tests := []struct {
+		name         string
+		landed       mgl32.Vec3 // eye position
+		wantMismatch bool
+	}{
+		{"arrived", mgl32.Vec3{100.5, 70 + playerEyeHeight, -20.5}, false},
+		{"placed elsewhere", mgl32.Vec3{0.5, 64 + playerEyeHeight, 0.5}, true},
+	}
+	for _, tt := range tests {
+		gs := NewGameState()
+		gs.UpdatePosition(tt.landed.X(), tt.landed.Y(), tt.landed.Z(), 0, 0)
+		result := teleportConfirmed(gs, 100.5, 70, -20.5, true)
+		if result.Mismatch != tt.wantMismatch {
+			t.Errorf("%s: expected mismatch %v, got %+v", tt.name, tt.wantMismatch, result)
+		}
+	}
+
+	gs := NewGameState()
+	if result := teleportConfirmed(gs, 100, 70, 0, false); result.Mismatch {
+		t.Error("expected no mismatch reported for an unconfirmed teleport")
+	}
+}
+
+func TestRelativeDestination(t *testing.T) {
+	tests := []struct {
+		name        string
+		dimension   int32
+		dx, dy, dz  float64
+		want        [3]float64
+		wantClamped bool
+	}{
+		{"up", 0, 0, 10, 0, [3]float64{10.5, 74, -3.5}, false},
+		{"offset", 0, -5, -2, 3, [3]float64{5.5, 62, -0.5}, false},
+		{"far", 0, 5000, 0, -5000, [3]float64{1010.5, 64, -1003.5}, true},
+		{"above overworld", 0, 0, 500, 0, [3]float64{10.5, 319, -3.5}, true},
+		{"below overworld", 0, 0, -200, 0, [3]float64{10.5, -64, -3.5}, true},
+		{"above nether", 1, 0, 100, 0, [3]float64{10.5, 127, -3.5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := NewGameState()
+			gs.UpdatePosition(10.5, 64+playerEyeHeight, -3.5, 0, 0)
+			gs.SetDimension(tt.dimension)
+			got, clamped := relativeDestination(gs, tt.dx, tt.dy, tt.dz)
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 0.001 {
+					t.Errorf("expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+			if clamped != tt.wantClamped {
+				t.Errorf("expected clamped %v, got %v", tt.wantClamped, clamped)
+			}
+		})
+	}
+}
@@ -22,11 +22,18 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 	// chat
 	s.AddTool(
 		mcp.NewTool("chat",
-			mcp.WithDescription("Send a chat message to the Realm as the connected player"),
+			mcp.WithDescription("Send a chat message to the Realm as the connected player, either publicly, as a whisper to one player (/tell) or as an emote (/me)"),
 			mcp.WithString("message",
 				mcp.Required(),
 				mcp.Description("The chat message to send"),
 			),
+			mcp.WithString("type",
+				mcp.Description("Message type: 'chat' (default) for public chat, 'whisper' to send privately to 'target', 'me' for an emote"),
+				mcp.Enum(chatTypeChat, chatTypeWhisper, chatTypeMe),
+			),
+			mcp.WithString("target",
+				mcp.Description("Player name to whisper to (required for type 'whisper')"),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			if err := requireConnected(state); err != nil {
@@ -36,20 +43,50 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
-			name, xuid := state.Identity()
-			conn := state.ServerConn()
-			if conn == nil {
-				return mcp.NewToolResultError("server connection not available"), nil
+			kind := req.GetString("type", chatTypeChat)
+			line, err := chatCommand(state, kind, req.GetString("target", ""), msg)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
-			if err := conn.WritePacket(&packet.Text{
-				TextType:   packet.TextTypeChat,
-				SourceName: name,
-				XUID:       xuid,
-				Message:    msg,
-			}); err != nil {
+			if err := sendChat(state, line); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("send error: %v", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("sent %s: %s", kind, line)), nil
+		},
+	)
+
+	// emote
+	s.AddTool(
+		mcp.NewTool("emote",
+			mcp.WithDescription(fmt.Sprintf("Play an emote as the connected player, visible to nearby players. Give a built-in emote name (%s) or the UUID of an emote the account owns.", strings.Join(builtinEmoteNames(), ", "))),
+			mcp.WithString("emote",
+				mcp.Required(),
+				mcp.Description("Built-in emote name or emote UUID"),
+			),
+			mcp.WithNumber("ticks",
+				mcp.Description(fmt.Sprintf("How long the emote plays, in ticks (default %d)", defaultEmoteTicks)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			emote, err := req.RequireString("emote")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			id, err := resolveEmoteID(emote)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ticks := req.GetInt("ticks", defaultEmoteTicks)
+			if ticks < 1 {
+				return mcp.NewToolResultError("ticks must be positive"), nil
+			}
+			if err := sendEmote(state, id, uint32(ticks)); err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("send error: %v", err)), nil
 			}
-			return mcp.NewToolResultText(fmt.Sprintf("sent chat: %s", msg)), nil
+			return mcp.NewToolResultText("played emote " + id), nil
 		},
 	)
 
@@ -61,6 +98,16 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 				mcp.Required(),
 				mcp.Description("The command to execute (without leading /)"),
 			),
+			mcp.WithString("method",
+				mcp.Description("How to send the command. 'chat' (default) sends it as a chat message, which is safe on Realms but returns no structured output. 'packet' sends a real CommandRequest and returns the command's output, but some Realms disconnect clients that send CommandRequest packets."),
+				mcp.Enum(commandMethodChat, commandMethodPacket),
+			),
+			mcp.WithBoolean("internal",
+				mcp.Description("Packet method only: mark the command as internally issued, like a command block. Some Realms relax player-facing checks for internal commands. Leave false (default) unless a command behaves differently when run from a command block."),
+			),
+			mcp.WithString("version",
+				mcp.Description("Packet method only: command syntax version to parse the command with (default 'latest')"),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			if err := requireConnected(state); err != nil {
@@ -73,8 +120,24 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 			// Ensure leading slash
 			cmd = strings.TrimPrefix(cmd, "/")
 
+			switch req.GetString("method", commandMethodChat) {
+			case commandMethodChat:
+			case commandMethodPacket:
+				opts := commandRequestOptions{
+					Internal: req.GetBool("internal", false),
+					Version:  req.GetString("version", ""),
+				}
+				result, err := sendCommandRequest(ctx, state, cmd, opts, commandOutputTimeout)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				return jsonResult(result)
+			default:
+				return mcp.NewToolResultError("method must be chat or packet"), nil
+			}
+
 			// Send as chat message — CommandRequest packets can cause disconnects on Realms
-			name, xuid := state.Identity()
+			name, xuid := state.ChatIdentity()
 			conn := state.ServerConn()
 			if conn == nil {
 				return mcp.NewToolResultError("server connection not available"), nil
@@ -91,10 +154,135 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 		},
 	)
 
+	// export_commands
+	s.AddTool(
+		mcp.NewTool("export_commands",
+			mcp.WithDescription("Write the Realm's full command tree to a JSON file: every command with its description, aliases, permission level, flags and overload parameters, plus the values of every enum and soft enum the parameters refer to. For documentation and offline reasoning; use get_available_commands for live queries."),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("File to write, e.g. commands.json (replaced if it exists)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			path, err := req.RequireString("path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tree := state.CommandTree()
+			if tree.ReceivedAt.IsZero() {
+				return mcp.NewToolResultError("no AvailableCommands received from the Realm yet"), nil
+			}
+			result, err := exportCommandTree(path, tree)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("export failed: %v", err)), nil
+			}
+			return jsonResult(result)
+		},
+	)
+
+	// set_waypoint
+	s.AddTool(
+		mcp.NewTool("set_waypoint",
+			mcp.WithDescription("Save a named waypoint for use with goto. Defaults to the player's current position. Waypoints last for the session, or are saved to disk when the bridge runs with -waypoints-file."),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Waypoint name")),
+			mcp.WithNumber("x", mcp.Description("X coordinate (default: current position)")),
+			mcp.WithNumber("y", mcp.Description("Y coordinate (default: current position)")),
+			mcp.WithNumber("z", mcp.Description("Z coordinate (default: current position)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := req.RequireString("name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if err := validateWaypointName(name); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			x, y, z, _, _, dim := state.Position()
+			w := Waypoint{
+				Name:      name,
+				X:         float32(req.GetFloat("x", float64(x))),
+				Y:         float32(req.GetFloat("y", float64(y-playerEyeHeight))),
+				Z:         float32(req.GetFloat("z", float64(z))),
+				Dimension: dim,
+			}
+			if err := state.SetWaypoint(w); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("waypoint set but not saved: %v", err)), nil
+			}
+			return jsonResult(w)
+		},
+	)
+
+	// goto
+	s.AddTool(
+		mcp.NewTool("goto",
+			mcp.WithDescription("Teleport the player to a navigation anchor: 'spawn' (world spawn), 'bed' or 'respawn' (the player's spawn point), or a waypoint saved with set_waypoint"),
+			mcp.WithString("target", mcp.Required(), mcp.Description("spawn, bed, respawn, or a waypoint name")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, err := req.RequireString("target")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			cmd, err := gotoCommand(state, target)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if err := sendCommand(state, cmd); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("goto error: %v", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("going to %s: /%s", target, cmd)), nil
+		},
+	)
+
+	// run_commands
+	s.AddTool(
+		mcp.NewTool("run_commands",
+			mcp.WithDescription("Execute a sequence of Minecraft commands in order, capturing each command's chat response. Stops at the first failure unless continue_on_error is set. Returns a per-command report."),
+			mcp.WithArray("commands",
+				mcp.Required(),
+				mcp.Description("Commands to execute, without leading slash (e.g. [\"time set day\", \"weather clear\"])"),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+			mcp.WithNumber("delay_ms",
+				mcp.Description("Delay in milliseconds after each command, used to collect its response (default 500)"),
+			),
+			mcp.WithBoolean("continue_on_error",
+				mcp.Description("Keep going after a command fails (default false)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			cmds, err := req.RequireStringSlice("commands")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(cmds) == 0 {
+				return mcp.NewToolResultError("no commands provided"), nil
+			}
+			delay := time.Duration(req.GetInt("delay_ms", 500)) * time.Millisecond
+			continueOnError := req.GetBool("continue_on_error", false)
+
+			result := runCommands(ctx, state, cmds, delay, continueOnError)
+			slog.Info("run_commands finished", "succeeded", result.Succeeded, "failed", result.Failed, "skipped", result.Skipped)
+			return jsonResult(result)
+		},
+	)
+
 	// teleport
 	s.AddTool(
 		mcp.NewTool("teleport",
-			mcp.WithDescription("Teleport the player to specific coordinates"),
+			mcp.WithDescription("Teleport the player to specific coordinates and wait for the server to confirm where the player landed. 'mismatch' is set if the server placed the player elsewhere; 'confirmed' is false if no position update arrived in time."),
 			mcp.WithNumber("x", mcp.Required(), mcp.Description("X coordinate")),
 			mcp.WithNumber("y", mcp.Required(), mcp.Description("Y coordinate")),
 			mcp.WithNumber("z", mcp.Required(), mcp.Description("Z coordinate")),
@@ -116,21 +304,130 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			msg := fmt.Sprintf("/tp @s %.2f %.2f %.2f", x, y, z)
-			name, xuid := state.Identity()
-			conn := state.ServerConn()
-			if conn == nil {
-				return mcp.NewToolResultError("server connection not available"), nil
+			result, err := teleportAndConfirm(ctx, state, x, y, z, teleportConfirmTimeout)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("teleport error: %v", err)), nil
 			}
-			if err := conn.WritePacket(&packet.Text{
-				TextType:   packet.TextTypeChat,
-				SourceName: name,
-				XUID:       xuid,
-				Message:    msg,
-			}); err != nil {
+			return jsonResult(result)
+		},
+	)
+
+	// teleport_relative
+	s.AddTool(
+		mcp.NewTool("teleport_relative",
+			mcp.WithDescription("Teleport the player by an offset from its current position and wait for the server to confirm where the player landed. Offsets are capped at 1000 blocks and Y is kept within the dimension's build height; 'clamped' is set if either limit applied."),
+			mcp.WithNumber("dx", mcp.Description("X offset in blocks (default 0)")),
+			mcp.WithNumber("dy", mcp.Description("Y offset in blocks (default 0)")),
+			mcp.WithNumber("dz", mcp.Description("Z offset in blocks (default 0)")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			dest, clamped := relativeDestination(state, req.GetFloat("dx", 0), req.GetFloat("dy", 0), req.GetFloat("dz", 0))
+
+			result, err := teleportAndConfirm(ctx, state, dest[0], dest[1], dest[2], teleportConfirmTimeout)
+			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("teleport error: %v", err)), nil
 			}
-			return mcp.NewToolResultText(fmt.Sprintf("teleporting to (%.2f, %.2f, %.2f)", x, y, z)), nil
+			return jsonResult(struct {
+				TeleportResult
+				Clamped bool `json:"clamped"`
+			}{result, clamped})
+		},
+	)
+
+	// set_gamerule
+	s.AddTool(
+		mcp.NewTool("set_gamerule",
+			mcp.WithDescription("Set a game rule on the Realm via /gamerule and wait for the server to confirm the change. confirmed is true only if the server sent the new value in GameRulesChanged after the command."),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Game rule name (e.g. doDaylightCycle, randomTickSpeed)"),
+			),
+			mcp.WithString("value",
+				mcp.Required(),
+				mcp.Description("New value: true/false for boolean rules, an integer for numeric rules"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			rawName, err := req.RequireString("name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			rawValue, err := req.RequireString("value")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, value, err := parseGameRule(rawName, rawValue)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			sent := time.Now()
+			if err := sendCommand(state, fmt.Sprintf("gamerule %s %v", name, value)); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("command error: %v", err)), nil
+			}
+
+			// The server answers with GameRulesChanged, which updates the cache. The
+			// rule may already hold value, so only a change after the command counts.
+			confirmed := false
+			deadline := time.Now().Add(gameRuleConfirmTimeout)
+			for time.Now().Before(deadline) {
+				if state.GameRuleChangedSince(name, value, sent) {
+					confirmed = true
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return mcp.NewToolResultError("interrupted waiting for confirmation"), nil
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
+			return jsonResult(map[string]any{
+				"name":      name,
+				"value":     value,
+				"confirmed": confirmed,
+			})
+		},
+	)
+
+	// switch_realm
+	s.AddTool(
+		mcp.NewTool("switch_realm",
+			mcp.WithDescription("Point the proxy at a different Realm without restarting it. The Realm is looked up first, and if it cannot be found the current session is left alone. Otherwise the current session ends and the Minecraft client is disconnected; when the client reconnects to the proxy it joins the new Realm. Waits for the new session and reports its world name. If the new Realm cannot be joined, the proxy reverts to the previous Realm."),
+			mcp.WithString("invite_code",
+				mcp.Description("Invite code of the Realm to switch to"),
+			),
+			mcp.WithString("realm_name",
+				mcp.Description("Name of a Realm the account owns or has joined to switch to, instead of an invite code (case-insensitive)"),
+			),
+			mcp.WithNumber("wait_seconds",
+				mcp.Description("How long to wait for the client to reconnect and join the new Realm (default 60, max 300)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			target := realmTarget{
+				Name:       req.GetString("realm_name", ""),
+				InviteCode: req.GetString("invite_code", ""),
+			}
+			if target.Name == "" && target.InviteCode == "" {
+				return mcp.NewToolResultError("invite_code or realm_name is required"), nil
+			}
+			wait := time.Duration(req.GetInt("wait_seconds", int(defaultSwitchRealmWait/time.Second))) * time.Second
+			if wait < 0 || wait > maxSwitchRealmWait {
+				return mcp.NewToolResultError(fmt.Sprintf("wait_seconds must be between 0 and %d", int(maxSwitchRealmWait/time.Second))), nil
+			}
+
+			result, err := switchRealm(ctx, state, target, wait)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(result)
 		},
 	)
 
@@ -158,16 +455,359 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 		},
 	)
 
+	// set_movement_speed
+	s.AddTool(
+		mcp.NewTool("set_movement_speed",
+			mcp.WithDescription("Change the player's walking speed with the Speed or Slowness effect and wait for the server to report the new movement speed. Fly speed cannot be changed with vanilla commands. 'confirmed' is false if the speed did not change, e.g. because the server rejected /effect or the level was already applied."),
+			mcp.WithNumber("level",
+				mcp.Required(),
+				mcp.Description("Speed level from -5 to 5: positive applies Speed, negative applies Slowness, 0 removes both"),
+			),
+			mcp.WithNumber("seconds",
+				mcp.Description("Effect duration in seconds (default 600)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			level, err := req.RequireInt("level")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			cmds, err := speedEffectCommands(level, req.GetInt("seconds", defaultSpeedSeconds))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			confirmed, err := setMovementSpeed(ctx, state, cmds, movementConfirmTimeout)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("command error: %v", err)), nil
+			}
+			return jsonResult(map[string]any{
+				"level":     level,
+				"confirmed": confirmed,
+				"speed":     state.MovementSpeed(),
+			})
+		},
+	)
+
+	// select_hotbar_slot
+	s.AddTool(
+		mcp.NewTool("select_hotbar_slot",
+			mcp.WithDescription("Select a hotbar slot so its item is held, e.g. before using a tool or weapon. Returns the slot and the item now held."),
+			mcp.WithNumber("slot",
+				mcp.Required(),
+				mcp.Description("Hotbar slot, 0-8"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			slot, err := req.RequireInt("slot")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			held, err := selectHotbarSlot(state, slot)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(held)
+		},
+	)
+
+	// submit_form
+	s.AddTool(
+		mcp.NewTool("submit_form",
+			mcp.WithDescription("Answer the pending UI form (see get_pending_form). The response is JSON: true/false for a modal form, the button index for a menu form, or an array with one value per element for a custom form."),
+			mcp.WithString("response",
+				mcp.Description(`JSON response, e.g. true, 2 or ["Steve", 5, true]`),
+			),
+			mcp.WithBoolean("cancel",
+				mcp.Description("Close the form without answering instead (default false)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			form, ok := state.PendingForm()
+			if !ok {
+				return mcp.NewToolResultError("no form is pending"), nil
+			}
+			response := req.GetString("response", "")
+			cancel := req.GetBool("cancel", false)
+			if response == "" && !cancel {
+				return mcp.NewToolResultError("either response or cancel is required"), nil
+			}
+			if err := submitForm(state, form, response, cancel); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("submit error: %v", err)), nil
+			}
+			if cancel {
+				return mcp.NewToolResultText(fmt.Sprintf("closed form %d", form.ID)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("submitted form %d: %s", form.ID, response)), nil
+		},
+	)
+
+	// cancel_operation
+	s.AddTool(
+		mcp.NewTool("cancel_operation",
+			mcp.WithDescription("Cancel the running long operation (upload_structure, place_blocks or build_from_file). It stops cleanly before its next item; reports how far it got."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			progress, ok := state.CancelOperation()
+			if !ok {
+				return mcp.NewToolResultError("no operation is running"), nil
+			}
+			slog.Info("operation cancelled", "name", progress.Name, "current", progress.Current, "total", progress.Total)
+			return mcp.NewToolResultText(fmt.Sprintf("cancelled %s after %d/%d", progress.Name, progress.Current, progress.Total)), nil
+		},
+	)
+
+	// clear_violations
+	s.AddTool(
+		mcp.NewTool("clear_violations",
+			mcp.WithDescription("Clear the packet violation warning history, e.g. before retrying a build, so get_violations shows only new ones"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(fmt.Sprintf("cleared %d violations", state.ClearViolations())), nil
+		},
+	)
+
+	// set_anti_idle
+	s.AddTool(
+		mcp.NewTool("set_anti_idle",
+			mcp.WithDescription("Enable or disable the anti-idle action. When enabled and no tool has been called for the interval, the proxy briefly turns the player's head to keep Realms from kicking the idle player. Off by default; never moves the player or sends chat."),
+			mcp.WithBoolean("enabled",
+				mcp.Required(),
+				mcp.Description("Whether to enable the anti-idle action"),
+			),
+			mcp.WithNumber("interval_seconds",
+				mcp.Description(fmt.Sprintf("Idle time before signalling activity (default %d, minimum %d)", int(DefaultAntiIdleInterval.Seconds()), int(minAntiIdleInterval.Seconds()))),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			enabled, err := req.RequireBool("enabled")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			interval := time.Duration(req.GetFloat("interval_seconds", DefaultAntiIdleInterval.Seconds()) * float64(time.Second))
+			if interval < minAntiIdleInterval {
+				return mcp.NewToolResultError(fmt.Sprintf("interval_seconds must be at least %d", int(minAntiIdleInterval.Seconds()))), nil
+			}
+			state.SetAntiIdle(enabled, interval)
+			slog.Info("anti-idle toggled", "enabled", enabled, "interval", interval)
+			if !enabled {
+				return mcp.NewToolResultText("anti-idle disabled"), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("anti-idle enabled after %s of inactivity", interval)), nil
+		},
+	)
+
+	// set_packet_log_filter
+	s.AddTool(
+		mcp.NewTool("set_packet_log_filter",
+			mcp.WithDescription("Restrict verbose packet logging to specific packet types (e.g. UpdateBlock, Text) and/or a direction. Setting a non-empty filter also enables packet logging. Pass an empty list to log all building packets again."),
+			mcp.WithArray("types",
+				mcp.Description("Packet type names to log, as named by gophertunnel (e.g. [\"UpdateBlock\",\"Text\"]). Unknown names are rejected. Empty means all building packets."),
+				mcp.Items(map[string]any{"type": "string"}),
+			),
+			mcp.WithString("direction",
+				mcp.Description("Which direction to log: both (default), client (client→server), or server (server→client)"),
+				mcp.Enum("both", "client", "server"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			types := req.GetStringSlice("types", nil)
+			if unknown := unknownPacketNames(types); len(unknown) > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("unknown packet types: %s", strings.Join(unknown, ", "))), nil
+			}
+			var dir string
+			switch req.GetString("direction", "both") {
+			case "both":
+			case "client":
+				dir = packetDirClient
+			case "server":
+				dir = packetDirServer
+			default:
+				return mcp.NewToolResultError("direction must be both, client, or server"), nil
+			}
+			state.SetPacketLogFilter(types, dir)
+			if len(types) > 0 {
+				state.SetVerbosePacketLog(true)
+			}
+			slog.Info("packet log filter set", "types", types, "direction", dir)
+			types, dir = state.PacketLogFilter()
+			return jsonResult(map[string]any{
+				"types":     types,
+				"direction": dir,
+				"enabled":   state.VerbosePacketLog(),
+			})
+		},
+	)
+
+	// move_item
+	s.AddTool(
+		mcp.NewTool("move_item",
+			mcp.WithDescription("Move items between slots of the player's own inventory using an item stack request, waiting for the server to confirm. If the destination holds a different item, the two stacks are swapped. Use get_window to find slot indices."),
+			mcp.WithString("source_window",
+				mcp.Required(),
+				mcp.Description("Window of the source slot"),
+				mcp.Enum(inventoryWindowNames()...),
+			),
+			mcp.WithNumber("source_slot", mcp.Required(), mcp.Description("Source slot index (as reported by get_window)")),
+			mcp.WithString("destination_window",
+				mcp.Required(),
+				mcp.Description("Window of the destination slot"),
+				mcp.Enum(inventoryWindowNames()...),
+			),
+			mcp.WithNumber("destination_slot", mcp.Required(), mcp.Description("Destination slot index (as reported by get_window)")),
+			mcp.WithNumber("count",
+				mcp.Description("Number of items to move (default: the whole stack)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			srcWindow, err := req.RequireString("source_window")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			srcSlot, err := req.RequireInt("source_slot")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			dstWindow, err := req.RequireString("destination_window")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			dstSlot, err := req.RequireInt("destination_slot")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			count := req.GetInt("count", 0)
+
+			result, err := moveItem(ctx, state, srcWindow, srcSlot, dstWindow, dstSlot, count)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("move_item failed: %v", err)), nil
+			}
+			slog.Info("move_item", "action", result.Action, "item", result.Item, "count", result.Count, "from", result.Source, "to", result.Destination)
+			return jsonResult(result)
+		},
+	)
+
+	// drop_item
+	s.AddTool(
+		mcp.NewTool("drop_item",
+			mcp.WithDescription("Drop items from the hotbar/inventory onto the ground, waiting for the server to confirm. Identify the stack by slot, by item name, or both (the slot must then hold that item)."),
+			mcp.WithNumber("slot",
+				mcp.Description("Hotbar/inventory slot to drop from (0-35)"),
+			),
+			mcp.WithString("item",
+				mcp.Description("Item name to drop, e.g. 'minecraft:dirt' (first matching slot if no slot is given)"),
+			),
+			mcp.WithNumber("count",
+				mcp.Description("Number of items to drop (default: the whole stack)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			result, err := dropItem(ctx, state, req.GetInt("slot", -1), req.GetString("item", ""), req.GetInt("count", 0))
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("drop_item failed: %v", err)), nil
+			}
+			slog.Info("drop_item", "item", result.Item, "count", result.Count, "slot", result.Slot)
+			return jsonResult(result)
+		},
+	)
+
+	// craft
+	s.AddTool(
+		mcp.NewTool("craft",
+			mcp.WithDescription("Craft an item into the inventory. In creative mode pass 'item' to take it from the creative inventory. In survival pass 'recipe' (a recipe ID such as 'minecraft:stick' or a recipe network ID) after placing the ingredients in the 2x2 crafting grid with move_item. Reports the resulting item and slot."),
+			mcp.WithString("item",
+				mcp.Description("Creative: item name to create, e.g. 'minecraft:stone'"),
+			),
+			mcp.WithNumber("count",
+				mcp.Description("Creative: number of items to create (default 64, max 64)"),
+			),
+			mcp.WithString("recipe",
+				mcp.Description("Survival: recipe ID or recipe network ID to craft from the crafting grid"),
+			),
+			mcp.WithNumber("crafts",
+				mcp.Description("Survival: how many times to craft the recipe (default 1)"),
+			),
+			mcp.WithNumber("slot",
+				mcp.Description("Destination slot in the hotbar/inventory (0-35, default: first empty slot)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			item := req.GetString("item", "")
+			recipe := req.GetString("recipe", "")
+			slot := req.GetInt("slot", -1)
+
+			var result CraftResult
+			var err error
+			switch {
+			case item != "" && recipe != "":
+				return mcp.NewToolResultError("pass either item or recipe, not both"), nil
+			case item != "":
+				result, err = craftCreative(ctx, state, item, req.GetInt("count", maxCreativeCraftCount), slot)
+			case recipe != "":
+				result, err = craftRecipe(ctx, state, recipe, req.GetInt("crafts", 1), slot)
+			default:
+				return mcp.NewToolResultError("either item or recipe is required"), nil
+			}
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("craft failed: %v", err)), nil
+			}
+			slog.Info("craft", "method", result.Method, "item", result.Item, "count", result.Count, "slot", result.Slot)
+			return jsonResult(result)
+		},
+	)
+
 	// place_blocks
 	s.AddTool(
 		mcp.NewTool("place_blocks",
-			mcp.WithDescription("Place blocks in the world by sending the full client placement packet sequence. Requires creative mode or the blocks in inventory. Each entry specifies coordinates and a block name."),
+			mcp.WithDescription("Place blocks in the world by sending the full client placement packet sequence. Requires creative mode or the blocks in inventory. Each entry specifies coordinates and a block name. Blocks beyond reach are placed after teleporting above them, or rejected if on_out_of_reach is 'error'. Each placement waits for the server's block update; placements the server reverts are retried."),
 			mcp.WithString("blocks",
 				mcp.Required(),
-				mcp.Description(`JSON array of block placements, e.g. [{"x":0,"y":64,"z":0,"block_name":"minecraft:stone"}]`),
+				mcp.Description(`JSON array of block placements, e.g. [{"x":0,"y":64,"z":0,"block_name":"minecraft:stone"}]. An optional "against_face" (down, up, north, south, west, east) names the side of the target holding the neighbor to place against, e.g. "north" for a torch on a wall north of it; by default a solid neighbor is chosen from the block cache, falling back to the block below.`),
 			),
 			mcp.WithNumber("delay_ms",
-				mcp.Description("Delay in milliseconds between placements (default 100)"),
+				mcp.Description("Fixed delay in milliseconds between placements. By default the delay adapts to how quickly the server confirms placements, starting at 100"),
+			),
+			mcp.WithNumber("reach",
+				mcp.Description("Maximum distance in blocks from the player's eyes to a target block (default 5)"),
+			),
+			mcp.WithString("on_out_of_reach",
+				mcp.Description("What to do when a block is beyond reach: teleport (default) or error"),
+				mcp.Enum(outOfReachTeleport, outOfReachError),
+			),
+			mcp.WithNumber("move_delay_ms",
+				mcp.Description("Delay in milliseconds after teleporting before placing (default 250)"),
+			),
+			mcp.WithNumber("attempts",
+				mcp.Description(fmt.Sprintf("Placement attempts per block, with backoff between them (default %d)", DefaultPlaceAttempts)),
+			),
+			mcp.WithBoolean("continue_on_error",
+				mcp.Description("Record blocks that still fail after all attempts and continue with the rest, instead of aborting the batch (default false)"),
+			),
+			mcp.WithString("mode",
+				mcp.Description("replace (default) places over existing blocks; keep skips positions the block cache shows are not air, counting them as skipped"),
+				mcp.Enum(placeModeReplace, placeModeKeep),
+			),
+			mcp.WithString("unknown_blocks",
+				mcp.Description("In keep mode, what to do where the block cache does not know the block: place (default) or skip"),
+				mcp.Enum(unknownBlocksPlace, unknownBlocksSkip),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -178,47 +818,217 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
-			delayMs := req.GetInt("delay_ms", 100)
-			delay := time.Duration(delayMs) * time.Millisecond
-
-			var blocks []struct {
-				X         int    `json:"x"`
-				Y         int    `json:"y"`
-				Z         int    `json:"z"`
-				BlockName string `json:"block_name"`
+			opts, err := placeOptionsFromRequest(req, false)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
+
+			var blocks []blockPlacement
 			if err := json.Unmarshal([]byte(blocksJSON), &blocks); err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid blocks JSON: %v", err)), nil
 			}
 			if len(blocks) == 0 {
 				return mcp.NewToolResultError("blocks array is empty"), nil
 			}
+			for i, b := range blocks {
+				if err := validatePlaceAgainst(b.AgainstFace); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("block %d: %v", i, err)), nil
+				}
+			}
 
 			conn := state.ServerConn()
 			if conn == nil {
 				return mcp.NewToolResultError("server connection not available"), nil
 			}
+			result, err := placeBlocks(ctx, state, conn, "place_blocks", blocks, opts)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(result)
+		},
+	)
 
-			placed := 0
-			for i, b := range blocks {
-				select {
-				case <-ctx.Done():
-					return mcp.NewToolResultText(fmt.Sprintf("interrupted after %d/%d blocks", placed, len(blocks))), nil
-				default:
+	// build_from_file
+	s.AddTool(
+		mcp.NewTool("build_from_file",
+			mcp.WithDescription("Build a structure from a file with place_blocks' machinery: blocks are placed bottom layer first at the origin, teleporting into reach, with adaptive pacing and the hotbar set up per block. Progress is reported by get_operation_progress and cancel_operation stops it. Supports CSV files with rows of x,y,z,block_name relative to the origin, and Bedrock .mcstructure files (air and block states are left out). Returns a summary with counts and the failed blocks."),
+			mcp.WithString("path", mcp.Required(), mcp.Description("Build file, e.g. house.csv or house.mcstructure")),
+			mcp.WithNumber("x", mcp.Required(), mcp.Description("Origin X coordinate")),
+			mcp.WithNumber("y", mcp.Required(), mcp.Description("Origin Y coordinate")),
+			mcp.WithNumber("z", mcp.Required(), mcp.Description("Origin Z coordinate")),
+			mcp.WithNumber("rotation",
+				mcp.Description("Clockwise rotation seen from above, in degrees: 0 (default), 90, 180 or 270. Block states such as stair facing are not rotated"),
+			),
+			mcp.WithNumber("delay_ms",
+				mcp.Description("Fixed delay in milliseconds between placements. By default the delay adapts to how quickly the server confirms placements"),
+			),
+			mcp.WithBoolean("continue_on_error",
+				mcp.Description("Record blocks that fail and continue with the rest (default true)"),
+			),
+			mcp.WithString("mode",
+				mcp.Description("replace (default) places over existing blocks; keep skips positions the block cache shows are not air"),
+				mcp.Enum(placeModeReplace, placeModeKeep),
+			),
+			mcp.WithString("preset",
+				mcp.Description("Build preset saved with save_build_preset whose settings apply where this call does not set them"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			req, err := withBuildPreset(state, req)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			path, err := req.RequireString("path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			var origin BlockCoord
+			for _, c := range []struct {
+				name string
+				v    *int
+			}{{"x", &origin.X}, {"y", &origin.Y}, {"z", &origin.Z}} {
+				if *c.v, err = req.RequireInt(c.name); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
 				}
+			}
+			rotation := req.GetInt("rotation", 0)
+			opts, err := placeOptionsFromRequest(req, true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
 
-				if err := placeBlock(conn, state, int32(b.X), int32(b.Y), int32(b.Z), b.BlockName); err != nil {
-					slog.Warn("place_blocks: placement failed", "index", i, "block", b.BlockName, "error", err)
-					return mcp.NewToolResultError(fmt.Sprintf("failed at block %d (%s at %d,%d,%d): %v", i, b.BlockName, b.X, b.Y, b.Z, err)), nil
-				}
-				placed++
+			relative, err := loadBuildFile(path)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("reading %s: %v", path, err)), nil
+			}
+			blocks, err := layoutBuild(relative, origin, rotation)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			conn := state.ServerConn()
+			if conn == nil {
+				return mcp.NewToolResultError("server connection not available"), nil
+			}
+			start := time.Now()
+			result, err := placeBlocks(ctx, state, conn, "build_from_file", blocks, opts)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(buildSummary(path, origin, rotation, len(blocks), result, time.Since(start)))
+		},
+	)
+
+	// save_build_preset
+	s.AddTool(
+		mcp.NewTool("save_build_preset",
+			mcp.WithDescription("Save named build settings for build_from_file's preset argument, replacing any preset of the same name. Settings are validated when saved. Presets last for the session, or are saved to disk when the bridge runs with -build-presets-file."),
+			mcp.WithString("name", mcp.Required(), mcp.Description("Preset name")),
+			mcp.WithString("settings",
+				mcp.Required(),
+				mcp.Description(`JSON object of settings, any of reach, delay_ms, move_delay_ms, attempts, continue_on_error, mode, unknown_blocks, on_out_of_reach and rotation, e.g. {"reach":4.5,"delay_ms":150,"mode":"keep","rotation":90}`),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name, err := req.RequireString("name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			settings, err := req.RequireString("settings")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			p, err := parseBuildPreset(name, settings)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if err := state.SetBuildPreset(p); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("preset not saved: %v", err)), nil
+			}
+			return jsonResult(p)
+		},
+	)
 
-				if delay > 0 && i < len(blocks)-1 {
-					time.Sleep(delay)
+	// load_area
+	s.AddTool(
+		mcp.NewTool("load_area",
+			mcp.WithDescription(fmt.Sprintf("Request the terrain of an area from the server with SubChunkRequest and decode it into the block cache, without moving the player, so find_safe_position, get_hazards and check_block_placeable work there. Covers whole chunk columns, at most %d per call; loaded terrain stays cached until the next load_area. The server only sends terrain it has loaded, usually within its view distance of the player, so distant sub-chunks are reported as not_found.", maxLoadAreaChunks)),
+			mcp.WithNumber("x1", mcp.Required(), mcp.Description("X coordinate of one corner")),
+			mcp.WithNumber("z1", mcp.Required(), mcp.Description("Z coordinate of one corner")),
+			mcp.WithNumber("x2", mcp.Required(), mcp.Description("X coordinate of the opposite corner")),
+			mcp.WithNumber("z2", mcp.Required(), mcp.Description("Z coordinate of the opposite corner")),
+			mcp.WithNumber("timeout_seconds",
+				mcp.Description(fmt.Sprintf("How long to wait for the server's responses (default %d, max %d)", int(defaultLoadAreaTimeout.Seconds()), int(maxLoadAreaTimeout.Seconds()))),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			var corners [4]int32
+			for i, name := range []string{"x1", "z1", "x2", "z2"} {
+				v, err := req.RequireInt(name)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
 				}
+				corners[i] = int32(v)
+			}
+			timeout := time.Duration(req.GetInt("timeout_seconds", int(defaultLoadAreaTimeout.Seconds()))) * time.Second
+			if timeout <= 0 || timeout > maxLoadAreaTimeout {
+				return mcp.NewToolResultError(fmt.Sprintf("timeout_seconds must be between 1 and %d", int(maxLoadAreaTimeout.Seconds()))), nil
+			}
+			result, err := loadArea(ctx, state, corners[0], corners[1], corners[2], corners[3], timeout)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
+			return jsonResult(result)
+		},
+	)
 
-			return mcp.NewToolResultText(fmt.Sprintf("placed %d blocks", placed)), nil
+	// dig_column
+	s.AddTool(
+		mcp.NewTool("dig_column",
+			mcp.WithDescription("Dig a shaft or tunnel: repeatedly break the block below (or the two blocks in front) and step into the space. Stops on bedrock, nearby lava (from the block cache), or when the depth is reached."),
+			mcp.WithNumber("depth",
+				mcp.Required(),
+				mcp.Description("Number of steps to dig"),
+			),
+			mcp.WithString("direction",
+				mcp.Description("Direction to dig: down, north, south, east, or west (default down)"),
+				mcp.Enum("down", "north", "south", "east", "west"),
+			),
+			mcp.WithNumber("delay_ms",
+				mcp.Description("Delay in milliseconds between steps (default 250)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			depth, err := req.RequireInt("depth")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if depth <= 0 {
+				return mcp.NewToolResultError("depth must be positive"), nil
+			}
+			dir := req.GetString("direction", "down")
+			if _, ok := digDirections[dir]; !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid direction %q", dir)), nil
+			}
+			delay := time.Duration(req.GetInt("delay_ms", 250)) * time.Millisecond
+
+			conn := state.ServerConn()
+			if conn == nil {
+				return mcp.NewToolResultError("server connection not available"), nil
+			}
+
+			result := digColumn(ctx, conn, state, dir, depth, delay)
+			slog.Info("dig_column finished", "direction", dir, "steps", result.Steps, "mined", result.BlocksMined, "stopped_by", result.StoppedBy)
+			return jsonResult(result)
 		},
 	)
 
@@ -233,6 +1043,9 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 			mcp.WithNumber("delay_ms",
 				mcp.Description("Delay in milliseconds between chunk sends (default 50)"),
 			),
+			mcp.WithNumber("max_reconnects",
+				mcp.Description("How many times to wait for the Realm session to reconnect and resume after a dropped connection (default 3, 0 to abort on the first drop)"),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			if err := requireConnected(state); err != nil {
@@ -244,6 +1057,7 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 			}
 			delayMs := req.GetInt("delay_ms", 50)
 			delay := time.Duration(delayMs) * time.Millisecond
+			maxReconnects := req.GetInt("max_reconnects", 3)
 
 			// Read chunks file
 			chunks, err := readChunksFile(filePath)
@@ -254,7 +1068,7 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 				return mcp.NewToolResultError("no chunks found in file"), nil
 			}
 
-			name, xuid := state.Identity()
+			name, xuid := state.ChatIdentity()
 			conn := state.ServerConn()
 			if conn == nil {
 				return mcp.NewToolResultError("server connection not available"), nil
@@ -262,7 +1076,12 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 
 			slog.Info("uploading structure", "file", filePath, "chunks", len(chunks), "delay_ms", delayMs)
 
-			for i, chunk := range chunks {
+			opID, ctx := state.StartOperation(ctx, "upload_structure", len(chunks))
+			defer state.EndOperation(opID)
+
+			reconnects := 0
+			for i := 0; i < len(chunks); {
+				state.UpdateOperation(opID, i, 0)
 				// Check for cancellation between sends
 				select {
 				case <-ctx.Done():
@@ -274,21 +1093,93 @@ func registerActionTools(s *server.MCPServer, state *GameState) {
 					TextType:   packet.TextTypeChat,
 					SourceName: name,
 					XUID:       xuid,
-					Message:    fmt.Sprintf("!chunk %s", chunk),
+					Message:    fmt.Sprintf("!chunk %s", chunks[i]),
 				}); err != nil {
-					return mcp.NewToolResultError(fmt.Sprintf("send error at chunk %d: %v", i+1, err)), nil
+					if reconnects >= maxReconnects {
+						return mcp.NewToolResultError(fmt.Sprintf("send error at chunk %d after %d reconnects: %v", i+1, reconnects, err)), nil
+					}
+					reconnects++
+					wait := reconnectWait(reconnects)
+					slog.Warn("upload_structure: connection lost, waiting for realm session to reconnect",
+						"chunk", i+1, "total", len(chunks), "attempt", reconnects, "max", maxReconnects, "wait", wait, "error", err)
+					state.SetOperationReconnects(opID, reconnects)
+					conn, err = waitForReconnect(ctx, state, conn, wait)
+					if err != nil {
+						return mcp.NewToolResultError(fmt.Sprintf("connection lost at chunk %d/%d: %v", i+1, len(chunks), err)), nil
+					}
+					name, xuid = state.ChatIdentity()
+					slog.Info("upload_structure: reconnected, resuming", "chunk", i+1, "total", len(chunks), "attempt", reconnects)
+					continue
 				}
+				i++
 
 				if delay > 0 {
-					time.Sleep(delay)
+					select {
+					case <-time.After(delay):
+					case <-ctx.Done():
+					}
 				}
 			}
 
+			if reconnects > 0 {
+				return mcp.NewToolResultText(fmt.Sprintf("uploaded %d chunks from %s (%d reconnects)", len(chunks), filePath, reconnects)), nil
+			}
 			return mcp.NewToolResultText(fmt.Sprintf("uploaded %d chunks from %s", len(chunks), filePath)), nil
 		},
 	)
 }
 
+// reconnectWait returns how long to wait for the session to come back on the given
+// reconnect attempt: 15s, 30s, 60s, then capped at 2 minutes.
+func reconnectWait(attempt int) time.Duration {
+	wait := 15 * time.Second << (attempt - 1)
+	if attempt > 4 || wait > 2*time.Minute {
+		return 2 * time.Minute
+	}
+	return wait
+}
+
+// waitForReconnect waits up to timeout for the proxy to establish a new Realm session
+// (the client reconnecting triggers a new dial) and returns the new server connection.
+func waitForReconnect(ctx context.Context, state *GameState, old *minecraft.Conn, timeout time.Duration) (*minecraft.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if conn := state.ServerConn(); conn != nil && conn != old && state.Status() == StatusConnected {
+			return conn, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return nil, fmt.Errorf("realm session did not reconnect within %s", timeout)
+}
+
+// gameRuleConfirmTimeout is how long set_gamerule waits for GameRulesChanged.
+const gameRuleConfirmTimeout = 2 * time.Second
+
+// sendCommand sends a command (without leading slash) as a chat message, the same
+// way the command tool does.
+func sendCommand(state *GameState, cmd string) error {
+	return sendChat(state, "/"+strings.TrimPrefix(cmd, "/"))
+}
+
+// sendChat sends a chat message as the player.
+func sendChat(state *GameState, msg string) error {
+	name, xuid := state.ChatIdentity()
+	conn := state.ServerConn()
+	if conn == nil {
+		return fmt.Errorf("server connection not available")
+	}
+	return conn.WritePacket(&packet.Text{
+		TextType:   packet.TextTypeChat,
+		SourceName: name,
+		XUID:       xuid,
+		Message:    msg,
+	})
+}
+
 // readChunksFile reads a line-delimited chunks file, skipping empty lines.
 func readChunksFile(path string) ([]string, error) {
 	file, err := os.Open(path)
@@ -309,31 +1200,21 @@ func readChunksFile(path string) ([]string, error) {
 }
 
 // placeBlock sends the 4-packet block placement sequence to the server connection,
-// mimicking what the real client sends when a player places a block.
-func placeBlock(conn *minecraft.Conn, state *GameState, x, y, z int32, blockName string) error {
-	networkID, ok := state.ResolveItemNetworkID(blockName)
-	if !ok {
-		return fmt.Errorf("unknown block name %q (not in item registry)", blockName)
+// mimicking what the real client sends when a player places a block. The block is
+// placed against the neighbor in direction against, or one chosen from the block
+// cache when against is empty or "auto".
+func placeBlock(ctx context.Context, conn *minecraft.Conn, state *GameState, x, y, z int32, blockName, against, strategy string) error {
+	heldItem, hotBarSlot, err := preparePlacement(ctx, state, strategy, blockName)
+	if err != nil {
+		return err
 	}
 
 	entityID := state.EntityID()
 	posX, posY, posZ, _, _, _ := state.Position()
 
-	targetPos := protocol.BlockPos{x, y - 1, z} // block below — we "click on top"
-	newPos := protocol.BlockPos{x, y, z}         // where the block will appear
-
-	heldItem := protocol.ItemInstance{
-		StackNetworkID: 0,
-		Stack: protocol.ItemStack{
-			ItemType: protocol.ItemType{
-				NetworkID:     networkID,
-				MetadataValue: 0,
-			},
-			BlockRuntimeID: 0,
-			Count:          1,
-			HasNetworkID:   false,
-		},
-	}
+	newPos := protocol.BlockPos{x, y, z} // where the block will appear
+	support := choosePlacementSupport(state, newPos, against)
+	targetPos := addBlockPos(newPos, support.Offset) // the block we click on
 
 	// 1. PlayerAction(StartItemUseOn)
 	if err := conn.WritePacket(&packet.PlayerAction{
@@ -341,7 +1222,7 @@ func placeBlock(conn *minecraft.Conn, state *GameState, x, y, z int32, blockName
 		ActionType:      protocol.PlayerActionStartItemUseOn,
 		BlockPosition:   targetPos,
 		ResultPosition:  newPos,
-		BlockFace:       1, // Up
+		BlockFace:       support.Face,
 	}); err != nil {
 		return fmt.Errorf("StartItemUseOn: %w", err)
 	}
@@ -352,11 +1233,11 @@ func placeBlock(conn *minecraft.Conn, state *GameState, x, y, z int32, blockName
 			ActionType:     protocol.UseItemActionClickBlock,
 			TriggerType:    protocol.TriggerTypePlayerInput,
 			BlockPosition:  targetPos,
-			BlockFace:      1, // Up
-			HotBarSlot:     0,
+			BlockFace:      support.Face,
+			HotBarSlot:     int32(hotBarSlot),
 			HeldItem:       heldItem,
 			Position:       mgl32.Vec3{posX, posY, posZ},
-			ClickedPosition: mgl32.Vec3{0.5, 0.5, 0.5},
+			ClickedPosition: clickPosition(support.Face),
 			BlockRuntimeID: 0,
 			ClientPrediction: protocol.ClientPredictionSuccess,
 		},
@@ -371,7 +1252,7 @@ func placeBlock(conn *minecraft.Conn, state *GameState, x, y, z int32, blockName
 			TriggerType:    protocol.TriggerTypePlayerInput,
 			BlockPosition:  protocol.BlockPos{0, 0, 0},
 			BlockFace:      -1,
-			HotBarSlot:     0,
+			HotBarSlot:     int32(hotBarSlot),
 			HeldItem:       heldItem,
 			Position:       mgl32.Vec3{posX, posY, posZ},
 			ClickedPosition: mgl32.Vec3{0, 0, 0},
@@ -395,3 +1276,45 @@ func placeBlock(conn *minecraft.Conn, state *GameState, x, y, z int32, blockName
 
 	return nil
 }
+
+// breakBlock sends the block breaking sequence for the block at pos, mimicking what
+// the real client sends when a player in creative mode destroys a block.
+func breakBlock(conn *minecraft.Conn, state *GameState, pos protocol.BlockPos) error {
+	entityID := state.EntityID()
+	posX, posY, posZ, _, _, _ := state.Position()
+
+	// 1. PlayerAction(StartBreak)
+	if err := conn.WritePacket(&packet.PlayerAction{
+		EntityRuntimeID: entityID,
+		ActionType:      protocol.PlayerActionStartBreak,
+		BlockPosition:   pos,
+		BlockFace:       1, // Up
+	}); err != nil {
+		return fmt.Errorf("StartBreak: %w", err)
+	}
+
+	// 2. InventoryTransaction(BreakBlock)
+	if err := conn.WritePacket(&packet.InventoryTransaction{
+		TransactionData: &protocol.UseItemTransactionData{
+			ActionType:       protocol.UseItemActionBreakBlock,
+			TriggerType:      protocol.TriggerTypePlayerInput,
+			BlockPosition:    pos,
+			BlockFace:        1, // Up
+			Position:         mgl32.Vec3{posX, posY, posZ},
+			ClientPrediction: protocol.ClientPredictionSuccess,
+		},
+	}); err != nil {
+		return fmt.Errorf("BreakBlock: %w", err)
+	}
+
+	// 3. PlayerAction(StopBreak)
+	if err := conn.WritePacket(&packet.PlayerAction{
+		EntityRuntimeID: entityID,
+		ActionType:      protocol.PlayerActionStopBreak,
+		BlockPosition:   pos,
+	}); err != nil {
+		return fmt.Errorf("StopBreak: %w", err)
+	}
+
+	return nil
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 )
 
 func registerQueryTools(s *server.MCPServer, state *GameState) {
 	// get_status
 	s.AddTool(
 		mcp.NewTool("get_status",
-			mcp.WithDescription("Get the current proxy connection status, player name, and whether the realm is connected"),
+			mcp.WithDescription("Get the current proxy connection status, player name, whether the realm is connected, and whether the player has permission to run commands and build"),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			name, _ := state.Identity()
@@ -21,11 +25,61 @@ func registerQueryTools(s *server.MCPServer, state *GameState) {
 				"status":          state.Status(),
 				"player_name":     name,
 				"realm_connected": state.Status() == StatusConnected,
+				"packets":         state.RelayStats(),
+				"versions":        state.ProtocolVersions(),
+				"resource_packs":  state.ResourcePacks(),
+				"permissions":     state.Permissions(),
 			}
 			return jsonResult(result)
 		},
 	)
 
+	// get_proxy_info
+	s.AddTool(
+		mcp.NewTool("get_proxy_info",
+			mcp.WithDescription("Get this proxy's listen address, the Realm it relays to (name, masked invite code, resolved address and protocol), its uptime, and the last Transfer packet the server sent (and whether the proxy followed it). Useful to tell several proxy instances apart."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonResult(state.ProxyInfo())
+		},
+	)
+
+	// get_snapshot
+	s.AddTool(
+		mcp.NewTool("get_snapshot",
+			mcp.WithDescription(fmt.Sprintf("Get a consistent snapshot of the whole game state in one call: status, identity, position, world info, inventory, players, the %d nearest entities and the last %d chat messages", maxSnapshotEntities, maxSnapshotChat)),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonResult(state.Snapshot())
+		},
+	)
+
+	// get_world_generation
+	s.AddTool(
+		mcp.NewTool("get_world_generation",
+			mcp.WithDescription("Get world generation settings from StartGame: the world seed (for biome prediction and structure locating; seed_known is false if the server hid it), whether generation is client-side, the world spawn position and the spawn radius"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.WorldGeneration())
+		},
+	)
+
+	// get_session_info
+	s.AddTool(
+		mcp.NewTool("get_session_info",
+			mcp.WithDescription("Get session metadata from the StartGame handshake: base game version, whether inventory is server-authoritative (item stack requests required), entity IDs, initial and world game modes, difficulty, and enabled experiments"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.SessionInfo())
+		},
+	)
+
 	// get_position
 	s.AddTool(
 		mcp.NewTool("get_position",
@@ -62,10 +116,68 @@ func registerQueryTools(s *server.MCPServer, state *GameState) {
 		},
 	)
 
+	// get_window
+	s.AddTool(
+		mcp.NewTool("get_window",
+			mcp.WithDescription("Get the slots of one part of the player's own inventory, including empty slots, with their slot indices"),
+			mcp.WithString("window",
+				mcp.Required(),
+				mcp.Description("Window to read: hotbar, inventory, cursor, armor, offhand, or crafting (the 2x2 grid)"),
+				mcp.Enum(inventoryWindowNames()...),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := req.RequireString("window")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			w, ok := inventoryWindows[name]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("unknown window %q", name)), nil
+			}
+			return jsonResult(map[string]any{
+				"window":    name,
+				"window_id": w.WindowID,
+				"slots":     state.WindowSlots(w.WindowID, w.FirstSlot, w.LastSlot),
+			})
+		},
+	)
+
+	// get_container_contents
+	s.AddTool(
+		mcp.NewTool("get_container_contents",
+			mcp.WithDescription("Get the slots of the container (chest, barrel, furnace, ...) the player has open. Container contents arrive shortly after the container opens, so this waits briefly for them; 'ready' is false if they had not arrived in time and the slots may be incomplete."),
+			mcp.WithNumber("wait_ms",
+				mcp.Description("How long to wait for the contents to arrive, in milliseconds (default 1000, max 10000)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			wait := time.Duration(req.GetInt("wait_ms", int(defaultContainerWait/time.Millisecond))) * time.Millisecond
+			wait = max(0, min(wait, maxContainerWait))
+			container, ok, err := waitForContainer(ctx, state, wait)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !ok {
+				return mcp.NewToolResultError("no container is open"), nil
+			}
+			return jsonResult(map[string]any{
+				"container": container,
+				"slots":     state.ContainerSlots(container.WindowID),
+			})
+		},
+	)
+
 	// get_players
 	s.AddTool(
 		mcp.NewTool("get_players",
-			mcp.WithDescription("Get the list of players currently online in the Realm"),
+			mcp.WithDescription("Get the list of players currently online in the Realm, with their platform (device OS) and skin ID when the server has sent them, and their entity runtime ID once the player has been seen nearby"),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			if err := requireConnected(state); err != nil {
@@ -76,12 +188,358 @@ func registerQueryTools(s *server.MCPServer, state *GameState) {
 		},
 	)
 
+	// get_entities
+	s.AddTool(
+		mcp.NewTool("get_entities",
+			mcp.WithDescription("Get nearby entities (mobs, players, items, projectiles) with their positions, velocities and health. Velocity is in blocks per tick and is zero until the server sends motion for the entity; health is omitted until the server reports it. Player entities include the player's XUID, platform and skin when known."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.Entities())
+		},
+	)
+
+	// get_block_entity
+	s.AddTool(
+		mcp.NewTool("get_block_entity",
+			mcp.WithDescription("Get the NBT data of a block entity (chest contents, sign text, ...) at a position in the current dimension, as last sent by the server"),
+			mcp.WithNumber("x", mcp.Required(), mcp.Description("X coordinate")),
+			mcp.WithNumber("y", mcp.Required(), mcp.Description("Y coordinate")),
+			mcp.WithNumber("z", mcp.Required(), mcp.Description("Z coordinate")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			x, err := req.RequireInt("x")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			y, err := req.RequireInt("y")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			z, err := req.RequireInt("z")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			be, ok := state.BlockEntity(protocol.BlockPos{int32(x), int32(y), int32(z)})
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("no block entity known at %d,%d,%d", x, y, z)), nil
+			}
+			return jsonResult(be)
+		},
+	)
+
+	// get_available_commands
+	s.AddTool(
+		mcp.NewTool("get_available_commands",
+			mcp.WithDescription("List the commands the Realm allows the player to run, as sent in AvailableCommands, with aliases and the parameters of each overload. Includes server-specific commands."),
+			mcp.WithString("prefix",
+				mcp.Description("Only return commands whose name starts with this prefix"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			prefix := strings.TrimPrefix(req.GetString("prefix", ""), "/")
+			commands := []CommandInfo{}
+			for _, c := range state.AvailableCommands() {
+				if strings.HasPrefix(c.Name, prefix) {
+					commands = append(commands, c)
+				}
+			}
+			return jsonResult(commands)
+		},
+	)
+
+	// get_operation_progress
+	s.AddTool(
+		mcp.NewTool("get_operation_progress",
+			mcp.WithDescription("Get the progress of the running long operation (upload_structure, place_blocks or build_from_file): items done, total, failures, reconnects to the Realm, elapsed time and rate. Reports status 'idle' when nothing is running."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			progress, ok := state.Operation()
+			if !ok {
+				return jsonResult(map[string]any{"status": "idle"})
+			}
+			return jsonResult(progress)
+		},
+	)
+
+	// list_build_presets
+	s.AddTool(
+		mcp.NewTool("list_build_presets",
+			mcp.WithDescription("List the build presets saved with save_build_preset, sorted by name, with their settings"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonResult(state.BuildPresets())
+		},
+	)
+
+	// find_safe_position
+	s.AddTool(
+		mcp.NewTool("find_safe_position",
+			mcp.WithDescription("Search the block cache near the given coordinates for the closest position where the player can stand safely: two air blocks above a solid block that is not lava, fire, magma, cactus or similar. Blocks the proxy has not seen count as unsafe, so this works best with -parse-chunks. Returns the feet block and the coordinates to pass to teleport, or found=false."),
+			mcp.WithNumber("x", mcp.Required(), mcp.Description("X coordinate")),
+			mcp.WithNumber("y", mcp.Required(), mcp.Description("Y coordinate of the feet")),
+			mcp.WithNumber("z", mcp.Required(), mcp.Description("Z coordinate")),
+			mcp.WithNumber("radius",
+				mcp.Description(fmt.Sprintf("Search radius in blocks (default %d, max %d)", defaultSafeSearchRadius, maxSafeSearchRadius)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			var coords [3]float64
+			for i, axis := range []string{"x", "y", "z"} {
+				v, err := req.RequireFloat(axis)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				coords[i] = v
+			}
+			radius := req.GetInt("radius", defaultSafeSearchRadius)
+			if radius < 0 || radius > maxSafeSearchRadius {
+				return mcp.NewToolResultError(fmt.Sprintf("radius must be between 0 and %d", maxSafeSearchRadius)), nil
+			}
+			target := protocol.BlockPos{
+				int32(math.Floor(coords[0])),
+				int32(math.Floor(coords[1])),
+				int32(math.Floor(coords[2])),
+			}
+			return jsonResult(findSafePosition(state, target, radius))
+		},
+	)
+
+	// get_hazards
+	s.AddTool(
+		mcp.NewTool("get_hazards",
+			mcp.WithDescription("List nearby threats, nearest first: hazardous blocks from the block cache (lava, fire, magma, cactus, ...), hostile mobs, and the bottom of the world when the player is close to it. Only blocks the proxy has seen are checked, so this works best with -parse-chunks."),
+			mcp.WithNumber("radius",
+				mcp.Description(fmt.Sprintf("Search radius in blocks around the player (default %d, max %d)", defaultHazardRadius, maxHazardRadius)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			radius := req.GetInt("radius", defaultHazardRadius)
+			if radius < 1 || radius > maxHazardRadius {
+				return mcp.NewToolResultError(fmt.Sprintf("radius must be between 1 and %d", maxHazardRadius)), nil
+			}
+			return jsonResult(findHazards(state, radius))
+		},
+	)
+
+	// get_biome
+	s.AddTool(
+		mcp.NewTool("get_biome",
+			mcp.WithDescription("Get the biome at the player's position (ID and name). Biomes are read from chunk data, so this requires -parse-chunks; the name is \"unknown\" until the player's chunk has been parsed."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(playerBiome(state))
+		},
+	)
+
+	// is_loaded
+	s.AddTool(
+		mcp.NewTool("is_loaded",
+			mcp.WithDescription("Check whether the proxy has block data for a coordinate in the current dimension: whether the block cache holds sub-chunks of its chunk column, when the first was received and when a block was last updated, and the player's distance to the chunk. Use this before relying on check_block_placeable, find_safe_position, get_hazards or place_blocks' keep mode at a position, which all read the block cache. Chunk data is only kept with -parse-chunks or after load_area."),
+			mcp.WithNumber("x", mcp.Required(), mcp.Description("X coordinate")),
+			mcp.WithNumber("y", mcp.Required(), mcp.Description("Y coordinate")),
+			mcp.WithNumber("z", mcp.Required(), mcp.Description("Z coordinate")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			x, err := req.RequireInt("x")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			y, err := req.RequireInt("y")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			z, err := req.RequireInt("z")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(isLoaded(state, protocol.BlockPos{int32(x), int32(y), int32(z)}, time.Now()))
+		},
+	)
+
+	// get_bearing
+	s.AddTool(
+		mcp.NewTool("get_bearing",
+			mcp.WithDescription("Get the horizontal distance and compass direction (N, NE, E, ...) from the player to a target, plus the yaw to face it. The target is the world spawn unless x and z or a waypoint are given."),
+			mcp.WithNumber("x", mcp.Description("Target X coordinate (requires z)")),
+			mcp.WithNumber("z", mcp.Description("Target Z coordinate (requires x)")),
+			mcp.WithString("waypoint", mcp.Description("Name of a waypoint saved with set_waypoint")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			args := req.GetArguments()
+			_, hasX := args["x"]
+			_, hasZ := args["z"]
+			var tx, tz float64
+			switch name := req.GetString("waypoint", ""); {
+			case name != "":
+				w, ok := state.Waypoint(name)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("unknown waypoint %q", name)), nil
+				}
+				tx, tz = float64(w.X), float64(w.Z)
+			case hasX && hasZ:
+				tx, tz = req.GetFloat("x", 0), req.GetFloat("z", 0)
+			case hasX || hasZ:
+				return mcp.NewToolResultError("x and z must be given together"), nil
+			default:
+				_, _, _, _, spawn := state.WorldInfo()
+				tx, tz = float64(spawn.X())+0.5, float64(spawn.Z())+0.5
+			}
+			x, _, z, _, _, _ := state.Position()
+			return jsonResult(bearingTo(float64(x), float64(z), tx, tz))
+		},
+	)
+
+	// check_block_placeable
+	s.AddTool(
+		mcp.NewTool("check_block_placeable",
+			mcp.WithDescription("Check whether place_blocks can place a block before trying: whether the name is in the item registry, the game mode, and whether the block is in the inventory or creative content. Lists the reasons placement would fail."),
+			mcp.WithString("block_name", mcp.Required(), mcp.Description("Block name, e.g. 'minecraft:stone'")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			block, err := req.RequireString("block_name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(checkBlockPlaceable(state, block))
+		},
+	)
+
+	// get_held_item
+	s.AddTool(
+		mcp.NewTool("get_held_item",
+			mcp.WithDescription("Get the selected hotbar slot (0-8) and the item held in it"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.HeldItem())
+		},
+	)
+
+	// get_movement_speed
+	s.AddTool(
+		mcp.NewTool("get_movement_speed",
+			mcp.WithDescription("Get the player's movement speed attribute (0.1 when walking without effects, including Speed/Slowness) and the default walk and fly speeds from the player's abilities"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.MovementSpeed())
+		},
+	)
+
+	// get_abilities
+	s.AddTool(
+		mcp.NewTool("get_abilities",
+			mcp.WithDescription("Get the player's movement abilities: whether they may fly, are flying, have noclip, are invulnerable or build instantly. Known is false until the server sends them. Flying follows the client starting and stopping flight."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.Abilities())
+		},
+	)
+
+	// get_pending_form
+	s.AddTool(
+		mcp.NewTool("get_pending_form",
+			mcp.WithDescription("Get the UI form (NPC menu, server settings, ...) the server is waiting for the player to answer, with its type ('modal', 'form' for button menus, 'custom_form'), title and full JSON. Answer it with submit_form."),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			form, ok := state.PendingForm()
+			if !ok {
+				return mcp.NewToolResultError("no form is pending"), nil
+			}
+			return jsonResult(form)
+		},
+	)
+
+	// get_raw_packet
+	s.AddTool(
+		mcp.NewTool("get_raw_packet",
+			mcp.WithDescription("Get the most recently intercepted packet of a type (gophertunnel name, e.g. 'UpdateBlock') as JSON, for protocol research. Packets are only captured while verbose packet logging is enabled."),
+			mcp.WithString("type", mcp.Required(), mcp.Description("Packet type name, e.g. 'LevelEvent'")),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name, err := req.RequireString("type")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			rp, ok := state.RawPacket(name)
+			if !ok {
+				if !state.VerbosePacketLog() {
+					return mcp.NewToolResultError("no packets captured: enable verbose packet logging first"), nil
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("no %s packet captured yet (captured: %s)", name, strings.Join(state.RawPacketTypes(), ", "))), nil
+			}
+			data, err := json.MarshalIndent(rp, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("serializing %s: %v", name, err)), nil
+			}
+			return mcp.NewToolResultText(string(data)), nil
+		},
+	)
+
+	// ping_pack
+	s.AddTool(
+		mcp.NewTool("ping_pack",
+			mcp.WithDescription("Check whether the Burnodd behavior pack is installed and responding by sending its chat probe and waiting for the reply. Reports the round-trip latency, or status 'no_response' if the pack did not answer in time."),
+			mcp.WithNumber("timeout_ms",
+				mcp.Description("How long to wait for the reply in milliseconds (default 5000)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			timeout := time.Duration(req.GetInt("timeout_ms", int(defaultPackPingTimeout/time.Millisecond))) * time.Millisecond
+			result, err := pingPack(ctx, state, timeout)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("ping_pack failed: %v", err)), nil
+			}
+			return jsonResult(result)
+		},
+	)
+
 	// get_chat_history
 	s.AddTool(
 		mcp.NewTool("get_chat_history",
-			mcp.WithDescription("Get recent chat messages from the Realm. Returns up to the last 100 messages."),
+			mcp.WithDescription("Get recent chat messages from the Realm. Returns up to the number of messages the proxy keeps (100 unless set with -chat-history)."),
 			mcp.WithNumber("count",
-				mcp.Description("Number of recent messages to return (default 20, max 100)"),
+				mcp.Description("Number of recent messages to return (default 20, max the chat history size)"),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -89,18 +547,133 @@ func registerQueryTools(s *server.MCPServer, state *GameState) {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 			count := req.GetInt("count", 20)
-			if count > maxChatHistory {
-				count = maxChatHistory
+			if size := state.ChatHistorySize(); count > size {
+				count = size
 			}
 			messages := state.ChatHistory(count)
 			return jsonResult(messages)
 		},
 	)
 
+	// wait_for
+	s.AddTool(
+		mcp.NewTool("wait_for",
+			mcp.WithDescription("Block until something happens in the game, or the timeout passes: a chat message containing some text, a player joining, the block at a position changing, or the player's health dropping below a threshold. Returns satisfied=false on timeout."),
+			mcp.WithString("condition",
+				mcp.Required(),
+				mcp.Description("What to wait for"),
+				mcp.Enum(WaitChatContains, WaitPlayerJoins, WaitBlockChangesAt, WaitHealthBelow),
+			),
+			mcp.WithString("text", mcp.Description("chat_contains: text to look for (case-insensitive)")),
+			mcp.WithString("player", mcp.Description("player_joins: only this player (default: anyone)")),
+			mcp.WithNumber("x", mcp.Description("block_changes_at: X coordinate")),
+			mcp.WithNumber("y", mcp.Description("block_changes_at: Y coordinate")),
+			mcp.WithNumber("z", mcp.Description("block_changes_at: Z coordinate")),
+			mcp.WithNumber("health", mcp.Description("health_below: threshold (full health is 20)")),
+			mcp.WithNumber("timeout_seconds",
+				mcp.Description(fmt.Sprintf("How long to wait (default %d, max %d)", int(defaultWaitTimeout.Seconds()), int(maxWaitTimeout.Seconds()))),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			cond := WaitCondition{
+				Type:   req.GetString("condition", ""),
+				Text:   req.GetString("text", ""),
+				Health: float32(req.GetFloat("health", 0)),
+			}
+			if cond.Type == WaitPlayerJoins {
+				cond.Text = req.GetString("player", "")
+			}
+			if cond.Type == WaitBlockChangesAt {
+				for i, axis := range []string{"x", "y", "z"} {
+					v, err := req.RequireInt(axis)
+					if err != nil {
+						return mcp.NewToolResultError(err.Error()), nil
+					}
+					cond.Position[i] = int32(v)
+				}
+			}
+			if err := cond.validate(); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			timeout := time.Duration(req.GetInt("timeout_seconds", int(defaultWaitTimeout.Seconds()))) * time.Second
+			if timeout <= 0 || timeout > maxWaitTimeout {
+				return mcp.NewToolResultError(fmt.Sprintf("timeout_seconds must be between 1 and %d", int(maxWaitTimeout.Seconds()))), nil
+			}
+			result, err := waitFor(ctx, state, cond, timeout)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(result)
+		},
+	)
+
+	// get_violations
+	s.AddTool(
+		mcp.NewTool("get_violations",
+			mcp.WithDescription(fmt.Sprintf("Get the last %d packet violation warnings, oldest first: the type, severity, offending packet ID and name, the context text, and when it happened. Direction C→S means the Minecraft client rejected a packet relayed from the Realm; S→C means the Realm rejected one. Use this to find out why builds or commands are being rejected.", maxViolations)),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return jsonResult(state.Violations())
+		},
+	)
+
+	// get_events
+	s.AddTool(
+		mcp.NewTool("get_events",
+			mcp.WithDescription("Get the timeline of notable events: joining the Realm, deaths, players joining and leaving, dimension changes and disconnects. Returns up to the last 200 events, oldest first; pass the last seen 'seq' as 'after' to get only newer events."),
+			mcp.WithNumber("after",
+				mcp.Description("Only return events with a seq greater than this (default 0)"),
+			),
+			mcp.WithNumber("count",
+				mcp.Description("Maximum number of most recent events to return (default 50)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			after := req.GetInt("after", 0)
+			if after < 0 {
+				return mcp.NewToolResultError("after must not be negative"), nil
+			}
+			return jsonResult(state.Events(uint64(after), req.GetInt("count", 50)))
+		},
+	)
+
+	// get_item_registry
+	s.AddTool(
+		mcp.NewTool("get_item_registry",
+			mcp.WithDescription("List the item names the connected Realm knows and their network IDs, sorted by name and paged. Use it to check whether a block or item name exists on this Realm's version, e.g. when placement fails with \"unknown item\"."),
+			mcp.WithString("filter",
+				mcp.Description("Only include names containing this text (case-insensitive)"),
+			),
+			mcp.WithNumber("offset",
+				mcp.Description("Number of matching entries to skip (default 0; use next_offset from the previous page)"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum entries to return (default %d, max %d)", defaultItemRegistryLimit, maxItemRegistryLimit)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			offset := req.GetInt("offset", 0)
+			if offset < 0 {
+				return mcp.NewToolResultError("offset must not be negative"), nil
+			}
+			limit := req.GetInt("limit", defaultItemRegistryLimit)
+			if limit < 1 || limit > maxItemRegistryLimit {
+				return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxItemRegistryLimit)), nil
+			}
+			return jsonResult(state.ItemRegistry(req.GetString("filter", ""), offset, limit))
+		},
+	)
+
 	// get_world_info
 	s.AddTool(
 		mcp.NewTool("get_world_info",
-			mcp.WithDescription("Get world information including name, time, game mode, health, and spawn position"),
+			mcp.WithDescription("Get world information including name, time, game mode, health, hunger, and spawn position"),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			if err := requireConnected(state); err != nil {
@@ -118,9 +691,25 @@ func registerQueryTools(s *server.MCPServer, state *GameState) {
 					"z": int(spawnPos.Z()),
 				},
 			}
+			if hunger, ok := state.Hunger(); ok {
+				result["hunger"] = hunger
+			}
 			return jsonResult(result)
 		},
 	)
+
+	// get_gamerules
+	s.AddTool(
+		mcp.NewTool("get_gamerules",
+			mcp.WithDescription("Get the Realm's game rules (e.g. doDaylightCycle, commandBlockOutput, randomTickSpeed) as sent by the server at join"),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := requireConnected(state); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return jsonResult(state.GameRules())
+		},
+	)
 }
 
 func requireConnected(state *GameState) error {
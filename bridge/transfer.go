@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"golang.org/x/oauth2"
+)
+
+// transferTip is shown to the client while the proxy follows a transfer.
+const transferTip = "Following transfer..."
+
+// TransferInfo describes the last Transfer packet sent by the server.
+type TransferInfo struct {
+	Address  string    `json:"address"`
+	Port     uint16    `json:"port"`
+	Time     time.Time `json:"time"`
+	Followed bool      `json:"followed"` // the proxy moved the client's session to the target
+}
+
+// Target returns the transfer destination as host:port.
+func (t TransferInfo) Target() string {
+	return net.JoinHostPort(t.Address, strconv.Itoa(int(t.Port)))
+}
+
+// SetFollowTransfers sets whether the proxy follows Transfer packets itself instead
+// of letting the client leave for the new server.
+func (gs *GameState) SetFollowTransfers(follow bool) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.followTransfers = follow
+}
+
+// FollowTransfers returns whether Transfer packets are followed by the proxy.
+func (gs *GameState) FollowTransfers() bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.followTransfers
+}
+
+// RecordTransfer records a Transfer packet from the server in the event log.
+func (gs *GameState) RecordTransfer(address string, port uint16) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	t := TransferInfo{Address: address, Port: port, Time: time.Now()}
+	gs.lastTransfer = &t
+	gs.recordEventLocked(EventTransferred, "transferred to "+t.Target())
+}
+
+// recordTransferFollowed marks the last transfer as followed once the session was
+// moved to its target.
+func (gs *GameState) recordTransferFollowed() {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if gs.lastTransfer == nil {
+		return
+	}
+	gs.lastTransfer.Followed = true
+	gs.recordEventLocked(EventTransferred, "followed transfer to "+gs.lastTransfer.Target())
+}
+
+// LastTransfer returns the last Transfer packet seen, or nil.
+func (gs *GameState) LastTransfer() *TransferInfo {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.lastTransfer == nil {
+		return nil
+	}
+	t := *gs.lastTransfer
+	return &t
+}
+
+// followTransfer dials and spawns the server a Transfer pointed at and moves the
+// client there, keeping it connected to the proxy. If the target cannot be reached,
+// the Transfer is passed on so the client can go there itself.
+func followTransfer(ctx context.Context, t TransferInfo, tokenSource oauth2.TokenSource, clientConn *minecraft.Conn, clientPackets <-chan packet.Packet, clientRuntimeID uint64, state *GameState) (*minecraft.Conn, error) {
+	_, _, _, _, _, dimension := state.Position()
+	state.SetConnections(nil, clientConn)
+	state.SetStatus(StatusReconnecting)
+	slog.Info("following transfer", "target", t.Target())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go keepClientAlive(ctx, clientConn, clientPackets, transferTip, cancel)
+
+	conn, err := dialServer(ctx, t.Target(), "transfer", tokenSource, state)
+	if err == nil {
+		conn, err = spawnWithRetry(ctx, conn, nil, 1, spawnAttemptTimeout)
+	}
+	cancel()
+	if err != nil {
+		_ = clientConn.WritePacket(&packet.Transfer{Address: t.Address, Port: t.Port})
+		return nil, fmt.Errorf("following transfer to %s: %w", t.Target(), err)
+	}
+	if err := resyncClient(conn, clientConn, clientRuntimeID, dimension, state); err != nil {
+		return nil, err
+	}
+	startRealmSession(conn, clientConn, state)
+	state.recordTransferFollowed()
+	return conn, nil
+}
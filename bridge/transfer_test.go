@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_Transfer(t *testing.T) {
+	gs := NewGameState()
+	interceptServerPacket(&packet.Transfer{Address: "play.example.net", Port: 19133}, gs)
+
+	last := gs.LastTransfer()
+	if last == nil || last.Target() != "play.example.net:19133" || last.Followed {
+		t.Fatalf("expected unfollowed transfer to play.example.net:19133, got %+v", last)
+	}
+	if events := gs.Events(0, 0); len(events) != 1 || events[0].Type != EventTransferred {
+		t.Errorf("expected one transferred event, got %+v", events)
+	}
+}
+
+func TestRecordTransferFollowed(t *testing.T) {
+	gs := NewGameState()
+	gs.recordTransferFollowed()
+	if gs.LastTransfer() != nil {
+		t.Fatal("expected no transfer to be recorded without a Transfer packet")
+	}
+
+	gs.SetFollowTransfers(true)
+	gs.RecordTransfer("10.0.0.5", 19132)
+	if gs.LastTransfer().Followed {
+		t.Error("expected a transfer not to count as followed before the session moved")
+	}
+	gs.recordTransferFollowed()
+	if last := gs.LastTransfer(); !last.Followed || last.Target() != "10.0.0.5:19132" {
+		t.Errorf("expected followed transfer to 10.0.0.5:19132, got %+v", last)
+	}
+	if events := gs.Events(0, 0); len(events) != 2 || events[1].Message != "followed transfer to 10.0.0.5:19132" {
+		t.Errorf("expected a followed transfer event, got %+v", events)
+	}
+}
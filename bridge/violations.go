@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// maxViolations is the number of packet violation warnings kept.
+const maxViolations = 100
+
+// Pools naming the packet a violation warning refers to. A warning from the client
+// is about a packet the server sent, and the other way round.
+var (
+	serverPacketPool = packet.NewServerPool()
+	clientPacketPool = packet.NewClientPool()
+)
+
+// Violation is a PacketViolationWarning seen on the connection. Direction is the
+// packet log direction of the warning itself: C→S when the client objected to a
+// packet relayed from the server, S→C when the server objected to one from us.
+type Violation struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	PacketID  int32     `json:"packet_id"`
+	Packet    string    `json:"packet,omitempty"` // name of the offending packet, if known
+	Context   string    `json:"context,omitempty"`
+}
+
+// violationTypeName names a violation type.
+func violationTypeName(t int32) string {
+	if t == packet.ViolationTypeMalformed {
+		return "malformed"
+	}
+	return fmt.Sprintf("unknown(%d)", t)
+}
+
+// violationSeverityName names a violation severity.
+func violationSeverityName(s int32) string {
+	switch s {
+	case packet.ViolationSeverityWarning:
+		return "warning"
+	case packet.ViolationSeverityFinalWarning:
+		return "final_warning"
+	case packet.ViolationSeverityTerminatingConnection:
+		return "terminating_connection"
+	}
+	return fmt.Sprintf("unknown(%d)", s)
+}
+
+// violationPacketName returns the name of the packet a warning sent in direction dir
+// refers to, or "" if the ID is unknown.
+func violationPacketName(id int32, dir string) string {
+	pool := clientPacketPool
+	if dir == packetDirClient {
+		pool = serverPacketPool
+	}
+	if newPacket, ok := pool[uint32(id)]; ok {
+		return packetTypeName(newPacket())
+	}
+	return ""
+}
+
+// RecordViolation logs a PacketViolationWarning and keeps it in the violation
+// history, dropping the oldest beyond maxViolations.
+func (gs *GameState) RecordViolation(p *packet.PacketViolationWarning, dir string) {
+	v := Violation{
+		Time:      time.Now(),
+		Direction: dir,
+		Type:      violationTypeName(p.Type),
+		Severity:  violationSeverityName(p.Severity),
+		PacketID:  p.PacketID,
+		Packet:    violationPacketName(p.PacketID, dir),
+		Context:   p.ViolationContext,
+	}
+	slog.Warn("packet violation warning", "dir", dir, "type", v.Type, "severity", v.Severity, "packet_id", v.PacketID, "packet", v.Packet, "context", v.Context)
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.violations = append(gs.violations, v)
+	if len(gs.violations) > maxViolations {
+		gs.violations = gs.violations[len(gs.violations)-maxViolations:]
+	}
+}
+
+// Violations returns the kept violation warnings, oldest first.
+func (gs *GameState) Violations() []Violation {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	result := make([]Violation, len(gs.violations))
+	copy(result, gs.violations)
+	return result
+}
+
+// ClearViolations empties the violation history and returns how many were dropped.
+func (gs *GameState) ClearViolations() int {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	n := len(gs.violations)
+	gs.violations = nil
+	return n
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestIntercept_PacketViolationWarning(t *testing.T) {
+	gs := NewGameState()
+	interceptClientPacket(&packet.PacketViolationWarning{
+		Type:             packet.ViolationTypeMalformed,
+		Severity:         packet.ViolationSeverityFinalWarning,
+		PacketID:         packet.IDUpdateBlock,
+		ViolationContext: "bad block",
+	}, gs)
+	interceptServerPacket(&packet.PacketViolationWarning{PacketID: packet.IDInventoryTransaction, Severity: 7}, gs)
+
+	v := gs.Violations()
+	if len(v) != 2 {
+		t.Fatalf("expected 2 violations, got %d", len(v))
+	}
+	tests := []struct {
+		got, expected Violation
+	}{
+		{v[0], Violation{Direction: packetDirClient, Type: "malformed", Severity: "final_warning", PacketID: packet.IDUpdateBlock, Packet: "UpdateBlock", Context: "bad block"}},
+		{v[1], Violation{Direction: packetDirServer, Type: "malformed", Severity: "unknown(7)", PacketID: packet.IDInventoryTransaction, Packet: "InventoryTransaction"}},
+	}
+	for i, tt := range tests {
+		if tt.got.Time.IsZero() {
+			t.Errorf("violation %d: expected a timestamp", i)
+		}
+		tt.got.Time = tt.expected.Time
+		if tt.got != tt.expected {
+			t.Errorf("violation %d: expected %+v, got %+v", i, tt.expected, tt.got)
+		}
+	}
+
+	if n := gs.ClearViolations(); n != 2 {
+		t.Errorf("expected 2 cleared, got %d", n)
+	}
+	if v := gs.Violations(); len(v) != 0 {
+		t.Errorf("expected no violations after clearing, got %d", len(v))
+	}
+}
+
+func TestRecordViolation_Bounded(t *testing.T) {
+	gs := NewGameState()
+	for i := range maxViolations + 5 {
+		gs.RecordViolation(&packet.PacketViolationWarning{PacketID: 9999, ViolationContext: fmt.Sprint(i)}, packetDirClient)
+	}
+	v := gs.Violations()
+	if len(v) != maxViolations {
+		t.Fatalf("expected %d violations, got %d", maxViolations, len(v))
+	}
+	if v[0].Context != "5" {
+		t.Errorf("expected oldest kept violation 5, got %q", v[0].Context)
+	}
+	if v[0].Packet != "" {
+		t.Errorf("expected no name for an unknown packet ID, got %q", v[0].Packet)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Conditions wait_for can wait on.
+const (
+	WaitChatContains   = "chat_contains"
+	WaitPlayerJoins    = "player_joins"
+	WaitBlockChangesAt = "block_changes_at"
+	WaitHealthBelow    = "health_below"
+)
+
+// Timeouts for wait_for.
+const (
+	defaultWaitTimeout = 60 * time.Second
+	maxWaitTimeout     = 10 * time.Minute
+)
+
+// WaitCondition is something a watcher waits for. Only the fields used by Type are
+// set: Text for chat_contains (a case-insensitive substring) and player_joins (a
+// player name, or empty for anyone), Position for block_changes_at, and Health for
+// health_below.
+type WaitCondition struct {
+	Type     string
+	Text     string
+	Position protocol.BlockPos
+	Health   float32
+}
+
+// validate checks that the condition is known and has the parameters it needs.
+func (c WaitCondition) validate() error {
+	switch c.Type {
+	case WaitChatContains:
+		if c.Text == "" {
+			return fmt.Errorf("%s needs the text to look for", WaitChatContains)
+		}
+	case WaitPlayerJoins, WaitBlockChangesAt:
+	case WaitHealthBelow:
+		if c.Health <= 0 {
+			return fmt.Errorf("%s needs a positive health threshold", WaitHealthBelow)
+		}
+	default:
+		return fmt.Errorf("unknown condition %q (want %s, %s, %s or %s)", c.Type,
+			WaitChatContains, WaitPlayerJoins, WaitBlockChangesAt, WaitHealthBelow)
+	}
+	return nil
+}
+
+// WaitResult reports how a wait ended. Detail describes what satisfied the condition.
+type WaitResult struct {
+	Condition  string  `json:"condition"`
+	Satisfied  bool    `json:"satisfied"`
+	Detail     string  `json:"detail,omitempty"`
+	WaitedSecs float64 `json:"waited_seconds"`
+}
+
+// watcher is a one-shot wait registered on the game state. ch receives the detail of
+// the event that satisfied cond.
+type watcher struct {
+	cond WaitCondition
+	ch   chan string
+}
+
+// AddWatcher registers a one-shot watcher for cond. The returned channel receives
+// one description of the event that satisfied it. A health_below watcher fires at
+// once if health is already below the threshold.
+func (gs *GameState) AddWatcher(cond WaitCondition) (uint64, <-chan string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.nextWatcherID++
+	w := &watcher{cond: cond, ch: make(chan string, 1)}
+	gs.watchers[gs.nextWatcherID] = w
+	if cond.Type == WaitHealthBelow && gs.health < cond.Health {
+		gs.notifyWatchersLocked(WaitHealthBelow, func(c WaitCondition) (string, bool) {
+			return fmt.Sprintf("health is %.1f", gs.health), gs.health < c.Health
+		})
+	}
+	return gs.nextWatcherID, w.ch
+}
+
+// RemoveWatcher drops a watcher, e.g. after a timeout.
+func (gs *GameState) RemoveWatcher(id uint64) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	delete(gs.watchers, id)
+}
+
+// notifyWatchersLocked fires and removes the watchers of condType for which match
+// reports true. Callers must hold gs.mu.
+func (gs *GameState) notifyWatchersLocked(condType string, match func(WaitCondition) (detail string, ok bool)) {
+	for id, w := range gs.watchers {
+		if w.cond.Type != condType {
+			continue
+		}
+		if detail, ok := match(w.cond); ok {
+			w.ch <- detail
+			delete(gs.watchers, id)
+		}
+	}
+}
+
+// NotifyChat checks chat_contains watchers against an incoming chat message.
+func (gs *GameState) NotifyChat(source, message string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	lower := strings.ToLower(message)
+	gs.notifyWatchersLocked(WaitChatContains, func(c WaitCondition) (string, bool) {
+		detail := message
+		if source != "" {
+			detail = source + ": " + message
+		}
+		return detail, strings.Contains(lower, strings.ToLower(c.Text))
+	})
+}
+
+// NotifyPlayerJoined checks player_joins watchers against a joining player.
+func (gs *GameState) NotifyPlayerJoined(name string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.notifyWatchersLocked(WaitPlayerJoins, func(c WaitCondition) (string, bool) {
+		return name + " joined", c.Text == "" || strings.EqualFold(c.Text, name)
+	})
+}
+
+// NotifyBlockChanged checks block_changes_at watchers against a block update.
+func (gs *GameState) NotifyBlockChanged(pos protocol.BlockPos, block string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.notifyWatchersLocked(WaitBlockChangesAt, func(c WaitCondition) (string, bool) {
+		return fmt.Sprintf("block at %d %d %d changed to %s", pos[0], pos[1], pos[2], block), c.Position == pos
+	})
+}
+
+// notifyHealthLocked checks health_below watchers against a new health value.
+// Callers must hold gs.mu.
+func (gs *GameState) notifyHealthLocked(h float32) {
+	gs.notifyWatchersLocked(WaitHealthBelow, func(c WaitCondition) (string, bool) {
+		return fmt.Sprintf("health dropped to %.1f", h), h < c.Health
+	})
+}
+
+// waitFor blocks until cond is satisfied, the timeout passes, or ctx is cancelled.
+// A timeout is not an error; the result reports Satisfied=false.
+func waitFor(ctx context.Context, state *GameState, cond WaitCondition, timeout time.Duration) (WaitResult, error) {
+	start := time.Now()
+	id, ch := state.AddWatcher(cond)
+	defer state.RemoveWatcher(id)
+
+	result := WaitResult{Condition: cond.Type}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case detail := <-ch:
+		result.Satisfied, result.Detail = true, detail
+	case <-timer.C:
+	case <-ctx.Done():
+		return WaitResult{}, ctx.Err()
+	}
+	result.WaitedSecs = time.Since(start).Seconds()
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// waitInBackground starts waitFor and returns a channel with its result.
+func waitInBackground(gs *GameState, cond WaitCondition, timeout time.Duration) <-chan WaitResult {
+	ch := make(chan WaitResult, 1)
+	registered := len(gs.watchers)
+	go func() {
+		result, _ := waitFor(context.Background(), gs, cond, timeout)
+		ch <- result
+	}()
+	// Wait until the watcher is registered so the packet below is not missed
+	for {
+		gs.mu.RLock()
+		n := len(gs.watchers)
+		gs.mu.RUnlock()
+		if n > registered {
+			return ch
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWaitFor_ChatContains(t *testing.T) {
+	gs := NewGameState()
+	done := waitInBackground(gs, WaitCondition{Type: WaitChatContains, Text: "ready"}, time.Second)
+	interceptServerPacket(&packet.Text{TextType: packet.TextTypeChat, SourceName: "Alex", Message: "not yet"}, gs)
+	interceptServerPacket(&packet.Text{TextType: packet.TextTypeChat, SourceName: "Alex", Message: "I'm READY"}, gs)
+
+	result := <-done
+	if !result.Satisfied || result.Detail != "Alex: I'm READY" {
+		t.Errorf("expected satisfied by Alex's message, got %+v", result)
+	}
+}
+
+func TestWaitFor_PlayerJoins(t *testing.T) {
+	gs := NewGameState()
+	done := waitInBackground(gs, WaitCondition{Type: WaitPlayerJoins, Text: "steve"}, time.Second)
+	interceptServerPacket(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{
+		{UUID: uuid.New(), XUID: "1", Username: "Alex"},
+	}}, gs)
+	interceptServerPacket(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{
+		{UUID: uuid.New(), XUID: "2", Username: "Steve"},
+	}}, gs)
+
+	result := <-done
+	if !result.Satisfied || result.Detail != "Steve joined" {
+		t.Errorf("expected satisfied by Steve joining, got %+v", result)
+	}
+}
+
+func TestWaitFor_BlockChangesAt(t *testing.T) {
+	gs := NewGameState()
+	gs.LearnBlock(9, "minecraft:gold_block")
+	done := waitInBackground(gs, WaitCondition{Type: WaitBlockChangesAt, Position: protocol.BlockPos{1, 64, 2}}, time.Second)
+	interceptServerPacket(&packet.UpdateBlock{Position: protocol.BlockPos{1, 65, 2}, NewBlockRuntimeID: 9}, gs)
+	interceptServerPacket(&packet.UpdateBlock{Position: protocol.BlockPos{1, 64, 2}, NewBlockRuntimeID: 9}, gs)
+
+	result := <-done
+	if !result.Satisfied || result.Detail != "block at 1 64 2 changed to minecraft:gold_block" {
+		t.Errorf("expected satisfied by the block change, got %+v", result)
+	}
+}
+
+func TestWaitFor_HealthBelow(t *testing.T) {
+	gs := NewGameState()
+	gs.SetHealth(20)
+	done := waitInBackground(gs, WaitCondition{Type: WaitHealthBelow, Health: 10}, time.Second)
+	gs.SetHealth(12)
+	gs.SetHealth(6)
+
+	result := <-done
+	if !result.Satisfied || result.Detail != "health dropped to 6.0" {
+		t.Errorf("expected satisfied at health 6, got %+v", result)
+	}
+
+	// Already below the threshold
+	result, err := waitFor(context.Background(), gs, WaitCondition{Type: WaitHealthBelow, Health: 10}, time.Second)
+	if err != nil || !result.Satisfied {
+		t.Errorf("expected immediate satisfaction, got %+v (err=%v)", result, err)
+	}
+}
+
+func TestWaitFor_Timeout(t *testing.T) {
+	gs := NewGameState()
+	result, err := waitFor(context.Background(), gs, WaitCondition{Type: WaitPlayerJoins}, 10*time.Millisecond)
+	if err != nil || result.Satisfied {
+		t.Errorf("expected unsatisfied result on timeout, got %+v (err=%v)", result, err)
+	}
+	if len(gs.watchers) != 0 {
+		t.Errorf("expected watcher removed after timeout, got %d", len(gs.watchers))
+	}
+}
+
+func TestWaitConditionValidate(t *testing.T) {
+	tests := []struct {
+		cond    WaitCondition
+		wantErr bool
+	}{
+		{WaitCondition{Type: WaitChatContains, Text: "hi"}, false},
+		{WaitCondition{Type: WaitChatContains}, true},
+		{WaitCondition{Type: WaitPlayerJoins}, false},
+		{WaitCondition{Type: WaitBlockChangesAt}, false},
+		{WaitCondition{Type: WaitHealthBelow, Health: 5}, false},
+		{WaitCondition{Type: WaitHealthBelow}, true},
+		{WaitCondition{Type: "rain_starts"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cond.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%+v: expected error=%v, got %v", tt.cond, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Waypoint is a named location registered with set_waypoint.
+type Waypoint struct {
+	Name      string  `json:"name"`
+	X         float32 `json:"x"`
+	Y         float32 `json:"y"`
+	Z         float32 `json:"z"`
+	Dimension int32   `json:"dimension"`
+}
+
+// unknownSpawnY is the Y coordinate the server sends when the spawn height is not known.
+const unknownSpawnY = 32767
+
+// goto keywords resolved from tracked spawn positions rather than waypoints.
+const (
+	gotoSpawn   = "spawn"
+	gotoBed     = "bed"
+	gotoRespawn = "respawn"
+)
+
+// loadWaypoints reads waypoints saved by saveWaypoints. A missing file yields no waypoints.
+func loadWaypoints(path string) (map[string]Waypoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Waypoint{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Waypoint
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	waypoints := make(map[string]Waypoint, len(list))
+	for _, w := range list {
+		waypoints[w.Name] = w
+	}
+	return waypoints, nil
+}
+
+// saveWaypoints writes waypoints to path as a JSON list sorted by name. The list is
+// written to a temporary file first and renamed over path, so an interrupted save
+// never leaves a truncated file behind.
+func saveWaypoints(path string, waypoints []Waypoint) error {
+	data, err := json.MarshalIndent(waypoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// validateWaypointName rejects names that would clash with goto keywords.
+func validateWaypointName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("waypoint name must not be empty")
+	}
+	switch strings.ToLower(name) {
+	case gotoSpawn, gotoBed, gotoRespawn:
+		return fmt.Errorf("%q is reserved for a goto keyword", name)
+	}
+	return nil
+}
+
+// gotoCommand returns the command that moves the player to target: a spawn keyword or
+// a waypoint name. Targets in another dimension are rejected since tp cannot cross
+// dimensions. When the target height is unknown, spreadplayers places the player on
+// the surface instead.
+func gotoCommand(state *GameState, target string) (string, error) {
+	_, _, _, _, _, dim := state.Position()
+
+	var pos protocol.BlockPos
+	var targetDim int32
+	switch strings.ToLower(target) {
+	case gotoSpawn:
+		_, _, _, _, pos = state.WorldInfo()
+		targetDim = 0
+	case gotoBed, gotoRespawn:
+		var ok bool
+		if pos, targetDim, ok = state.PlayerSpawn(); !ok {
+			return "", fmt.Errorf("no player spawn point received yet")
+		}
+	default:
+		w, ok := state.Waypoint(target)
+		if !ok {
+			names := state.WaypointNames()
+			if len(names) == 0 {
+				return "", fmt.Errorf("unknown waypoint %q (no waypoints set)", target)
+			}
+			return "", fmt.Errorf("unknown waypoint %q (known: %s)", target, strings.Join(names, ", "))
+		}
+		if w.Dimension != dim {
+			return "", fmt.Errorf("waypoint %q is in the %s, player is in the %s", target, dimensionName(w.Dimension), dimensionName(dim))
+		}
+		return fmt.Sprintf("tp @s %.2f %.2f %.2f", w.X, w.Y, w.Z), nil
+	}
+
+	if targetDim != dim {
+		return "", fmt.Errorf("%s is in the %s, player is in the %s", target, dimensionName(targetDim), dimensionName(dim))
+	}
+	if pos.Y() >= unknownSpawnY || pos.Y() == math.MinInt32 {
+		return fmt.Sprintf("spreadplayers %d %d 0 1 @s", pos.X(), pos.Z()), nil
+	}
+	return fmt.Sprintf("tp @s %.1f %d %.1f", float32(pos.X())+0.5, pos.Y(), float32(pos.Z())+0.5), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func TestGotoCommand(t *testing.T) {
+	gs := NewGameState()
+	gs.SetWorldSpawn(protocol.BlockPos{100, 70, -50})
+	if err := gs.SetWaypoint(Waypoint{Name: "base", X: 10, Y: 64, Z: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetWaypoint(Waypoint{Name: "fortress", X: 1, Y: 40, Z: 1, Dimension: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target  string
+		want    string
+		wantErr string
+	}{
+		{"spawn", "tp @s 100.5 70 -49.5", ""},
+		{"base", "tp @s 10.00 64.00 20.00", ""},
+		{"bed", "", "no player spawn point"},
+		{"fortress", "", "is in the nether"},
+		{"nowhere", "", "known: base, fortress"},
+	}
+	for _, tt := range tests {
+		got, err := gotoCommand(gs, tt.target)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("gotoCommand(%q): expected error containing %q, got %v", tt.target, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("gotoCommand(%q) = %q, %v; expected %q", tt.target, got, err, tt.want)
+		}
+	}
+}
+
+func TestGotoCommand_PlayerSpawnAndUnknownHeight(t *testing.T) {
+	gs := NewGameState()
+	interceptServerPacket(&packet.SetSpawnPosition{
+		SpawnType: packet.SpawnTypePlayer,
+		Position:  protocol.BlockPos{5, 65, 5},
+	}, gs)
+	if got, err := gotoCommand(gs, "bed"); err != nil || got != "tp @s 5.5 65 5.5" {
+		t.Errorf("expected tp to bed, got %q (err=%v)", got, err)
+	}
+
+	interceptServerPacket(&packet.SetSpawnPosition{
+		SpawnType: packet.SpawnTypeWorld,
+		Position:  protocol.BlockPos{0, unknownSpawnY, 0},
+	}, gs)
+	if got, err := gotoCommand(gs, "spawn"); err != nil || got != "spreadplayers 0 0 0 1 @s" {
+		t.Errorf("expected spreadplayers for unknown spawn height, got %q (err=%v)", got, err)
+	}
+}
+
+func TestValidateWaypointName(t *testing.T) {
+	for _, name := range []string{"", " ", "spawn", "Bed", "respawn"} {
+		if err := validateWaypointName(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+	if err := validateWaypointName("home"); err != nil {
+		t.Errorf("expected home to be valid, got %v", err)
+	}
+}
+
+func TestWaypointsPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "waypoints.json")
+
+	gs := NewGameState()
+	if err := gs.LoadWaypoints(path); err != nil {
+		t.Fatalf("loading a missing file should succeed, got %v", err)
+	}
+	if err := gs.SetWaypoint(Waypoint{Name: "home", X: 1, Y: 2, Z: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewGameState()
+	if err := reloaded.LoadWaypoints(path); err != nil {
+		t.Fatal(err)
+	}
+	w, ok := reloaded.Waypoint("home")
+	if !ok || w.X != 1 || w.Y != 2 || w.Z != 3 {
+		t.Errorf("expected home at (1,2,3) after reload, got %+v (ok=%v)", w, ok)
+	}
+}
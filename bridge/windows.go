@@ -0,0 +1,62 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// inventoryWindow locates a named part of the player's own inventory: the window ID
+// it is tracked under (from InventoryContent/InventorySlot), its slot range, and the
+// container ID used to address it in item stack requests.
+type inventoryWindow struct {
+	WindowID    byte
+	FirstSlot   int
+	LastSlot    int
+	ContainerID byte
+}
+
+// inventoryWindows maps get_window names to the well-known window IDs and slot ranges.
+// The UI window (124) holds the cursor and the 2x2 crafting grid of the inventory screen.
+var inventoryWindows = map[string]inventoryWindow{
+	"hotbar":    {protocol.WindowIDInventory, 0, 8, protocol.ContainerHotBar},
+	"inventory": {protocol.WindowIDInventory, 9, 35, protocol.ContainerInventory},
+	"cursor":    {protocol.WindowIDUI, 0, 0, protocol.ContainerCursor},
+	"armor":     {protocol.WindowIDArmour, 0, 3, protocol.ContainerArmor},
+	"offhand":   {protocol.WindowIDOffHand, 0, 0, protocol.ContainerOffhand},
+	"crafting":  {protocol.WindowIDUI, 28, 31, protocol.ContainerCraftingInput},
+}
+
+// inventoryWindowNames returns the supported window names in sorted order.
+func inventoryWindowNames() []string {
+	names := make([]string, 0, len(inventoryWindows))
+	for name := range inventoryWindows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// windowForContainer returns the inventory window addressed by a container ID.
+func windowForContainer(containerID byte) (inventoryWindow, bool) {
+	for _, w := range inventoryWindows {
+		if w.ContainerID == containerID {
+			return w, true
+		}
+	}
+	return inventoryWindow{}, false
+}
+
+// contains reports whether slot lies within the window's slot range.
+func (w inventoryWindow) contains(slot int) bool {
+	return slot >= w.FirstSlot && slot <= w.LastSlot
+}
+
+// playerInventoryWindow returns the window addressing a slot of the combined hotbar and
+// inventory (window 0, slots 0-35).
+func playerInventoryWindow(slot int) inventoryWindow {
+	if slot <= inventoryWindows["hotbar"].LastSlot {
+		return inventoryWindows["hotbar"]
+	}
+	return inventoryWindows["inventory"]
+}
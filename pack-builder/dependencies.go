@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	serverModule   = "@minecraft/server"
+	serverUIModule = "@minecraft/server-ui"
+)
+
+// serverUIVersions maps the major version of the @minecraft/server dependency
+// to the @minecraft/server-ui version released alongside it.
+var serverUIVersions = map[string]string{
+	"1": "1.3.0",
+	"2": "2.0.0",
+}
+
+// defaultServerUIVersion is used when the pack has no @minecraft/server dependency.
+const defaultServerUIVersion = "2.0.0"
+
+// serverUIImport matches static and dynamic imports of @minecraft/server-ui.
+// Only import statements are matched so that mentions in strings or comments
+// do not trigger a manifest change.
+var serverUIImport = regexp.MustCompile(`(?m)^\s*import\b[^;]*?\bfrom\s*["']@minecraft/server-ui["']|\bimport\s*\(\s*["']@minecraft/server-ui["']\s*\)`)
+
+// usesServerUI reports whether any .js file under packDir imports @minecraft/server-ui.
+func usesServerUI(packDir string) (bool, error) {
+	found := false
+	err := filepath.Walk(packDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if found || info.IsDir() || strings.ToLower(filepath.Ext(path)) != ".js" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		if serverUIImport.Match(data) {
+			found = true
+		}
+		return nil
+	})
+	return found, err
+}
+
+// majorVersion returns the major component of a "x.y.z[-suffix]" version string.
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// ensureServerUIDependency adds or corrects the @minecraft/server-ui dependency
+// so that it matches the @minecraft/server major version. It returns a
+// description of the change, or "" when the manifest was left untouched.
+func ensureServerUIDependency(manifest *Manifest) string {
+	want := defaultServerUIVersion
+	for _, dep := range manifest.Dependencies {
+		if dep.ModuleName == serverModule {
+			if v, ok := serverUIVersions[majorVersion(dep.Version)]; ok {
+				want = v
+			}
+		}
+	}
+
+	for i, dep := range manifest.Dependencies {
+		if dep.ModuleName != serverUIModule {
+			continue
+		}
+		if majorVersion(dep.Version) == majorVersion(want) {
+			return ""
+		}
+		manifest.Dependencies[i].Version = want
+		return fmt.Sprintf("changed %s dependency from %s to %s", serverUIModule, dep.Version, want)
+	}
+
+	manifest.Dependencies = append(manifest.Dependencies, Dependency{
+		ModuleName: serverUIModule,
+		Version:    want,
+	})
+	return fmt.Sprintf("added %s dependency version %s", serverUIModule, want)
+}
+
+// repairDependencies adds a missing @minecraft/server-ui dependency to the
+// manifest when the pack's scripts import it. Without it the pack fails to
+// load silently.
+func repairDependencies(packDir string) error {
+	uses, err := usesServerUI(packDir)
+	if err != nil {
+		return fmt.Errorf("scanning scripts: %w", err)
+	}
+	if !uses {
+		return nil
+	}
+
+	manifestPath := filepath.Join(packDir, "manifest.json")
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	change := ensureServerUIDependency(&manifest)
+	if change == "" {
+		return nil
+	}
+	fmt.Printf("Manifest: %s\n", change)
+
+	newData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
+	}
+
+	if err := os.WriteFile(manifestPath, append(newData, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	return nil
+}
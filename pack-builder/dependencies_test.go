@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestServerUIImport(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`import { ActionFormData } from "@minecraft/server-ui";`, true},
+		{"import {\n  ModalFormData,\n} from '@minecraft/server-ui';", true},
+		{`const ui = await import("@minecraft/server-ui");`, true},
+		{`import { world } from "@minecraft/server";`, false},
+		{`// TODO: use @minecraft/server-ui for menus`, false},
+		{`world.sendMessage("needs @minecraft/server-ui");`, false},
+	}
+	for _, tt := range tests {
+		if got := serverUIImport.MatchString(tt.src); got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.src, tt.want, got)
+		}
+	}
+}
+
+func TestEnsureServerUIDependency(t *testing.T) {
+	m := Manifest{Dependencies: []Dependency{{ModuleName: serverModule, Version: "1.16.0"}}}
+	if change := ensureServerUIDependency(&m); change == "" {
+		t.Fatal("expected dependency to be added")
+	}
+	if len(m.Dependencies) != 2 || m.Dependencies[1].Version != "1.3.0" {
+		t.Errorf("expected server-ui 1.3.0, got %+v", m.Dependencies)
+	}
+
+	if change := ensureServerUIDependency(&m); change != "" {
+		t.Errorf("expected no change, got %q", change)
+	}
+
+	m.Dependencies[0].Version = "2.0.0"
+	ensureServerUIDependency(&m)
+	if m.Dependencies[1].Version != "2.0.0" {
+		t.Errorf("expected server-ui 2.0.0, got %s", m.Dependencies[1].Version)
+	}
+}
@@ -49,6 +49,12 @@ func main() {
 	noBump := flag.Bool("no-bump", false, "Skip version bump")
 	flag.Parse()
 
+	// Add dependencies required by the scripts
+	if err := repairDependencies(*packDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error repairing manifest: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Bump version and get the new version
 	var version [3]int
 	var err error